@@ -51,6 +51,14 @@ func main() {
 				Help:    v.Usage,
 				List:    false,
 			})
+		case *cli.FloatFlag:
+			flags = append(flags, flag{
+				Flag:    v.Name,
+				Default: strconv.FormatFloat(v.Value, 'f', -1, 64),
+				Envs:    v.Sources.EnvKeys(),
+				Help:    v.Usage,
+				List:    false,
+			})
 		case *cli.BoolFlag:
 			flags = append(flags, flag{
 				Flag:    v.Name,
@@ -36,6 +36,22 @@ func main() {
 		))
 	}
 
+	f.WriteString("\n### Check Run Labels\n\n")
+	for _, row := range config.CheckRunLabels() {
+		f.WriteString(fmt.Sprintf(
+			"* %s\n",
+			row,
+		))
+	}
+
+	f.WriteString("\n### Check Suite Labels\n\n")
+	for _, row := range config.CheckSuiteLabels() {
+		f.WriteString(fmt.Sprintf(
+			"* %s\n",
+			row,
+		))
+	}
+
 	f.WriteString("\n### Hosted Runner Labels\n\n")
 	for _, row := range config.RunnerLabels() {
 		f.WriteString(fmt.Sprintf(
@@ -28,6 +28,8 @@ func main() {
 	cfg := config.Load().Target
 	cfg.WorkflowRuns.Labels = config.RunLabels()
 	cfg.WorkflowJobs.Labels = config.JobLabels()
+	cfg.CheckRuns.Labels = config.CheckRunLabels()
+	cfg.CheckRuns.SuiteLabels = config.CheckSuiteLabels()
 	cfg.Runners.Labels = config.RunnerLabels()
 
 	collectors = append(
@@ -65,6 +67,36 @@ func main() {
 		exporter.NewWorkflowJobCollector(slog.Default(), nil, nil, nil, nil, cfg).Metrics()...,
 	)
 
+	collectors = append(
+		collectors,
+		exporter.NewWorkflowSummaryCollector(slog.Default(), nil, nil, nil, nil, cfg).Metrics()...,
+	)
+
+	collectors = append(
+		collectors,
+		exporter.NewWorkflowHealthCollector(slog.Default(), nil, nil, nil, nil, cfg).Metrics()...,
+	)
+
+	collectors = append(
+		collectors,
+		exporter.NewWorkflowRegressionCollector(slog.Default(), nil, nil, nil, nil, cfg).Metrics()...,
+	)
+
+	collectors = append(
+		collectors,
+		exporter.NewCheckRunCollector(slog.Default(), nil, nil, nil, nil, cfg).Metrics()...,
+	)
+
+	collectors = append(
+		collectors,
+		exporter.NewPullRequestCollector(slog.Default(), nil, nil, nil, nil, cfg).Metrics()...,
+	)
+
+	collectors = append(
+		collectors,
+		exporter.NewDeploymentCollector(slog.Default(), nil, nil, nil, nil, cfg).Metrics()...,
+	)
+
 	metrics := make([]metric, 0)
 
 	metrics = append(metrics, metric{
@@ -79,6 +111,66 @@ func main() {
 		Labels: []string{"collector"},
 	})
 
+	metrics = append(metrics, metric{
+		Name:   "github_exporter_series_dropped_total",
+		Help:   "Total number of series dropped or folded because of the series limit per collector",
+		Labels: []string{"collector"},
+	})
+
+	metrics = append(metrics, metric{
+		Name:   "github_reconciled_records_total",
+		Help:   "Total number of unfinished records finished or deleted by the reconciler per type",
+		Labels: []string{"type"},
+	})
+
+	metrics = append(metrics, metric{
+		Name:   "github_webhook_received_total",
+		Help:   "Total number of received webhook deliveries per event",
+		Labels: []string{"event"},
+	})
+
+	metrics = append(metrics, metric{
+		Name:   "github_webhook_deliveries_total",
+		Help:   "Total number of handled webhook deliveries per event, owner and outcome",
+		Labels: []string{"event", "owner", "outcome"},
+	})
+
+	metrics = append(metrics, metric{
+		Name:   "github_webhook_delivery_duration_seconds",
+		Help:   "Histogram of latencies from receiving a webhook delivery until its outcome",
+		Labels: []string{"event", "outcome"},
+	})
+
+	metrics = append(metrics, metric{
+		Name:   "github_webhook_duplicate_deliveries_total",
+		Help:   "Total number of skipped duplicate webhook deliveries per event",
+		Labels: []string{"event"},
+	})
+
+	metrics = append(metrics, metric{
+		Name:   "github_webhook_secret_matches_total",
+		Help:   "Total number of webhook deliveries validated per index of the configured secrets",
+		Labels: []string{"index"},
+	})
+
+	metrics = append(metrics, metric{
+		Name:   "github_webhook_queue_depth",
+		Help:   "Number of webhook events waiting within the queue",
+		Labels: []string{},
+	})
+
+	metrics = append(metrics, metric{
+		Name:   "github_webhook_queue_dropped_total",
+		Help:   "Total number of dropped webhook events per event and reason",
+		Labels: []string{"event", "reason"},
+	})
+
+	metrics = append(metrics, metric{
+		Name:   "github_webhook_queue_processing_seconds",
+		Help:   "Histogram of latencies from receiving to processing webhook events",
+		Labels: []string{"event"},
+	})
+
 	for _, desc := range collectors {
 		m := metric{
 			Name:   reflect.ValueOf(desc).Elem().FieldByName("fqName").String(),
@@ -33,6 +33,89 @@ var (
 		},
 		[]string{"collector"},
 	)
+
+	seriesDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "exporter_series_dropped_total",
+			Help:      "Total number of series dropped or folded because of the series limit per collector.",
+		},
+		[]string{"collector"},
+	)
+
+	reconciledRecords = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reconciled_records_total",
+			Help:      "Total number of unfinished records finished or deleted by the reconciler per type.",
+		},
+		[]string{"type"},
+	)
+
+	webhookReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_received_total",
+			Help:      "Total number of received webhook deliveries per event.",
+		},
+		[]string{"event"},
+	)
+
+	webhookOutcomes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_deliveries_total",
+			Help:      "Total number of handled webhook deliveries per event, owner and outcome.",
+		},
+		[]string{"event", "owner", "outcome"},
+	)
+
+	webhookLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "webhook_delivery_duration_seconds",
+			Help:      "Histogram of latencies from receiving a webhook delivery until its outcome.",
+			Buckets:   []float64{0.001, 0.01, 0.1, 0.5, 1.0, 2.0, 5.0, 10.0, 30.0, 60.0},
+		},
+		[]string{"event", "outcome"},
+	)
+
+	webhookDuplicates = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_duplicate_deliveries_total",
+			Help:      "Total number of skipped duplicate webhook deliveries per event.",
+		},
+		[]string{"event"},
+	)
+
+	webhookSecretMatches = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_secret_matches_total",
+			Help:      "Total number of webhook deliveries validated per index of the configured secrets.",
+		},
+		[]string{"index"},
+	)
+
+	webhookQueueDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_queue_dropped_total",
+			Help:      "Total number of dropped webhook events per event and reason.",
+		},
+		[]string{"event", "reason"},
+	)
+
+	webhookQueueLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "webhook_queue_processing_seconds",
+			Help:      "Histogram of latencies from receiving to processing webhook events.",
+			Buckets:   []float64{0.001, 0.01, 0.1, 0.5, 1.0, 2.0, 5.0, 10.0, 30.0, 60.0},
+		},
+		[]string{"event"},
+	)
 )
 
 func init() {
@@ -45,6 +128,15 @@ func init() {
 
 	registry.MustRegister(requestDuration)
 	registry.MustRegister(requestFailures)
+	registry.MustRegister(seriesDropped)
+	registry.MustRegister(reconciledRecords)
+	registry.MustRegister(webhookReceived)
+	registry.MustRegister(webhookOutcomes)
+	registry.MustRegister(webhookLatency)
+	registry.MustRegister(webhookDuplicates)
+	registry.MustRegister(webhookSecretMatches)
+	registry.MustRegister(webhookQueueDropped)
+	registry.MustRegister(webhookQueueLatency)
 }
 
 type promLogger struct {
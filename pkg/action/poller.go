@@ -0,0 +1,367 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/exporter"
+	"github.com/promhippie/github_exporter/pkg/store"
+	"github.com/ryanuber/go-glob"
+)
+
+// poller backfills the store from the API for setups without webhooks.
+type poller struct {
+	cfg      *config.Config
+	db       store.Store
+	logger   *slog.Logger
+	client   *github.Client
+	stop     chan struct{}
+	once     sync.Once
+	lastJobs time.Time
+}
+
+// newPoller initializes a new poller based on the configuration.
+func newPoller(cfg *config.Config, db store.Store, logger *slog.Logger, client *github.Client) *poller {
+	return &poller{
+		cfg:    cfg,
+		db:     db,
+		logger: logger.With("component", "poller"),
+		client: client,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Run polls the API within the configured intervals until it gets closed.
+func (p *poller) Run() error {
+	var (
+		wg sync.WaitGroup
+	)
+
+	if p.cfg.Collector.WorkflowRuns && p.cfg.Target.WorkflowRuns.Poll {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			p.loop("workflow_run", p.cfg.Target.WorkflowRuns.PollInterval, p.pollRuns)
+		}()
+	}
+
+	if p.cfg.Collector.WorkflowJobs && p.cfg.Target.WorkflowJobs.Poll {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			p.loop("workflow_job", p.cfg.Target.WorkflowJobs.PollInterval, p.pollJobs)
+		}()
+	}
+
+	wg.Wait()
+	return nil
+}
+
+// Close stops the poller.
+func (p *poller) Close() {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+}
+
+func (p *poller) loop(kind string, interval time.Duration, handle func()) {
+	p.logger.Info("Starting poller",
+		"type", kind,
+		"interval", interval,
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		handle()
+
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll passes all repos to the handler and reports if all of them succeeded.
+func (p *poller) poll(kind string, handle func(*github.Repository) error) bool {
+	now := time.Now()
+	repos := p.repos()
+	success := true
+
+	for _, repo := range repos {
+		select {
+		case <-p.stop:
+			return false
+		default:
+		}
+
+		if err := handle(repo); err != nil {
+			success = false
+
+			p.logger.Error("Failed to poll repo",
+				"type", kind,
+				"name", repo.GetFullName(),
+				"err", err,
+			)
+
+			requestFailures.WithLabelValues("poller").Inc()
+		}
+	}
+
+	requestDuration.WithLabelValues("poller").Observe(time.Since(now).Seconds())
+
+	p.logger.Debug("Polled repos",
+		"type", kind,
+		"repos", len(repos),
+		"duration", time.Since(now),
+	)
+
+	return success
+}
+
+func (p *poller) pollRuns() {
+	p.poll("workflow_run", p.pollRepoRuns)
+}
+
+func (p *poller) pollRepoRuns(repo *github.Repository) error {
+	runs, err := p.listRuns(repo, p.cfg.Target.WorkflowRuns.Window)
+
+	if err != nil {
+		return err
+	}
+
+	for _, run := range runs {
+		event := &github.WorkflowRunEvent{
+			WorkflowRun: run,
+			Repo:        run.GetRepository(),
+		}
+
+		if event.Repo == nil {
+			event.Repo = repo
+		}
+
+		if err := p.db.StoreWorkflowRunEvent(event); err != nil {
+			return fmt.Errorf("failed to store workflow run %d: %w", run.GetID(), err)
+		}
+	}
+
+	return nil
+}
+
+func (p *poller) pollJobs() {
+	// Jobs of runs which have been completed before the previous successful
+	// poll are already stored, so we only fetch jobs for recent or unfinished
+	// runs. Every repo of a cycle shares the same threshold, and a failed cycle
+	// keeps the threshold to backfill the missed jobs on the next one.
+	started := time.Now()
+	since := p.lastJobs.Add(-p.cfg.Target.WorkflowJobs.PollInterval)
+
+	if p.poll("workflow_job", func(repo *github.Repository) error {
+		return p.pollRepoJobs(repo, since)
+	}) {
+		p.lastJobs = started
+	}
+}
+
+func (p *poller) pollRepoJobs(repo *github.Repository, since time.Time) error {
+	runs, err := p.listRuns(repo, p.cfg.Target.WorkflowJobs.Window)
+
+	if err != nil {
+		return err
+	}
+
+	for _, run := range runs {
+		if run.GetStatus() == "completed" && run.GetUpdatedAt().Before(since) {
+			continue
+		}
+
+		jobs, err := p.listJobs(repo, run.GetID())
+
+		if err != nil {
+			return err
+		}
+
+		for _, job := range jobs {
+			if err := p.db.StoreWorkflowJobEvent(&github.WorkflowJobEvent{
+				WorkflowJob: job,
+				Repo:        repo,
+			}); err != nil {
+				return fmt.Errorf("failed to store workflow job %d: %w", job.GetID(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *poller) listRuns(repo *github.Repository, window time.Duration) ([]*github.WorkflowRun, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Target.Timeout)
+	defer cancel()
+
+	opts := &github.ListWorkflowRunsOptions{
+		Created: ">=" + time.Now().Add(-window).UTC().Format("2006-01-02T15:04:05Z"),
+		ListOptions: github.ListOptions{
+			PerPage: p.cfg.Target.PerPage,
+		},
+	}
+
+	var (
+		runs []*github.WorkflowRun
+	)
+
+	for {
+		result, resp, err := p.client.Actions.ListRepositoryWorkflowRuns(
+			ctx,
+			repo.GetOwner().GetLogin(),
+			repo.GetName(),
+			opts,
+		)
+
+		if err != nil {
+			exporter.CloseBody(resp)
+			return nil, err
+		}
+
+		runs = append(
+			runs,
+			result.WorkflowRuns...,
+		)
+
+		if resp.NextPage == 0 {
+			exporter.CloseBody(resp)
+			break
+		}
+
+		exporter.CloseBody(resp)
+		opts.Page = resp.NextPage
+	}
+
+	return runs, nil
+}
+
+func (p *poller) listJobs(repo *github.Repository, runID int64) ([]*github.WorkflowJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Target.Timeout)
+	defer cancel()
+
+	opts := &github.ListWorkflowJobsOptions{
+		Filter: "latest",
+		ListOptions: github.ListOptions{
+			PerPage: p.cfg.Target.PerPage,
+		},
+	}
+
+	var (
+		jobs []*github.WorkflowJob
+	)
+
+	for {
+		result, resp, err := p.client.Actions.ListWorkflowJobs(
+			ctx,
+			repo.GetOwner().GetLogin(),
+			repo.GetName(),
+			runID,
+			opts,
+		)
+
+		if err != nil {
+			exporter.CloseBody(resp)
+			return nil, err
+		}
+
+		jobs = append(
+			jobs,
+			result.Jobs...,
+		)
+
+		if resp.NextPage == 0 {
+			exporter.CloseBody(resp)
+			break
+		}
+
+		exporter.CloseBody(resp)
+		opts.Page = resp.NextPage
+	}
+
+	return jobs, nil
+}
+
+// repos resolves the configured repos and the repos of all configured orgs.
+func (p *poller) repos() []*github.Repository {
+	collected := make(map[string]bool)
+	result := make([]*github.Repository, 0)
+
+	appendRepo := func(record *github.Repository) {
+		if collected[record.GetFullName()] {
+			return
+		}
+
+		collected[record.GetFullName()] = true
+		result = append(result, record)
+	}
+
+	for _, name := range p.cfg.Target.Repos {
+		n := strings.Split(name, "/")
+
+		if len(n) != 2 {
+			p.logger.Error("Invalid repo name",
+				"name", name,
+			)
+
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Target.Timeout)
+		records, err := exporter.ReposByOwnerAndName(ctx, p.client, n[0], n[1], p.cfg.Target.PerPage)
+		cancel()
+
+		if err != nil {
+			p.logger.Error("Failed to fetch repos",
+				"name", name,
+				"err", err,
+			)
+
+			requestFailures.WithLabelValues("poller").Inc()
+			continue
+		}
+
+		for _, record := range records {
+			if !glob.Glob(name, record.GetFullName()) {
+				continue
+			}
+
+			appendRepo(record)
+		}
+	}
+
+	for _, name := range p.cfg.Target.Orgs {
+		ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Target.Timeout)
+		records, err := exporter.ReposByOrg(ctx, p.client, name, p.cfg.Target.PerPage)
+		cancel()
+
+		if err != nil {
+			p.logger.Error("Failed to fetch org repos",
+				"name", name,
+				"err", err,
+			)
+
+			requestFailures.WithLabelValues("poller").Inc()
+			continue
+		}
+
+		for _, record := range records {
+			appendRepo(record)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,183 @@
+package action
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/promhippie/github_exporter/pkg/config"
+)
+
+// testGithubClient returns a client talking to a fake API defined by the mux.
+func testGithubClient(t *testing.T, mux *http.ServeMux) *github.Client {
+	t.Helper()
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(server.Client())
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	return client
+}
+
+func testPollerConfig() *config.Config {
+	cfg := config.Load()
+	cfg.Target.Repos = []string{"owner/repo"}
+	cfg.Target.Timeout = 10 * time.Second
+	cfg.Target.PerPage = 1
+	cfg.Target.WorkflowRuns.Window = time.Hour
+	cfg.Target.WorkflowJobs.Window = time.Hour
+	cfg.Target.WorkflowJobs.PollInterval = time.Minute
+
+	return cfg
+}
+
+func TestPollerRuns(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /repos/owner/repo", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":1,"name":"repo","full_name":"owner/repo","owner":{"login":"owner"}}`)
+	})
+
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		// Every page contains a single run to cover the pagination.
+		switch r.URL.Query().Get("page") {
+		case "", "1":
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, r.URL.Path))
+			fmt.Fprintf(w, `{"total_count":2,"workflow_runs":[{"id":1,"workflow_id":2,"run_number":1,"run_attempt":1,"name":"CI","status":"completed","conclusion":"success","created_at":%q,"updated_at":%q}]}`, now.Add(-5*time.Minute).Format(time.RFC3339), now.Format(time.RFC3339))
+		case "2":
+			fmt.Fprintf(w, `{"total_count":2,"workflow_runs":[{"id":2,"workflow_id":2,"run_number":2,"run_attempt":1,"name":"CI","status":"in_progress","created_at":%q,"updated_at":%q}]}`, now.Add(-time.Minute).Format(time.RFC3339), now.Format(time.RFC3339))
+		}
+	})
+
+	db := testWebhookStore(t)
+
+	p := newPoller(
+		testPollerConfig(),
+		db,
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		testGithubClient(t, mux),
+	)
+
+	p.pollRuns()
+
+	records, err := db.GetWorkflowRuns(time.Hour)
+
+	if err != nil {
+		t.Fatalf("Failed to fetch workflow runs: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 polled workflow runs, got %d", len(records))
+	}
+
+	for _, record := range records {
+		if record.Owner != "owner" || record.Repo != "repo" {
+			t.Errorf("Expected workflow run of owner/repo, got %s/%s", record.Owner, record.Repo)
+		}
+	}
+}
+
+func TestPollerJobs(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	mux := http.NewServeMux()
+
+	var (
+		lock    sync.Mutex
+		fetched = make(map[string]int)
+		failing bool
+	)
+
+	mux.HandleFunc("GET /repos/owner/repo", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id":1,"name":"repo","full_name":"owner/repo","owner":{"login":"owner"}}`)
+	})
+
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"total_count":2,"workflow_runs":[{"id":1,"status":"completed","created_at":%q,"updated_at":%q},{"id":2,"status":"in_progress","created_at":%q,"updated_at":%q}]}`,
+			now.Add(-time.Hour/2).Format(time.RFC3339),
+			now.Add(-time.Hour/2).Format(time.RFC3339),
+			now.Add(-time.Minute).Format(time.RFC3339),
+			now.Format(time.RFC3339),
+		)
+	})
+
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs/{id}/jobs", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		id := r.PathValue("id")
+		fetched[id]++
+
+		fmt.Fprintf(w, `{"total_count":1,"jobs":[{"id":%s0,"run_id":%s,"name":"test","status":"completed","conclusion":"success","created_at":%q,"started_at":%q,"completed_at":%q}]}`,
+			id,
+			id,
+			now.Add(-time.Minute).Format(time.RFC3339),
+			now.Add(-time.Minute).Format(time.RFC3339),
+			now.Format(time.RFC3339),
+		)
+	})
+
+	counts := func() map[string]int {
+		lock.Lock()
+		defer lock.Unlock()
+
+		return maps.Clone(fetched)
+	}
+
+	db := testWebhookStore(t)
+
+	p := newPoller(
+		testPollerConfig(),
+		db,
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		testGithubClient(t, mux),
+	)
+
+	p.pollJobs()
+
+	if got := counts(); got["1"] != 1 || got["2"] != 1 {
+		t.Errorf("Expected jobs of all runs on the first poll, got %v", got)
+	}
+
+	records, err := db.GetWorkflowJobs(time.Hour)
+
+	if err != nil {
+		t.Fatalf("Failed to fetch workflow jobs: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Errorf("Expected 2 polled workflow jobs, got %d", len(records))
+	}
+
+	p.pollJobs()
+
+	if got := counts(); got["1"] != 1 || got["2"] != 2 {
+		t.Errorf("Expected only jobs of unfinished runs on the next poll, got %v", got)
+	}
+
+	lock.Lock()
+	failing = true
+	lock.Unlock()
+
+	lastJobs := p.lastJobs
+	p.pollJobs()
+
+	if !p.lastJobs.Equal(lastJobs) {
+		t.Errorf("Expected failed poll to keep the threshold, got %v instead of %v", p.lastJobs, lastJobs)
+	}
+}
@@ -0,0 +1,157 @@
+package action
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/promhippie/github_exporter/pkg/config"
+)
+
+// webhookTask defines a queued webhook event.
+type webhookTask struct {
+	kind     string
+	received time.Time
+	process  func() error
+	done     func(error, time.Duration)
+}
+
+// webhookQueue processes webhook events asynchronously by a pool of workers.
+type webhookQueue struct {
+	cfg    config.Queue
+	logger *slog.Logger
+	tasks  chan *webhookTask
+	closed bool
+	lock   sync.RWMutex
+	wg     sync.WaitGroup
+}
+
+// newWebhookQueue initializes a new queue based on the configuration.
+func newWebhookQueue(cfg config.Queue, logger *slog.Logger) *webhookQueue {
+	return &webhookQueue{
+		cfg:    cfg,
+		logger: logger.With("component", "queue"),
+		tasks:  make(chan *webhookTask, cfg.Size),
+	}
+}
+
+// Enqueue adds a task to the queue, it returns false if the task got dropped.
+// The done callback gets called once after the final attempt with the last
+// error and the time spent waiting between the retries.
+func (q *webhookQueue) Enqueue(kind string, process func() error, done func(error, time.Duration)) bool {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	if q.closed {
+		webhookQueueDropped.WithLabelValues(kind, "shutdown").Inc()
+		return false
+	}
+
+	select {
+	case q.tasks <- &webhookTask{
+		kind:     kind,
+		received: time.Now(),
+		process:  process,
+		done:     done,
+	}:
+		return true
+	default:
+		q.logger.Warn("Dropping webhook event, queue is full",
+			"type", kind,
+			"size", q.cfg.Size,
+		)
+
+		webhookQueueDropped.WithLabelValues(kind, "full").Inc()
+		return false
+	}
+}
+
+// Depth returns the number of currently queued tasks.
+func (q *webhookQueue) Depth() int {
+	return len(q.tasks)
+}
+
+// Run starts the workers and blocks until the queue have been drained.
+func (q *webhookQueue) Run() error {
+	q.logger.Info("Starting webhook queue",
+		"size", q.cfg.Size,
+		"workers", q.cfg.Workers,
+	)
+
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+
+		go func() {
+			defer q.wg.Done()
+
+			for task := range q.tasks {
+				q.process(task)
+			}
+		}()
+	}
+
+	q.wg.Wait()
+	return nil
+}
+
+// Close stops accepting new tasks and lets the workers drain the queue.
+func (q *webhookQueue) Close() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	q.logger.Info("Draining webhook queue",
+		"depth", q.Depth(),
+	)
+
+	q.closed = true
+	close(q.tasks)
+}
+
+func (q *webhookQueue) process(task *webhookTask) {
+	var (
+		paused time.Duration
+	)
+
+	_, err := backoff.Retry(
+		context.Background(),
+		func() (bool, error) {
+			if err := task.process(); err != nil {
+				return false, err
+			}
+
+			return true, nil
+		},
+		backoff.WithBackOff(backoff.NewExponentialBackOff()),
+		backoff.WithMaxElapsedTime(q.cfg.Retry),
+		backoff.WithNotify(func(err error, dur time.Duration) {
+			paused += dur
+
+			q.logger.Warn("Processing webhook event failed",
+				"type", task.kind,
+				"retry", dur,
+				"error", err,
+			)
+		}),
+	)
+
+	if err != nil {
+		q.logger.Error("Giving up to process webhook event",
+			"type", task.kind,
+			"error", err,
+		)
+
+		webhookQueueDropped.WithLabelValues(task.kind, "failed").Inc()
+	}
+
+	webhookQueueLatency.WithLabelValues(task.kind).Observe(time.Since(task.received).Seconds())
+
+	if task.done != nil {
+		task.done(err, paused)
+	}
+}
@@ -0,0 +1,110 @@
+package action
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/promhippie/github_exporter/pkg/config"
+)
+
+func TestWebhookQueueDone(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		retry    time.Duration
+		attempts int
+		failed   bool
+		paused   bool
+	}{
+		{
+			name:     "success",
+			failures: 0,
+			retry:    time.Minute,
+			attempts: 1,
+			failed:   false,
+			paused:   false,
+		},
+		{
+			name:     "retried",
+			failures: 1,
+			retry:    time.Minute,
+			attempts: 2,
+			failed:   false,
+			paused:   true,
+		},
+		{
+			name:     "given up",
+			failures: 100,
+			retry:    time.Millisecond,
+			attempts: 1,
+			failed:   true,
+			paused:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := newWebhookQueue(
+				config.Queue{
+					Size:    1,
+					Workers: 1,
+					Retry:   tt.retry,
+				},
+				slog.New(slog.NewTextHandler(os.Stdout, nil)),
+			)
+
+			attempts := 0
+			calls := 0
+
+			var (
+				lastErr    error
+				lastPaused time.Duration
+			)
+
+			if !queue.Enqueue(
+				"workflow_run",
+				func() error {
+					attempts++
+
+					if attempts <= tt.failures {
+						return errors.New("failed")
+					}
+
+					return nil
+				},
+				func(err error, paused time.Duration) {
+					calls++
+					lastErr = err
+					lastPaused = paused
+				},
+			) {
+				t.Fatalf("Expected task to be enqueued")
+			}
+
+			queue.Close()
+
+			if err := queue.Run(); err != nil {
+				t.Fatalf("Failed to run queue: %v", err)
+			}
+
+			if calls != 1 {
+				t.Errorf("Expected done to be called once, got %d", calls)
+			}
+
+			if attempts != tt.attempts {
+				t.Errorf("Expected %d attempts, got %d", tt.attempts, attempts)
+			}
+
+			if (lastErr != nil) != tt.failed {
+				t.Errorf("Expected failure to be %v, got %v", tt.failed, lastErr)
+			}
+
+			if (lastPaused > 0) != tt.paused {
+				t.Errorf("Expected pause to be %v, got %v", tt.paused, lastPaused)
+			}
+		})
+	}
+}
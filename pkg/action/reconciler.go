@@ -0,0 +1,258 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+// reconciler refreshes unfinished records which missed their final webhook.
+type reconciler struct {
+	cfg    *config.Config
+	db     store.Store
+	logger *slog.Logger
+	client *github.Client
+	stop   chan struct{}
+	once   sync.Once
+}
+
+// newReconciler initializes a new reconciler based on the configuration.
+func newReconciler(cfg *config.Config, db store.Store, logger *slog.Logger, client *github.Client) *reconciler {
+	return &reconciler{
+		cfg:    cfg,
+		db:     db,
+		logger: logger.With("component", "reconciler"),
+		client: client,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Run reconciles on startup and within the configured interval until it gets closed.
+func (r *reconciler) Run() error {
+	r.logger.Info("Starting reconciler",
+		"interval", r.cfg.Reconciler.Interval,
+		"threshold", r.cfg.Reconciler.Threshold,
+	)
+
+	ticker := time.NewTicker(r.cfg.Reconciler.Interval)
+	defer ticker.Stop()
+
+	for {
+		if r.cfg.Collector.WorkflowRuns {
+			r.reconcileRuns()
+		}
+
+		if r.cfg.Collector.WorkflowJobs {
+			r.reconcileJobs()
+		}
+
+		select {
+		case <-r.stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops the reconciler.
+func (r *reconciler) Close() {
+	r.once.Do(func() {
+		close(r.stop)
+	})
+}
+
+func (r *reconciler) reconcileRuns() {
+	records, err := r.db.GetPendingWorkflowRuns(r.cfg.Reconciler.Threshold)
+
+	if err != nil {
+		r.logger.Error("Failed to fetch pending workflow runs",
+			"err", err,
+		)
+
+		return
+	}
+
+	for _, record := range records {
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		finished, err := r.reconcileRun(record)
+
+		if err != nil {
+			r.logger.Error("Failed to reconcile workflow run",
+				"owner", record.Owner,
+				"repo", record.Repo,
+				"id", record.Identifier,
+				"err", err,
+			)
+
+			requestFailures.WithLabelValues("reconciler").Inc()
+			continue
+		}
+
+		if finished {
+			reconciledRecords.WithLabelValues("workflow_run").Inc()
+		}
+	}
+}
+
+// reconcileRun stores the current state of the workflow run and reports if it
+// has been finished, runs which have been deleted get removed from the store.
+func (r *reconciler) reconcileRun(record *store.WorkflowRun) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Target.Timeout)
+	defer cancel()
+
+	now := time.Now()
+	run, resp, err := r.client.Actions.GetWorkflowRunAttempt(
+		ctx,
+		record.Owner,
+		record.Repo,
+		record.Identifier,
+		record.Attempt,
+		nil,
+	)
+	requestDuration.WithLabelValues("reconciler").Observe(time.Since(now).Seconds())
+
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		r.logger.Debug("Deleting vanished workflow run",
+			"owner", record.Owner,
+			"repo", record.Repo,
+			"id", record.Identifier,
+		)
+
+		if err := r.db.DeleteWorkflowRun(record); err != nil {
+			return false, fmt.Errorf("failed to delete workflow run: %w", err)
+		}
+
+		return true, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	r.logger.Debug("Reconciling workflow run",
+		"owner", record.Owner,
+		"repo", record.Repo,
+		"id", record.Identifier,
+		"status", run.GetStatus(),
+		"conclusion", run.GetConclusion(),
+	)
+
+	if err := r.db.StoreWorkflowRunEvent(&github.WorkflowRunEvent{
+		WorkflowRun: run,
+		Repo:        reconcileRepo(record.Owner, record.Repo),
+	}); err != nil {
+		return false, fmt.Errorf("failed to store workflow run: %w", err)
+	}
+
+	return run.GetStatus() == "completed", nil
+}
+
+func (r *reconciler) reconcileJobs() {
+	records, err := r.db.GetPendingWorkflowJobs(r.cfg.Reconciler.Threshold)
+
+	if err != nil {
+		r.logger.Error("Failed to fetch pending workflow jobs",
+			"err", err,
+		)
+
+		return
+	}
+
+	for _, record := range records {
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		finished, err := r.reconcileJob(record)
+
+		if err != nil {
+			r.logger.Error("Failed to reconcile workflow job",
+				"owner", record.Owner,
+				"repo", record.Repo,
+				"id", record.Identifier,
+				"err", err,
+			)
+
+			requestFailures.WithLabelValues("reconciler").Inc()
+			continue
+		}
+
+		if finished {
+			reconciledRecords.WithLabelValues("workflow_job").Inc()
+		}
+	}
+}
+
+// reconcileJob stores the current state of the workflow job and reports if it
+// has been finished, jobs which have been deleted get removed from the store.
+func (r *reconciler) reconcileJob(record *store.WorkflowJob) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Target.Timeout)
+	defer cancel()
+
+	now := time.Now()
+	job, resp, err := r.client.Actions.GetWorkflowJobByID(
+		ctx,
+		record.Owner,
+		record.Repo,
+		record.Identifier,
+	)
+	requestDuration.WithLabelValues("reconciler").Observe(time.Since(now).Seconds())
+
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		r.logger.Debug("Deleting vanished workflow job",
+			"owner", record.Owner,
+			"repo", record.Repo,
+			"id", record.Identifier,
+		)
+
+		if err := r.db.DeleteWorkflowJob(record); err != nil {
+			return false, fmt.Errorf("failed to delete workflow job: %w", err)
+		}
+
+		return true, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	r.logger.Debug("Reconciling workflow job",
+		"owner", record.Owner,
+		"repo", record.Repo,
+		"id", record.Identifier,
+		"status", job.GetStatus(),
+		"conclusion", job.GetConclusion(),
+	)
+
+	if err := r.db.StoreWorkflowJobEvent(&github.WorkflowJobEvent{
+		WorkflowJob: job,
+		Repo:        reconcileRepo(record.Owner, record.Repo),
+	}); err != nil {
+		return false, fmt.Errorf("failed to store workflow job: %w", err)
+	}
+
+	return job.GetStatus() == "completed", nil
+}
+
+func reconcileRepo(owner, name string) *github.Repository {
+	return &github.Repository{
+		Name: github.Ptr(name),
+		Owner: &github.User{
+			Login: github.Ptr(owner),
+		},
+	}
+}
@@ -0,0 +1,200 @@
+package action
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestReconcilerRuns(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs/1/attempts/1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"id":1,"workflow_id":2,"run_number":1,"run_attempt":1,"name":"CI","status":"completed","conclusion":"success","created_at":%q,"updated_at":%q}`,
+			now.Add(-3*time.Hour).Format(time.RFC3339),
+			now.Format(time.RFC3339),
+		)
+	})
+
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs/2/attempts/1", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs/3/attempts/1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"id":3,"workflow_id":2,"run_number":3,"run_attempt":1,"name":"CI","status":"in_progress","created_at":%q,"updated_at":%q}`,
+			now.Add(-3*time.Hour).Format(time.RFC3339),
+			now.Format(time.RFC3339),
+		)
+	})
+
+	mux.HandleFunc("GET /repos/owner/repo/actions/runs/4/attempts/1", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	db := testWebhookStore(t)
+
+	for _, id := range []int64{1, 2, 3, 4} {
+		if err := db.StoreWorkflowRunEvent(&github.WorkflowRunEvent{
+			Repo: reconcileRepo("owner", "repo"),
+			WorkflowRun: &github.WorkflowRun{
+				ID:         github.Ptr(id),
+				WorkflowID: github.Ptr(int64(2)),
+				RunNumber:  github.Ptr(int(id)),
+				RunAttempt: github.Ptr(1),
+				Name:       github.Ptr("CI"),
+				Status:     github.Ptr("in_progress"),
+				CreatedAt:  &github.Timestamp{Time: now.Add(-3 * time.Hour)},
+				UpdatedAt:  &github.Timestamp{Time: now.Add(-2 * time.Hour)},
+			},
+		}); err != nil {
+			t.Fatalf("Failed to store workflow run: %v", err)
+		}
+	}
+
+	cfg := testPollerConfig()
+	cfg.Reconciler.Threshold = time.Hour
+
+	r := newReconciler(
+		cfg,
+		db,
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		testGithubClient(t, mux),
+	)
+
+	reconciled := testutil.ToFloat64(reconciledRecords.WithLabelValues("workflow_run"))
+	failures := testutil.ToFloat64(requestFailures.WithLabelValues("reconciler"))
+
+	r.reconcileRuns()
+
+	if got := testutil.ToFloat64(reconciledRecords.WithLabelValues("workflow_run")) - reconciled; got != 2 {
+		t.Errorf("Expected 2 reconciled workflow runs, got %v", got)
+	}
+
+	if got := testutil.ToFloat64(requestFailures.WithLabelValues("reconciler")) - failures; got != 1 {
+		t.Errorf("Expected 1 failed reconciliation, got %v", got)
+	}
+
+	pending, err := db.GetPendingWorkflowRuns(cfg.Reconciler.Threshold)
+
+	if err != nil {
+		t.Fatalf("Failed to fetch pending workflow runs: %v", err)
+	}
+
+	if len(pending) != 1 || pending[0].Identifier != 2 {
+		t.Errorf("Expected only the failed workflow run to stay pending, got %d records", len(pending))
+	}
+
+	records, err := db.GetWorkflowRuns(4 * time.Hour)
+
+	if err != nil {
+		t.Fatalf("Failed to fetch workflow runs: %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Errorf("Expected the deleted workflow run to be removed, got %d records", len(records))
+	}
+
+	for _, record := range records {
+		if record.Identifier == 4 {
+			t.Errorf("Expected workflow run 4 to be removed")
+		}
+	}
+}
+
+func TestReconcilerJobs(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /repos/owner/repo/actions/jobs/5", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"id":5,"run_id":1,"run_attempt":1,"name":"test","status":"completed","conclusion":"failure","created_at":%q,"started_at":%q,"completed_at":%q}`,
+			now.Add(-2*time.Hour).Format(time.RFC3339),
+			now.Add(-2*time.Hour).Format(time.RFC3339),
+			now.Format(time.RFC3339),
+		)
+	})
+
+	mux.HandleFunc("GET /repos/owner/repo/actions/jobs/6", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	mux.HandleFunc("GET /repos/owner/repo/actions/jobs/7", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `{"id":7,"run_id":1,"run_attempt":1,"name":"lint","status":"queued","created_at":%q}`,
+			now.Add(-2*time.Hour).Format(time.RFC3339),
+		)
+	})
+
+	db := testWebhookStore(t)
+
+	for id, name := range map[int64]string{5: "test", 6: "build", 7: "lint"} {
+		if err := db.StoreWorkflowJobEvent(&github.WorkflowJobEvent{
+			Repo: reconcileRepo("owner", "repo"),
+			WorkflowJob: &github.WorkflowJob{
+				ID:         github.Ptr(id),
+				RunID:      github.Ptr(int64(1)),
+				RunAttempt: github.Ptr(int64(1)),
+				Name:       github.Ptr(name),
+				Status:     github.Ptr("in_progress"),
+				CreatedAt:  &github.Timestamp{Time: now.Add(-2 * time.Hour)},
+				StartedAt:  &github.Timestamp{Time: now.Add(-2 * time.Hour)},
+			},
+		}); err != nil {
+			t.Fatalf("Failed to store workflow job: %v", err)
+		}
+	}
+
+	cfg := testPollerConfig()
+	cfg.Reconciler.Threshold = time.Hour
+
+	r := newReconciler(
+		cfg,
+		db,
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		testGithubClient(t, mux),
+	)
+
+	reconciled := testutil.ToFloat64(reconciledRecords.WithLabelValues("workflow_job"))
+
+	r.reconcileJobs()
+
+	if got := testutil.ToFloat64(reconciledRecords.WithLabelValues("workflow_job")) - reconciled; got != 2 {
+		t.Errorf("Expected 2 reconciled workflow jobs, got %v", got)
+	}
+
+	records, err := db.GetWorkflowJobs(3 * time.Hour)
+
+	if err != nil {
+		t.Fatalf("Failed to fetch workflow jobs: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 workflow jobs, got %d", len(records))
+	}
+
+	for _, record := range records {
+		switch record.Identifier {
+		case 5:
+			if record.Status != "completed" || record.Conclusion != "failure" {
+				t.Errorf("Expected reconciled workflow job, got %s with %s", record.Status, record.Conclusion)
+			}
+		case 6:
+			t.Errorf("Expected deleted workflow job to be removed")
+		}
+	}
+
+	pending, err := db.GetPendingWorkflowJobs(cfg.Reconciler.Threshold)
+
+	if err != nil {
+		t.Fatalf("Failed to fetch pending workflow jobs: %v", err)
+	}
+
+	if len(pending) != 1 || pending[0].Identifier != 7 {
+		t.Errorf("Expected only the queued workflow job to stay pending, got %d records", len(pending))
+	}
+}
@@ -0,0 +1,166 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+// resolver resolves the names and paths of workflows referenced by runs.
+type resolver struct {
+	cfg    *config.Config
+	db     store.Store
+	logger *slog.Logger
+	client *github.Client
+	stop   chan struct{}
+	once   sync.Once
+}
+
+// newResolver initializes a new resolver based on the configuration.
+func newResolver(cfg *config.Config, db store.Store, logger *slog.Logger, client *github.Client) *resolver {
+	return &resolver{
+		cfg:    cfg,
+		db:     db,
+		logger: logger.With("component", "resolver"),
+		client: client,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Run resolves workflows on startup and within the configured interval until it gets closed.
+func (r *resolver) Run() error {
+	r.logger.Info("Starting resolver",
+		"interval", r.cfg.Target.WorkflowRuns.ResolveInterval,
+	)
+
+	ticker := time.NewTicker(r.cfg.Target.WorkflowRuns.ResolveInterval)
+	defer ticker.Stop()
+
+	for {
+		r.resolve()
+
+		select {
+		case <-r.stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops the resolver.
+func (r *resolver) Close() {
+	r.once.Do(func() {
+		close(r.stop)
+	})
+}
+
+func (r *resolver) resolve() {
+	runs, err := r.db.GetWorkflowRuns(r.cfg.Target.WorkflowRuns.Window)
+
+	if err != nil {
+		r.logger.Error("Failed to fetch workflow runs",
+			"err", err,
+		)
+
+		return
+	}
+
+	workflows, err := r.db.GetWorkflows()
+
+	if err != nil {
+		r.logger.Error("Failed to fetch workflows",
+			"err", err,
+		)
+
+		return
+	}
+
+	// Workflows which have been refreshed within the interval are skipped,
+	// that way restarts of the exporter don't resolve everything again.
+	threshold := time.Now().Add(-r.cfg.Target.WorkflowRuns.ResolveInterval).Unix()
+	resolved := make(map[string]bool)
+
+	for _, workflow := range workflows {
+		if workflow.UpdatedAt > threshold {
+			resolved[workflowKey(workflow.Owner, workflow.Repo, workflow.Identifier)] = true
+		}
+	}
+
+	for _, run := range runs {
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		key := workflowKey(run.Owner, run.Repo, run.WorkflowID)
+
+		if resolved[key] {
+			continue
+		}
+
+		resolved[key] = true
+
+		if err := r.resolveWorkflow(run.Owner, run.Repo, run.WorkflowID); err != nil {
+			r.logger.Error("Failed to resolve workflow",
+				"owner", run.Owner,
+				"repo", run.Repo,
+				"workflow", run.WorkflowID,
+				"err", err,
+			)
+
+			requestFailures.WithLabelValues("resolver").Inc()
+		}
+	}
+}
+
+func (r *resolver) resolveWorkflow(owner, repo string, id int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Target.Timeout)
+	defer cancel()
+
+	now := time.Now()
+	workflow, _, err := r.client.Actions.GetWorkflowByID(
+		ctx,
+		owner,
+		repo,
+		id,
+	)
+	requestDuration.WithLabelValues("resolver").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		return err
+	}
+
+	r.logger.Debug("Resolved workflow",
+		"owner", owner,
+		"repo", repo,
+		"workflow", id,
+		"name", workflow.GetName(),
+		"path", workflow.GetPath(),
+	)
+
+	if err := r.db.StoreWorkflow(&store.Workflow{
+		Owner:      owner,
+		Repo:       repo,
+		Identifier: id,
+		Name:       workflow.GetName(),
+		Path:       workflow.GetPath(),
+		State:      workflow.GetState(),
+		UpdatedAt:  time.Now().Unix(),
+	}); err != nil {
+		return fmt.Errorf("failed to store workflow: %w", err)
+	}
+
+	return nil
+}
+
+func workflowKey(owner, repo string, id int64) string {
+	return owner + "/" + repo + "/" + strconv.FormatInt(id, 10)
+}
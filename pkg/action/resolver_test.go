@@ -0,0 +1,114 @@
+package action
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+func TestResolverResolve(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	mux := http.NewServeMux()
+
+	var (
+		lock    sync.Mutex
+		fetched = make(map[string]int)
+	)
+
+	mux.HandleFunc("GET /repos/owner/repo/actions/workflows/{id}", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		id := r.PathValue("id")
+		fetched[id]++
+
+		fmt.Fprintf(w, `{"id":%s,"name":"Workflow %s","path":".github/workflows/%s.yml","state":"active"}`, id, id, id)
+	})
+
+	db := testWebhookStore(t)
+
+	// Workflow 2 has two runs but should be resolved once, workflow 3 has been
+	// resolved recently and workflow 4 is outdated.
+	for id, workflowID := range map[int64]int64{1: 2, 2: 2, 3: 3, 4: 4} {
+		if err := db.StoreWorkflowRunEvent(&github.WorkflowRunEvent{
+			Repo: reconcileRepo("owner", "repo"),
+			WorkflowRun: &github.WorkflowRun{
+				ID:         github.Ptr(id),
+				WorkflowID: github.Ptr(workflowID),
+				RunNumber:  github.Ptr(int(id)),
+				RunAttempt: github.Ptr(1),
+				Status:     github.Ptr("completed"),
+				CreatedAt:  &github.Timestamp{Time: now.Add(-time.Minute)},
+				UpdatedAt:  &github.Timestamp{Time: now},
+			},
+		}); err != nil {
+			t.Fatalf("Failed to store workflow run: %v", err)
+		}
+	}
+
+	for id, updatedAt := range map[int64]time.Time{3: now, 4: now.Add(-2 * time.Hour)} {
+		if err := db.StoreWorkflow(&store.Workflow{
+			Owner:      "owner",
+			Repo:       "repo",
+			Identifier: id,
+			Name:       "Cached",
+			UpdatedAt:  updatedAt.Unix(),
+		}); err != nil {
+			t.Fatalf("Failed to store workflow: %v", err)
+		}
+	}
+
+	cfg := testPollerConfig()
+	cfg.Target.WorkflowRuns.ResolveInterval = time.Hour
+
+	r := newResolver(
+		cfg,
+		db,
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		testGithubClient(t, mux),
+	)
+
+	r.resolve()
+
+	lock.Lock()
+	got := maps.Clone(fetched)
+	lock.Unlock()
+
+	if got["2"] != 1 || got["3"] != 0 || got["4"] != 1 {
+		t.Errorf("Expected workflows 2 and 4 to be resolved once, got %v", got)
+	}
+
+	workflows, err := db.GetWorkflows()
+
+	if err != nil {
+		t.Fatalf("Failed to fetch workflows: %v", err)
+	}
+
+	want := map[int64]string{
+		2: "Workflow 2",
+		3: "Cached",
+		4: "Workflow 4",
+	}
+
+	if len(workflows) != len(want) {
+		t.Fatalf("Expected %d workflows, got %d", len(want), len(workflows))
+	}
+
+	for _, workflow := range workflows {
+		if workflow.Name != want[workflow.Identifier] {
+			t.Errorf("Expected workflow %d to be named %q, got %q", workflow.Identifier, want[workflow.Identifier], workflow.Name)
+		}
+
+		if workflow.Identifier == 2 && workflow.Path != ".github/workflows/2.yml" {
+			t.Errorf("Expected resolved path of workflow 2, got %q", workflow.Path)
+		}
+	}
+}
@@ -9,12 +9,14 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/go-github/v72/github"
 	"github.com/oklog/run"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/exporter-toolkit/web"
 	"github.com/promhippie/github_exporter/pkg/config"
@@ -42,10 +44,65 @@ func Server(cfg *config.Config, db store.Store, logger *slog.Logger) error {
 
 	var gr run.Group
 
+	var (
+		queue *webhookQueue
+	)
+
+	if useWebhook(cfg, logger) && cfg.Webhook.Queue.Enabled {
+		queue = newWebhookQueue(cfg.Webhook.Queue, logger)
+
+		registry.MustRegister(prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "webhook_queue_depth",
+				Help:      "Number of webhook events waiting within the queue.",
+			},
+			func() float64 {
+				return float64(queue.Depth())
+			},
+		))
+
+		gr.Add(func() error {
+			return queue.Run()
+		}, func(_ error) {
+			queue.Close()
+		})
+	}
+
+	if usePoller(cfg, logger) {
+		poller := newPoller(cfg, db, logger, client)
+
+		gr.Add(func() error {
+			return poller.Run()
+		}, func(_ error) {
+			poller.Close()
+		})
+	}
+
+	if useReconciler(cfg, logger) {
+		reconciler := newReconciler(cfg, db, logger, client)
+
+		gr.Add(func() error {
+			return reconciler.Run()
+		}, func(_ error) {
+			reconciler.Close()
+		})
+	}
+
+	if useResolver(cfg, logger) {
+		resolver := newResolver(cfg, db, logger, client)
+
+		gr.Add(func() error {
+			return resolver.Run()
+		}, func(_ error) {
+			resolver.Close()
+		})
+	}
+
 	{
 		server := &http.Server{
 			Addr:         cfg.Server.Addr,
-			Handler:      handler(cfg, db, logger, client),
+			Handler:      handler(cfg, db, logger, client, queue),
 			ReadTimeout:  5 * time.Second,
 			WriteTimeout: cfg.Server.Timeout,
 		}
@@ -86,7 +143,7 @@ func Server(cfg *config.Config, db store.Store, logger *slog.Logger) error {
 		stop := make(chan os.Signal, 1)
 
 		gr.Add(func() error {
-			signal.Notify(stop, os.Interrupt)
+			signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
 			<-stop
 
@@ -99,7 +156,7 @@ func Server(cfg *config.Config, db store.Store, logger *slog.Logger) error {
 	return gr.Run()
 }
 
-func handler(cfg *config.Config, db store.Store, logger *slog.Logger, client *github.Client) *chi.Mux {
+func handler(cfg *config.Config, db store.Store, logger *slog.Logger, client *github.Client, queue *webhookQueue) *chi.Mux {
 	mux := chi.NewRouter()
 	mux.Use(middleware.Recoverer(logger))
 	mux.Use(middleware.RealIP)
@@ -113,98 +170,242 @@ func handler(cfg *config.Config, db store.Store, logger *slog.Logger, client *gi
 	if cfg.Collector.Admin {
 		logger.Debug("Admin collector registered")
 
-		registry.MustRegister(exporter.NewAdminCollector(
+		registry.MustRegister(limitCollector(
+			cfg,
 			logger,
-			client,
-			db,
-			requestFailures,
-			requestDuration,
-			cfg.Target,
+			"admin",
+			exporter.NewAdminCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
 		))
 	}
 
 	if cfg.Collector.Orgs {
 		logger.Debug("Org collector registered")
 
-		registry.MustRegister(exporter.NewOrgCollector(
+		registry.MustRegister(limitCollector(
+			cfg,
 			logger,
-			client,
-			db,
-			requestFailures,
-			requestDuration,
-			cfg.Target,
+			"org",
+			exporter.NewOrgCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
 		))
 	}
 
 	if cfg.Collector.Repos {
 		logger.Debug("Repo collector registered")
 
-		registry.MustRegister(exporter.NewRepoCollector(
+		registry.MustRegister(limitCollector(
+			cfg,
 			logger,
-			client,
-			db,
-			requestFailures,
-			requestDuration,
-			cfg.Target,
+			"repo",
+			exporter.NewRepoCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
 		))
 	}
 
 	if cfg.Collector.Billing {
 		logger.Debug("Billing collector registered")
 
-		registry.MustRegister(exporter.NewBillingCollector(
+		registry.MustRegister(limitCollector(
+			cfg,
 			logger,
-			client,
-			db,
-			requestFailures,
-			requestDuration,
-			cfg.Target,
+			"billing",
+			exporter.NewBillingCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
 		))
 	}
 
 	if cfg.Collector.Runners {
 		logger.Debug("Runner collector registered")
 
-		registry.MustRegister(exporter.NewRunnerCollector(
+		registry.MustRegister(limitCollector(
+			cfg,
 			logger,
-			client,
-			db,
-			requestFailures,
-			requestDuration,
-			cfg.Target,
+			"runner",
+			exporter.NewRunnerCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
 		))
 	}
 
 	if cfg.Collector.WorkflowRuns {
 		logger.Debug("WorkflowRun collector registered")
 
-		registry.MustRegister(exporter.NewWorkflowRunCollector(
+		registry.MustRegister(limitCollector(
+			cfg,
 			logger,
-			client,
-			db,
-			requestFailures,
-			requestDuration,
-			cfg.Target,
+			"workflow_run",
+			exporter.NewWorkflowRunCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
 		))
 	}
 
 	if cfg.Collector.WorkflowJobs {
 		logger.Debug("WorkflowJob collector registered")
 
-		registry.MustRegister(exporter.NewWorkflowJobCollector(
+		registry.MustRegister(limitCollector(
+			cfg,
 			logger,
-			client,
-			db,
-			requestFailures,
-			requestDuration,
-			cfg.Target,
+			"workflow_job",
+			exporter.NewWorkflowJobCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
+		))
+	}
+
+	if cfg.Collector.Summaries {
+		logger.Debug("WorkflowSummary collector registered")
+
+		registry.MustRegister(limitCollector(
+			cfg,
+			logger,
+			"workflow_summary",
+			exporter.NewWorkflowSummaryCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
+		))
+	}
+
+	if cfg.Collector.Health {
+		logger.Debug("WorkflowHealth collector registered")
+
+		registry.MustRegister(limitCollector(
+			cfg,
+			logger,
+			"workflow_health",
+			exporter.NewWorkflowHealthCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
+		))
+	}
+
+	if cfg.Collector.Regressions {
+		logger.Debug("WorkflowRegression collector registered")
+
+		registry.MustRegister(limitCollector(
+			cfg,
+			logger,
+			"workflow_regression",
+			exporter.NewWorkflowRegressionCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
+		))
+	}
+
+	if cfg.Collector.CheckRuns {
+		logger.Debug("CheckRun collector registered")
+
+		registry.MustRegister(limitCollector(
+			cfg,
+			logger,
+			"check_run",
+			exporter.NewCheckRunCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
+		))
+	}
+
+	if cfg.Collector.PullRequests {
+		logger.Debug("PullRequest collector registered")
+
+		registry.MustRegister(limitCollector(
+			cfg,
+			logger,
+			"pull_request",
+			exporter.NewPullRequestCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
+		))
+	}
+
+	if cfg.Collector.Deployments {
+		logger.Debug("Deployment collector registered")
+
+		registry.MustRegister(limitCollector(
+			cfg,
+			logger,
+			"deployment",
+			exporter.NewDeploymentCollector(
+				logger,
+				client,
+				db,
+				requestFailures,
+				requestDuration,
+				cfg.Target,
+			),
 		))
 	}
 
 	reg := promhttp.HandlerFor(
 		registry,
 		promhttp.HandlerOpts{
-			ErrorLog: promLogger{logger},
+			ErrorLog:          promLogger{logger},
+			EnableOpenMetrics: true,
 		},
 	)
 
@@ -215,132 +416,8 @@ func handler(cfg *config.Config, db store.Store, logger *slog.Logger, client *gi
 	mux.Route("/", func(root chi.Router) {
 		root.Handle(cfg.Server.Path, reg)
 
-		if cfg.Collector.WorkflowRuns || cfg.Collector.WorkflowJobs {
-			root.HandleFunc(cfg.Webhook.Path, func(w http.ResponseWriter, r *http.Request) {
-				secret, err := config.Value(cfg.Webhook.Secret)
-
-				if err != nil {
-					logger.Error("Failed to read webhook secret",
-						"error", err,
-					)
-
-					w.Header().Set("Content-Type", "text/plain")
-					w.WriteHeader(http.StatusInternalServerError)
-
-					io.WriteString(w, http.StatusText(http.StatusInternalServerError))
-					return
-				}
-
-				payload, err := github.ValidatePayload(
-					r,
-					[]byte(secret),
-				)
-
-				if err != nil {
-					logger.Error("Failed to parse github webhook",
-						"error", err,
-					)
-
-					w.Header().Set("Content-Type", "text/plain")
-					w.WriteHeader(http.StatusInternalServerError)
-
-					io.WriteString(w, http.StatusText(http.StatusInternalServerError))
-					return
-				}
-
-				event, err := github.ParseWebHook(
-					github.WebHookType(r),
-					payload,
-				)
-
-				if err != nil {
-					logger.Error("Failed to parse github event",
-						"error", err,
-					)
-
-					w.Header().Set("Content-Type", "text/plain")
-					w.WriteHeader(http.StatusInternalServerError)
-
-					io.WriteString(w, http.StatusText(http.StatusInternalServerError))
-					return
-				}
-
-				switch event := event.(type) {
-				case *github.WorkflowRunEvent:
-					wfRun := event.GetWorkflowRun()
-					logger.Debug("Received webhook request",
-						"type", "workflow_run",
-						"owner", event.GetRepo().GetOwner().GetLogin(),
-						"repo", event.GetRepo().GetName(),
-						"workflow", wfRun.GetWorkflowID(),
-						"number", wfRun.GetRunNumber(),
-						"id", wfRun.GetID(),
-						"event", wfRun.GetEvent(),
-						"status", wfRun.GetStatus(),
-						"conclusion", wfRun.GetConclusion(),
-						"actor", wfRun.GetActor().GetLogin(),
-						"created_at", wfRun.GetCreatedAt().Time.Unix(),
-						"updated_at", wfRun.GetUpdatedAt().Time.Unix(),
-						"started_at", wfRun.GetRunStartedAt().Time.Unix(),
-					)
-
-					if err := db.StoreWorkflowRunEvent(event); err != nil {
-						logger.Error("Failed to store github event",
-							"type", "workflow_run",
-							"owner", event.GetRepo().GetOwner().GetLogin(),
-							"repo", event.GetRepo().GetName(),
-							"workflow", wfRun.GetWorkflowID(),
-							"number", wfRun.GetRunNumber(),
-							"error", err,
-						)
-
-						w.Header().Set("Content-Type", "text/plain")
-						w.WriteHeader(http.StatusInternalServerError)
-
-						io.WriteString(w, http.StatusText(http.StatusInternalServerError))
-						return
-					}
-				case *github.WorkflowJobEvent:
-					wfJob := event.GetWorkflowJob()
-					logger.Debug("received webhook request",
-						"type", "workflow_job",
-						"owner", event.GetRepo().GetOwner().GetLogin(),
-						"repo", event.GetRepo().GetName(),
-						"id", wfJob.GetID(),
-						"name", wfJob.GetName(),
-						"attempt", wfJob.GetRunAttempt(),
-						"status", wfJob.GetStatus(),
-						"conclusion", wfJob.GetConclusion(),
-						"created_at", wfJob.GetCreatedAt().Time.Unix(),
-						"started_at", wfJob.GetStartedAt().Time.Unix(),
-						"completed_at", wfJob.GetCompletedAt().Time.Unix(),
-						"labels", strings.Join(wfJob.Labels, ", "),
-					)
-
-					if err := db.StoreWorkflowJobEvent(event); err != nil {
-						logger.Error(
-							"failed to store github event",
-							"type", "workflow_job",
-							"owner", event.GetRepo().GetOwner().GetLogin(),
-							"repo", event.GetRepo().GetName(),
-							"name", wfJob.GetName(),
-							"id", wfJob.GetID(),
-							"error", err,
-						)
-
-						w.Header().Set("Content-Type", "text/plain")
-						w.WriteHeader(http.StatusInternalServerError)
-
-						io.WriteString(w, http.StatusText(http.StatusInternalServerError))
-						return
-					}
-				}
-
-				w.Header().Set("Content-Type", "text/plain")
-				w.WriteHeader(http.StatusOK)
-
-				io.WriteString(w, http.StatusText(http.StatusOK))
-			})
+		if useWebhook(cfg, logger) {
+			root.Handle(cfg.Webhook.Path, newWebhook(cfg, db, logger, queue))
 		}
 
 		root.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) {
@@ -361,6 +438,58 @@ func handler(cfg *config.Config, db store.Store, logger *slog.Logger, client *gi
 	return mux
 }
 
+func limitCollector(cfg *config.Config, logger *slog.Logger, name string, collector prometheus.Collector) prometheus.Collector {
+	limit, err := cfg.Limits.Collector(name)
+
+	if err != nil {
+		logger.Error("Failed to parse series limit",
+			"collector", name,
+			"err", err,
+		)
+
+		return collector
+	}
+
+	if limit <= 0 {
+		return collector
+	}
+
+	return exporter.NewLimitCollector(
+		logger,
+		collector,
+		name,
+		seriesDropped,
+		limit,
+		cfg.Limits.Overflow,
+	)
+}
+
+func useWebhook(cfg *config.Config, _ *slog.Logger) bool {
+	return cfg.Collector.WorkflowRuns ||
+		cfg.Collector.WorkflowJobs ||
+		cfg.Collector.Summaries ||
+		cfg.Collector.Health ||
+		cfg.Collector.Regressions ||
+		cfg.Collector.CheckRuns ||
+		cfg.Collector.PullRequests ||
+		cfg.Collector.Deployments
+}
+
+func usePoller(cfg *config.Config, _ *slog.Logger) bool {
+	return (cfg.Collector.WorkflowRuns && cfg.Target.WorkflowRuns.Poll) ||
+		(cfg.Collector.WorkflowJobs && cfg.Target.WorkflowJobs.Poll)
+}
+
+func useReconciler(cfg *config.Config, _ *slog.Logger) bool {
+	return cfg.Reconciler.Enabled &&
+		(cfg.Collector.WorkflowRuns || cfg.Collector.WorkflowJobs)
+}
+
+func useResolver(cfg *config.Config, _ *slog.Logger) bool {
+	return cfg.Collector.WorkflowRuns &&
+		cfg.Target.WorkflowRuns.Resolve
+}
+
 func useEnterprise(cfg *config.Config, _ *slog.Logger) bool {
 	return cfg.Target.BaseURL != ""
 }
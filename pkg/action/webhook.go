@@ -0,0 +1,550 @@
+package action
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+var (
+	// errWebhookBody gets returned if the body of a delivery can't be read.
+	errWebhookBody = errors.New("failed to read webhook body")
+)
+
+// webhook handles the webhook deliveries from GitHub.
+type webhook struct {
+	cfg       *config.Config
+	db        store.Store
+	logger    *slog.Logger
+	queue     *webhookQueue
+	lastPrune atomic.Int64
+}
+
+// newWebhook initializes the webhook handler, the queue is optional.
+func newWebhook(cfg *config.Config, db store.Store, logger *slog.Logger, queue *webhookQueue) *webhook {
+	return &webhook{
+		cfg:    cfg,
+		db:     db,
+		logger: logger,
+		queue:  queue,
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	received := time.Now()
+	kind := github.WebHookType(r)
+	delivery := github.DeliveryID(r)
+
+	webhookReceived.WithLabelValues(kind).Inc()
+
+	payload, err := h.validate(r)
+
+	if errors.Is(err, errWebhookBody) {
+		h.logger.Error("Failed to read github webhook",
+			"error", err,
+		)
+
+		observeOutcome(kind, "", "read_error", time.Since(received))
+		writeStatus(w, http.StatusInternalServerError)
+		return
+	}
+
+	if err != nil {
+		h.logger.Error("Failed to parse github webhook",
+			"error", err,
+		)
+
+		observeOutcome(kind, "", "invalid_signature", time.Since(received))
+		writeStatus(w, http.StatusInternalServerError)
+		return
+	}
+
+	event, err := github.ParseWebHook(
+		kind,
+		payload,
+	)
+
+	if err != nil {
+		h.logger.Error("Failed to parse github event",
+			"error", err,
+		)
+
+		observeOutcome(kind, "", "unparseable", time.Since(received))
+		writeStatus(w, http.StatusInternalServerError)
+		return
+	}
+
+	owner := eventOwner(event)
+	process := h.processor(event)
+
+	if process == nil {
+		observeOutcome(kind, owner, "ignored", time.Since(received))
+		writeStatus(w, http.StatusOK)
+		return
+	}
+
+	if !h.reserveDelivery(delivery, kind, received) {
+		h.logger.Debug("Skipping duplicate webhook delivery",
+			"type", kind,
+			"delivery", delivery,
+		)
+
+		webhookDuplicates.WithLabelValues(kind).Inc()
+		observeOutcome(kind, owner, "duplicate", time.Since(received))
+		writeStatus(w, http.StatusOK)
+		return
+	}
+
+	// The outcome gets recorded once after the final attempt, the time spent
+	// between retries is not part of the latency.
+	done := func(err error, paused time.Duration) {
+		if err != nil {
+			observeOutcome(kind, owner, "store_failed", time.Since(received)-paused)
+			h.releaseDelivery(delivery)
+			return
+		}
+
+		observeOutcome(kind, owner, "stored", time.Since(received)-paused)
+		h.pruneDeliveries()
+	}
+
+	if h.queue != nil {
+		if !h.queue.Enqueue(kind, process, done) {
+			h.releaseDelivery(delivery)
+			writeStatus(w, http.StatusServiceUnavailable)
+			return
+		}
+
+		writeStatus(w, http.StatusAccepted)
+		return
+	}
+
+	err = process()
+	done(err, 0)
+
+	if err != nil {
+		writeStatus(w, http.StatusInternalServerError)
+		return
+	}
+
+	writeStatus(w, http.StatusOK)
+}
+
+// validate reads the payload and accepts it if any of the secrets matches,
+// the single secret comes first followed by the additional secrets.
+func (h *webhook) validate(r *http.Request) ([]byte, error) {
+	raws := h.cfg.Webhook.Secrets
+
+	if h.cfg.Webhook.Secret != "" {
+		raws = append([]string{h.cfg.Webhook.Secret}, raws...)
+	}
+
+	secrets := make([]string, 0, len(raws))
+
+	for _, raw := range raws {
+		secret, err := config.Value(raw)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read webhook secret: %w", err)
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	signature := r.Header.Get(github.SHA256SignatureHeader)
+
+	if signature == "" {
+		signature = r.Header.Get(github.SHA1SignatureHeader)
+	}
+
+	body, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errWebhookBody, err)
+	}
+
+	if len(secrets) == 0 {
+		return github.ValidatePayloadFromBody(
+			contentType,
+			bytes.NewReader(body),
+			signature,
+			nil,
+		)
+	}
+
+	for idx, secret := range secrets {
+		payload, err := github.ValidatePayloadFromBody(
+			contentType,
+			bytes.NewReader(body),
+			signature,
+			[]byte(secret),
+		)
+
+		if err != nil {
+			continue
+		}
+
+		webhookSecretMatches.WithLabelValues(strconv.Itoa(idx)).Inc()
+		return payload, nil
+	}
+
+	return nil, errors.New("payload signature does not match any secret")
+}
+
+// processor returns a function to store the event, nil for unsupported events.
+func (h *webhook) processor(event interface{}) func() error {
+	switch event := event.(type) {
+	case *github.WorkflowRunEvent:
+		return func() error {
+			wfRun := event.GetWorkflowRun()
+			h.logger.Debug("Received webhook request",
+				"type", "workflow_run",
+				"owner", event.GetRepo().GetOwner().GetLogin(),
+				"repo", event.GetRepo().GetName(),
+				"workflow", wfRun.GetWorkflowID(),
+				"number", wfRun.GetRunNumber(),
+				"id", wfRun.GetID(),
+				"event", wfRun.GetEvent(),
+				"status", wfRun.GetStatus(),
+				"conclusion", wfRun.GetConclusion(),
+				"actor", wfRun.GetActor().GetLogin(),
+				"created_at", wfRun.GetCreatedAt().Time.Unix(),
+				"updated_at", wfRun.GetUpdatedAt().Time.Unix(),
+				"started_at", wfRun.GetRunStartedAt().Time.Unix(),
+			)
+
+			if err := h.db.StoreWorkflowRunEvent(event); err != nil {
+				h.logger.Error("Failed to store github event",
+					"type", "workflow_run",
+					"owner", event.GetRepo().GetOwner().GetLogin(),
+					"repo", event.GetRepo().GetName(),
+					"workflow", wfRun.GetWorkflowID(),
+					"number", wfRun.GetRunNumber(),
+					"error", err,
+				)
+
+				return err
+			}
+
+			return nil
+		}
+	case *github.WorkflowJobEvent:
+		return func() error {
+			wfJob := event.GetWorkflowJob()
+			h.logger.Debug("received webhook request",
+				"type", "workflow_job",
+				"owner", event.GetRepo().GetOwner().GetLogin(),
+				"repo", event.GetRepo().GetName(),
+				"id", wfJob.GetID(),
+				"name", wfJob.GetName(),
+				"attempt", wfJob.GetRunAttempt(),
+				"status", wfJob.GetStatus(),
+				"conclusion", wfJob.GetConclusion(),
+				"created_at", wfJob.GetCreatedAt().Time.Unix(),
+				"started_at", wfJob.GetStartedAt().Time.Unix(),
+				"completed_at", wfJob.GetCompletedAt().Time.Unix(),
+				"labels", strings.Join(wfJob.Labels, ", "),
+			)
+
+			if err := h.db.StoreWorkflowJobEvent(event); err != nil {
+				h.logger.Error(
+					"failed to store github event",
+					"type", "workflow_job",
+					"owner", event.GetRepo().GetOwner().GetLogin(),
+					"repo", event.GetRepo().GetName(),
+					"name", wfJob.GetName(),
+					"id", wfJob.GetID(),
+					"error", err,
+				)
+
+				return err
+			}
+
+			return nil
+		}
+	case *github.CheckSuiteEvent:
+		return func() error {
+			suite := event.GetCheckSuite()
+			h.logger.Debug("Received webhook request",
+				"type", "check_suite",
+				"owner", event.GetRepo().GetOwner().GetLogin(),
+				"repo", event.GetRepo().GetName(),
+				"id", suite.GetID(),
+				"app", suite.GetApp().GetSlug(),
+				"status", suite.GetStatus(),
+				"conclusion", suite.GetConclusion(),
+				"created_at", suite.GetCreatedAt().Time.Unix(),
+				"updated_at", suite.GetUpdatedAt().Time.Unix(),
+			)
+
+			if err := h.db.StoreCheckSuiteEvent(event); err != nil {
+				h.logger.Error("Failed to store github event",
+					"type", "check_suite",
+					"owner", event.GetRepo().GetOwner().GetLogin(),
+					"repo", event.GetRepo().GetName(),
+					"id", suite.GetID(),
+					"error", err,
+				)
+
+				return err
+			}
+
+			return nil
+		}
+	case *github.CheckRunEvent:
+		return func() error {
+			checkRun := event.GetCheckRun()
+			h.logger.Debug("Received webhook request",
+				"type", "check_run",
+				"owner", event.GetRepo().GetOwner().GetLogin(),
+				"repo", event.GetRepo().GetName(),
+				"id", checkRun.GetID(),
+				"name", checkRun.GetName(),
+				"app", checkRun.GetApp().GetSlug(),
+				"status", checkRun.GetStatus(),
+				"conclusion", checkRun.GetConclusion(),
+				"started_at", checkRun.GetStartedAt().Time.Unix(),
+				"completed_at", checkRun.GetCompletedAt().Time.Unix(),
+			)
+
+			if err := h.db.StoreCheckRunEvent(event); err != nil {
+				h.logger.Error("Failed to store github event",
+					"type", "check_run",
+					"owner", event.GetRepo().GetOwner().GetLogin(),
+					"repo", event.GetRepo().GetName(),
+					"name", checkRun.GetName(),
+					"id", checkRun.GetID(),
+					"error", err,
+				)
+
+				return err
+			}
+
+			return nil
+		}
+	case *github.PullRequestEvent:
+		return func() error {
+			pr := event.GetPullRequest()
+			h.logger.Debug("Received webhook request",
+				"type", "pull_request",
+				"owner", event.GetRepo().GetOwner().GetLogin(),
+				"repo", event.GetRepo().GetName(),
+				"number", pr.GetNumber(),
+				"action", event.GetAction(),
+				"base", pr.GetBase().GetRef(),
+				"state", pr.GetState(),
+				"merged", pr.GetMerged(),
+				"created_at", pr.GetCreatedAt().Time.Unix(),
+				"updated_at", pr.GetUpdatedAt().Time.Unix(),
+			)
+
+			if err := h.db.StorePullRequestEvent(event); err != nil {
+				h.logger.Error("Failed to store github event",
+					"type", "pull_request",
+					"owner", event.GetRepo().GetOwner().GetLogin(),
+					"repo", event.GetRepo().GetName(),
+					"number", pr.GetNumber(),
+					"error", err,
+				)
+
+				return err
+			}
+
+			return nil
+		}
+	case *github.PullRequestReviewEvent:
+		return func() error {
+			pr := event.GetPullRequest()
+			h.logger.Debug("Received webhook request",
+				"type", "pull_request_review",
+				"owner", event.GetRepo().GetOwner().GetLogin(),
+				"repo", event.GetRepo().GetName(),
+				"number", pr.GetNumber(),
+				"action", event.GetAction(),
+				"reviewer", event.GetReview().GetUser().GetLogin(),
+				"state", event.GetReview().GetState(),
+				"submitted_at", event.GetReview().GetSubmittedAt().Time.Unix(),
+			)
+
+			if err := h.db.StorePullRequestReviewEvent(event); err != nil {
+				h.logger.Error("Failed to store github event",
+					"type", "pull_request_review",
+					"owner", event.GetRepo().GetOwner().GetLogin(),
+					"repo", event.GetRepo().GetName(),
+					"number", pr.GetNumber(),
+					"error", err,
+				)
+
+				return err
+			}
+
+			return nil
+		}
+	case *github.DeploymentEvent:
+		return func() error {
+			deployment := event.GetDeployment()
+			h.logger.Debug("Received webhook request",
+				"type", "deployment",
+				"owner", event.GetRepo().GetOwner().GetLogin(),
+				"repo", event.GetRepo().GetName(),
+				"id", deployment.GetID(),
+				"environment", deployment.GetEnvironment(),
+				"sha", deployment.GetSHA(),
+				"created_at", deployment.GetCreatedAt().Time.Unix(),
+			)
+
+			if err := h.db.StoreDeploymentEvent(event); err != nil {
+				h.logger.Error("Failed to store github event",
+					"type", "deployment",
+					"owner", event.GetRepo().GetOwner().GetLogin(),
+					"repo", event.GetRepo().GetName(),
+					"id", deployment.GetID(),
+					"error", err,
+				)
+
+				return err
+			}
+
+			return nil
+		}
+	case *github.DeploymentStatusEvent:
+		return func() error {
+			deployment := event.GetDeployment()
+			h.logger.Debug("Received webhook request",
+				"type", "deployment_status",
+				"owner", event.GetRepo().GetOwner().GetLogin(),
+				"repo", event.GetRepo().GetName(),
+				"id", deployment.GetID(),
+				"environment", deployment.GetEnvironment(),
+				"state", event.GetDeploymentStatus().GetState(),
+				"created_at", event.GetDeploymentStatus().GetCreatedAt().Time.Unix(),
+			)
+
+			if err := h.db.StoreDeploymentStatusEvent(event); err != nil {
+				h.logger.Error("Failed to store github event",
+					"type", "deployment_status",
+					"owner", event.GetRepo().GetOwner().GetLogin(),
+					"repo", event.GetRepo().GetName(),
+					"id", deployment.GetID(),
+					"error", err,
+				)
+
+				return err
+			}
+
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// reserveDelivery records the delivery on receipt to skip concurrent and later
+// redeliveries, it returns false for duplicates. Deliveries without an ID or
+// failing reservations get processed anyway.
+func (h *webhook) reserveDelivery(delivery, kind string, received time.Time) bool {
+	if delivery == "" {
+		return true
+	}
+
+	reserved, err := h.db.ReserveWebhookDelivery(&store.WebhookDelivery{
+		Delivery:  delivery,
+		Event:     kind,
+		CreatedAt: received.Unix(),
+	})
+
+	if err != nil {
+		h.logger.Error("Failed to reserve github delivery",
+			"delivery", delivery,
+			"error", err,
+		)
+
+		return true
+	}
+
+	return reserved
+}
+
+// releaseDelivery removes the reservation of a failed delivery, that way a
+// redelivery by GitHub gets processed again.
+func (h *webhook) releaseDelivery(delivery string) {
+	if delivery == "" {
+		return
+	}
+
+	if err := h.db.ReleaseWebhookDelivery(delivery); err != nil {
+		h.logger.Error("Failed to release github delivery",
+			"delivery", delivery,
+			"error", err,
+		)
+	}
+}
+
+// pruneDeliveries removes outdated deliveries at most once per minute.
+func (h *webhook) pruneDeliveries() {
+	if last := h.lastPrune.Load(); time.Since(time.Unix(last, 0)) > time.Minute && h.lastPrune.CompareAndSwap(last, time.Now().Unix()) {
+		if err := h.db.PruneWebhookDeliveries(deliveryWindow(h.cfg)); err != nil {
+			h.logger.Error("Failed to prune github deliveries",
+				"error", err,
+			)
+		}
+	}
+}
+
+func deliveryWindow(cfg *config.Config) time.Duration {
+	return slices.Max([]time.Duration{
+		cfg.Target.WorkflowRuns.PurgeWindow,
+		cfg.Target.WorkflowJobs.PurgeWindow,
+		cfg.Target.CheckRuns.PurgeWindow,
+		cfg.Target.PullRequests.PurgeWindow,
+		cfg.Target.Deployments.PurgeWindow,
+	})
+}
+
+// eventOwner extracts the owner of the repository or organization of an event.
+func eventOwner(event interface{}) string {
+	if val, ok := event.(interface{ GetRepo() *github.Repository }); ok {
+		if owner := val.GetRepo().GetOwner().GetLogin(); owner != "" {
+			return owner
+		}
+	}
+
+	if val, ok := event.(interface{ GetOrg() *github.Organization }); ok {
+		return val.GetOrg().GetLogin()
+	}
+
+	return ""
+}
+
+// observeOutcome records the outcome and latency of a webhook delivery.
+func observeOutcome(kind, owner, outcome string, latency time.Duration) {
+	webhookOutcomes.WithLabelValues(kind, owner, outcome).Inc()
+	webhookLatency.WithLabelValues(kind, outcome).Observe(latency.Seconds())
+}
+
+func writeStatus(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(status)
+
+	io.WriteString(w, http.StatusText(status))
+}
@@ -0,0 +1,301 @@
+package action
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+const (
+	testWorkflowRunPayload = `{
+	"action": "completed",
+	"workflow_run": {
+		"id": 1,
+		"workflow_id": 2,
+		"run_number": 3,
+		"run_attempt": 1,
+		"status": "completed",
+		"conclusion": "success",
+		"created_at": "2024-01-01T00:00:00Z",
+		"updated_at": "2024-01-01T00:10:00Z"
+	},
+	"repository": {
+		"name": "repo",
+		"owner": {
+			"login": "owner"
+		}
+	}
+}`
+)
+
+// failingStore fails to store workflow runs to simulate a broken database.
+type failingStore struct {
+	store.Store
+}
+
+func (s *failingStore) StoreWorkflowRunEvent(_ *github.WorkflowRunEvent) error {
+	return errors.New("database is gone")
+}
+
+func testWebhookRequest(delivery, secret, payload string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/github", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(github.EventTypeHeader, "workflow_run")
+	req.Header.Set(github.DeliveryIDHeader, delivery)
+
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(payload))
+
+		req.Header.Set(github.SHA256SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	return req
+}
+
+func testWebhookStore(t *testing.T) store.Store {
+	t.Helper()
+
+	s, err := store.New("memory://", slog.New(slog.NewTextHandler(os.Stdout, nil)))
+
+	if err != nil {
+		t.Fatalf("Failed to setup store: %v", err)
+	}
+
+	return s
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	db := testWebhookStore(t)
+	cfg := config.Load()
+	cfg.Target.WorkflowRuns.PurgeWindow = time.Hour
+
+	handler := newWebhook(
+		cfg,
+		db,
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		nil,
+	)
+
+	tests := []struct {
+		name     string
+		delivery string
+		want     int
+		stored   bool
+	}{
+		{
+			name:     "first delivery",
+			delivery: "delivery-1",
+			want:     http.StatusOK,
+			stored:   true,
+		},
+		{
+			name:     "redelivery",
+			delivery: "delivery-1",
+			want:     http.StatusOK,
+			stored:   false,
+		},
+		{
+			name:     "other delivery",
+			delivery: "delivery-2",
+			want:     http.StatusOK,
+			stored:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := testutil.ToFloat64(webhookDuplicates.WithLabelValues("workflow_run"))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, testWebhookRequest(tt.delivery, "", testWorkflowRunPayload))
+
+			if rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rec.Code)
+			}
+
+			if duplicate := testutil.ToFloat64(webhookDuplicates.WithLabelValues("workflow_run")) > before; duplicate == tt.stored {
+				t.Errorf("Expected delivery to be stored %v, got duplicate %v", tt.stored, duplicate)
+			}
+		})
+	}
+}
+
+func TestWebhookReleaseFailedDelivery(t *testing.T) {
+	db := testWebhookStore(t)
+	cfg := config.Load()
+	cfg.Target.WorkflowRuns.PurgeWindow = time.Hour
+
+	handler := newWebhook(
+		cfg,
+		&failingStore{Store: db},
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		nil,
+	)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, testWebhookRequest("delivery", "", testWorkflowRunPayload))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+
+	reserved, err := db.ReserveWebhookDelivery(&store.WebhookDelivery{
+		Delivery:  "delivery",
+		CreatedAt: time.Now().Unix(),
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to reserve delivery: %v", err)
+	}
+
+	if !reserved {
+		t.Errorf("Expected failed delivery to be released")
+	}
+}
+
+func TestWebhookSecrets(t *testing.T) {
+	cfg := config.Load()
+	cfg.Target.WorkflowRuns.PurgeWindow = time.Hour
+	cfg.Webhook.Secret = "pre,vious"
+	cfg.Webhook.Secrets = []string{
+		"base64://Y3VycmVudA==",
+	}
+
+	handler := newWebhook(
+		cfg,
+		testWebhookStore(t),
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		nil,
+	)
+
+	tests := []struct {
+		name   string
+		secret string
+		want   int
+		index  string
+	}{
+		{
+			name:   "previous secret",
+			secret: "pre,vious",
+			want:   http.StatusOK,
+			index:  "0",
+		},
+		{
+			name:   "partial secret",
+			secret: "pre",
+			want:   http.StatusInternalServerError,
+		},
+		{
+			name:   "current secret",
+			secret: "current",
+			want:   http.StatusOK,
+			index:  "1",
+		},
+		{
+			name:   "unknown secret",
+			secret: "unknown",
+			want:   http.StatusInternalServerError,
+		},
+		{
+			name:   "unsigned",
+			secret: "",
+			want:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := map[string]float64{
+				"0": testutil.ToFloat64(webhookSecretMatches.WithLabelValues("0")),
+				"1": testutil.ToFloat64(webhookSecretMatches.WithLabelValues("1")),
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, testWebhookRequest(tt.name, tt.secret, testWorkflowRunPayload))
+
+			if rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rec.Code)
+			}
+
+			for index, before := range matches {
+				want := before
+
+				if index == tt.index {
+					want++
+				}
+
+				if got := testutil.ToFloat64(webhookSecretMatches.WithLabelValues(index)); got != want {
+					t.Errorf("Expected %v matches of secret %s, got %v", want, index, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookWithoutSecret(t *testing.T) {
+	cfg := config.Load()
+	cfg.Target.WorkflowRuns.PurgeWindow = time.Hour
+
+	handler := newWebhook(
+		cfg,
+		testWebhookStore(t),
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		nil,
+	)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, testWebhookRequest("unsigned", "", testWorkflowRunPayload))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestWebhookReadError(t *testing.T) {
+	cfg := config.Load()
+	cfg.Webhook.Secret = "secret"
+
+	handler := newWebhook(
+		cfg,
+		testWebhookStore(t),
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		nil,
+	)
+
+	readErrors := testutil.ToFloat64(webhookOutcomes.WithLabelValues("workflow_run", "", "read_error"))
+	invalid := testutil.ToFloat64(webhookOutcomes.WithLabelValues("workflow_run", "", "invalid_signature"))
+
+	req := testWebhookRequest("broken", "secret", testWorkflowRunPayload)
+	req.Body = io.NopCloser(iotest.ErrReader(errors.New("connection reset")))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+
+	if got := testutil.ToFloat64(webhookOutcomes.WithLabelValues("workflow_run", "", "read_error")) - readErrors; got != 1 {
+		t.Errorf("Expected 1 read error, got %v", got)
+	}
+
+	if got := testutil.ToFloat64(webhookOutcomes.WithLabelValues("workflow_run", "", "invalid_signature")) - invalid; got != 0 {
+		t.Errorf("Expected no invalid signature, got %v", got)
+	}
+}
@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"time"
 
@@ -104,6 +105,155 @@ func Run() error {
 			if cfg.Target.WorkflowJobs.PurgeWindow < cfg.Target.WorkflowJobs.Window {
 				logger.Warn("Workflow Run purge window cannot be smaller than query window or data loss will occur", "config", cfg.Target.WorkflowJobs)
 			}
+			if cfg.Target.CheckRuns.PurgeWindow < cfg.Target.CheckRuns.Window {
+				logger.Warn("Check Run purge window cannot be smaller than query window or data loss will occur", "config", cfg.Target.CheckRuns)
+			}
+			if cfg.Target.PullRequests.PurgeWindow < cfg.Target.PullRequests.Window {
+				logger.Warn("Pull Request purge window cannot be smaller than query window or data loss will occur", "config", cfg.Target.PullRequests)
+			}
+			if cfg.Target.Deployments.PurgeWindow < cfg.Target.Deployments.Window {
+				logger.Warn("Deployment purge window cannot be smaller than query window or data loss will occur", "config", cfg.Target.Deployments)
+			}
+			for _, rules := range [][]string{
+				cfg.Target.WorkflowRuns.Relabel,
+				cfg.Target.WorkflowJobs.Relabel,
+				cfg.Target.Runners.Relabel,
+			} {
+				if _, err := config.Relabels(rules); err != nil {
+					logger.Error("Failed to parse relabel rules",
+						"error", err,
+					)
+
+					return err
+				}
+			}
+
+			if _, err := cfg.Limits.Collector(""); err != nil {
+				logger.Error("Failed to parse series limits",
+					"error", err,
+				)
+
+				return err
+			}
+
+			if cfg.Webhook.Queue.Enabled {
+				if cfg.Webhook.Queue.Workers < 1 {
+					err := fmt.Errorf("invalid webhook queue workers: %d", cfg.Webhook.Queue.Workers)
+
+					logger.Error("Failed to validate webhook queue",
+						"error", err,
+					)
+
+					return err
+				}
+
+				if cfg.Webhook.Queue.Size < 1 {
+					err := fmt.Errorf("invalid webhook queue size: %d", cfg.Webhook.Queue.Size)
+
+					logger.Error("Failed to validate webhook queue",
+						"error", err,
+					)
+
+					return err
+				}
+			}
+
+			if cfg.Collector.WorkflowRuns && cfg.Target.WorkflowRuns.Poll && cfg.Target.WorkflowRuns.PollInterval <= 0 {
+				err := fmt.Errorf("invalid workflow run poll interval: %s", cfg.Target.WorkflowRuns.PollInterval)
+
+				logger.Error("Failed to validate poll interval",
+					"error", err,
+				)
+
+				return err
+			}
+
+			if cfg.Collector.WorkflowJobs && cfg.Target.WorkflowJobs.Poll && cfg.Target.WorkflowJobs.PollInterval <= 0 {
+				err := fmt.Errorf("invalid workflow job poll interval: %s", cfg.Target.WorkflowJobs.PollInterval)
+
+				logger.Error("Failed to validate poll interval",
+					"error", err,
+				)
+
+				return err
+			}
+
+			if cfg.Reconciler.Enabled && (cfg.Collector.WorkflowRuns || cfg.Collector.WorkflowJobs) && cfg.Reconciler.Interval <= 0 {
+				err := fmt.Errorf("invalid reconciler interval: %s", cfg.Reconciler.Interval)
+
+				logger.Error("Failed to validate reconciler interval",
+					"error", err,
+				)
+
+				return err
+			}
+
+			if cfg.Collector.WorkflowRuns && cfg.Target.WorkflowRuns.Resolve && cfg.Target.WorkflowRuns.ResolveInterval <= 0 {
+				err := fmt.Errorf("invalid workflow resolve interval: %s", cfg.Target.WorkflowRuns.ResolveInterval)
+
+				logger.Error("Failed to validate resolve interval",
+					"error", err,
+				)
+
+				return err
+			}
+
+			if cfg.Collector.Summaries {
+				for _, val := range cfg.Target.Summaries.Windows {
+					window, err := config.Window(val)
+
+					if err != nil {
+						logger.Error("Failed to parse summary window",
+							"window", val,
+							"error", err,
+						)
+
+						return err
+					}
+
+					if cfg.Target.WorkflowRuns.PurgeWindow < window || cfg.Target.WorkflowJobs.PurgeWindow < window {
+						logger.Warn("Workflow purge windows are smaller than summary window, summaries will be incomplete", "window", val)
+					}
+				}
+			}
+
+			if cfg.Collector.WorkflowJobs && cfg.Target.WorkflowJobs.Flaky {
+				if cfg.Target.WorkflowJobs.PurgeWindow < cfg.Target.WorkflowJobs.FlakyWindow {
+					logger.Warn("Workflow job purge window is smaller than flaky window, flaky jobs will be incomplete", "window", cfg.Target.WorkflowJobs.FlakyWindow.String())
+				}
+			}
+
+			if cfg.Collector.Health {
+				if !cfg.Collector.Repos {
+					logger.Warn("Workflow health requires the repo collector to detect the default branches")
+				}
+
+				if cfg.Target.WorkflowRuns.PurgeWindow < cfg.Target.Health.Window {
+					logger.Warn("Workflow run purge window is smaller than health window, workflow health will be incomplete", "window", cfg.Target.Health.Window.String())
+				}
+			}
+
+			if cfg.Collector.Deployments {
+				if cfg.Target.WorkflowRuns.PurgeWindow < cfg.Target.Deployments.Window {
+					logger.Warn("Workflow run purge window is smaller than deployment window, lead times will be incomplete", "window", cfg.Target.Deployments.Window.String())
+				}
+			}
+
+			if cfg.Collector.Regressions {
+				if cfg.Target.Regressions.Baseline < 1 {
+					err := fmt.Errorf("invalid workflow regression baseline: %d", cfg.Target.Regressions.Baseline)
+
+					logger.Error("Failed to validate regression baseline",
+						"error", err,
+					)
+
+					return err
+				}
+
+				if cfg.Target.WorkflowRuns.PurgeWindow < cfg.Target.Regressions.Window || cfg.Target.WorkflowJobs.PurgeWindow < cfg.Target.Regressions.Window {
+					logger.Warn("Workflow purge windows are smaller than regression window, regressions will be incomplete", "window", cfg.Target.Regressions.Window.String())
+				}
+			}
 
 			return action.Server(cfg, db, logger)
 		},
@@ -186,10 +336,66 @@ func RootFlags(cfg *config.Config) []cli.Flag {
 		&cli.StringFlag{
 			Name:        "webhook.secret",
 			Value:       "",
-			Usage:       "Secret used by GitHub to access webhook",
+			Usage:       "Secret used by GitHub to access webhook, also supports file:// and base64://",
 			Sources:     cli.EnvVars("GITHUB_EXPORTER_WEBHOOK_SECRET"),
 			Destination: &cfg.Webhook.Secret,
 		},
+		&cli.StringSliceFlag{
+			Name:        "webhook.secrets",
+			Value:       []string{},
+			Usage:       "Additional secrets for the webhook to rotate them, any of them gets accepted, also supports file:// and base64://",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WEBHOOK_SECRETS"),
+			Destination: &cfg.Webhook.Secrets,
+		},
+		&cli.BoolFlag{
+			Name:        "webhook.queue",
+			Value:       false,
+			Usage:       "Enable asynchronous processing of webhook events",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WEBHOOK_QUEUE"),
+			Destination: &cfg.Webhook.Queue.Enabled,
+		},
+		&cli.IntFlag{
+			Name:        "webhook.queue.size",
+			Value:       1000,
+			Usage:       "Maximum number of queued webhook events",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WEBHOOK_QUEUE_SIZE"),
+			Destination: &cfg.Webhook.Queue.Size,
+		},
+		&cli.IntFlag{
+			Name:        "webhook.queue.workers",
+			Value:       4,
+			Usage:       "Number of workers processing queued webhook events",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WEBHOOK_QUEUE_WORKERS"),
+			Destination: &cfg.Webhook.Queue.Workers,
+		},
+		&cli.DurationFlag{
+			Name:        "webhook.queue.retry",
+			Value:       5 * time.Minute,
+			Usage:       "Maximum time to retry storing a queued webhook event",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WEBHOOK_QUEUE_RETRY"),
+			Destination: &cfg.Webhook.Queue.Retry,
+		},
+		&cli.BoolFlag{
+			Name:        "reconciler",
+			Value:       false,
+			Usage:       "Enable reconciliation of unfinished workflow runs and jobs via the API",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_RECONCILER"),
+			Destination: &cfg.Reconciler.Enabled,
+		},
+		&cli.DurationFlag{
+			Name:        "reconciler.interval",
+			Value:       10 * time.Minute,
+			Usage:       "Interval for reconciling unfinished workflow runs and jobs",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_RECONCILER_INTERVAL"),
+			Destination: &cfg.Reconciler.Interval,
+		},
+		&cli.DurationFlag{
+			Name:        "reconciler.threshold",
+			Value:       1 * time.Hour,
+			Usage:       "Duration after unfinished workflow runs and jobs get reconciled",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_RECONCILER_THRESHOLD"),
+			Destination: &cfg.Reconciler.Threshold,
+		},
 		&cli.StringFlag{
 			Name:        "database.dsn",
 			Value:       defaultDatabaseDSN,
@@ -328,6 +534,62 @@ func RootFlags(cfg *config.Config) []cli.Flag {
 			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_RUNS_LABELS"),
 			Destination: &cfg.Target.WorkflowRuns.Labels,
 		},
+		&cli.StringSliceFlag{
+			Name:        "collector.workflow_runs.relabel",
+			Value:       []string{},
+			Usage:       "List of relabel rules for workflow runs like replace:branch:feature:feature/.*",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_RUNS_RELABEL"),
+			Destination: &cfg.Target.WorkflowRuns.Relabel,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.workflow_runs.status",
+			Value:       false,
+			Usage:       "Expose the deprecated numeric status metric for workflows for compatibility",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_RUNS_STATUS"),
+			Destination: &cfg.Target.WorkflowRuns.Status,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.workflow_runs.state",
+			Value:       true,
+			Usage:       "Expose the state of workflows as a state-set metric",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_RUNS_STATE"),
+			Destination: &cfg.Target.WorkflowRuns.State,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.workflow_runs.aggregate",
+			Value:       false,
+			Usage:       "Expose aggregated counters instead of metrics per workflow run",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_RUNS_AGGREGATE"),
+			Destination: &cfg.Target.WorkflowRuns.Aggregate,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.workflow_runs.poll",
+			Value:       false,
+			Usage:       "Enable polling of workflow runs from the API for setups without webhooks",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_RUNS_POLL"),
+			Destination: &cfg.Target.WorkflowRuns.Poll,
+		},
+		&cli.DurationFlag{
+			Name:        "collector.workflow_runs.poll_interval",
+			Value:       5 * time.Minute,
+			Usage:       "Interval for polling workflow runs from the API",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_RUNS_POLL_INTERVAL"),
+			Destination: &cfg.Target.WorkflowRuns.PollInterval,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.workflow_runs.resolve",
+			Value:       false,
+			Usage:       "Resolve names and paths of workflows from the API",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_RUNS_RESOLVE"),
+			Destination: &cfg.Target.WorkflowRuns.Resolve,
+		},
+		&cli.DurationFlag{
+			Name:        "collector.workflow_runs.resolve_interval",
+			Value:       time.Hour,
+			Usage:       "Interval for refreshing the resolved workflows",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_RUNS_RESOLVE_INTERVAL"),
+			Destination: &cfg.Target.WorkflowRuns.ResolveInterval,
+		},
 		&cli.BoolFlag{
 			Name:        "collector.workflow_jobs",
 			Value:       false,
@@ -356,6 +618,188 @@ func RootFlags(cfg *config.Config) []cli.Flag {
 			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_JOBS_LABELS"),
 			Destination: &cfg.Target.WorkflowJobs.Labels,
 		},
+		&cli.StringSliceFlag{
+			Name:        "collector.workflow_jobs.relabel",
+			Value:       []string{},
+			Usage:       "List of relabel rules for workflow jobs like replace:branch:feature:feature/.*",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_JOBS_RELABEL"),
+			Destination: &cfg.Target.WorkflowJobs.Relabel,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.workflow_jobs.aggregate",
+			Value:       false,
+			Usage:       "Expose aggregated counters instead of metrics per workflow job",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_JOBS_AGGREGATE"),
+			Destination: &cfg.Target.WorkflowJobs.Aggregate,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.workflow_jobs.steps",
+			Value:       false,
+			Usage:       "Expose duration and conclusion metrics for the steps of workflow jobs",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_JOBS_STEPS"),
+			Destination: &cfg.Target.WorkflowJobs.Steps,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.workflow_jobs.flaky",
+			Value:       false,
+			Usage:       "Expose metrics about jobs which failed and succeeded on a later attempt",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_JOBS_FLAKY"),
+			Destination: &cfg.Target.WorkflowJobs.Flaky,
+		},
+		&cli.DurationFlag{
+			Name:        "collector.workflow_jobs.flaky_window",
+			Value:       7 * 24 * time.Hour,
+			Usage:       "History window for detecting flaky workflow jobs",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_JOBS_FLAKY_WINDOW"),
+			Destination: &cfg.Target.WorkflowJobs.FlakyWindow,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.workflow_jobs.poll",
+			Value:       false,
+			Usage:       "Enable polling of workflow jobs from the API for setups without webhooks",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_JOBS_POLL"),
+			Destination: &cfg.Target.WorkflowJobs.Poll,
+		},
+		&cli.DurationFlag{
+			Name:        "collector.workflow_jobs.poll_interval",
+			Value:       5 * time.Minute,
+			Usage:       "Interval for polling workflow jobs from the API",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_JOBS_POLL_INTERVAL"),
+			Destination: &cfg.Target.WorkflowJobs.PollInterval,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.check_runs",
+			Value:       false,
+			Usage:       "Enable collector for check suites and check runs",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_COLLECTOR_CHECK_RUNS"),
+			Destination: &cfg.Collector.CheckRuns,
+		},
+		&cli.DurationFlag{
+			Name:        "collector.check_runs.window",
+			Value:       24 * time.Hour,
+			Usage:       "History window for querying check runs",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_CHECK_RUNS_WINDOW"),
+			Destination: &cfg.Target.CheckRuns.Window,
+		},
+		&cli.DurationFlag{
+			Name:        "collector.check_runs.purge_window",
+			Value:       24 * time.Hour,
+			Usage:       "History window for keeping data in database. Defaults to the query window",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_CHECK_RUNS_PURGE_WINDOW"),
+			Destination: &cfg.Target.CheckRuns.PurgeWindow,
+		},
+		&cli.StringSliceFlag{
+			Name:        "collector.check_runs.labels",
+			Value:       config.CheckRunLabels(),
+			Usage:       "List of labels used for check runs",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_CHECK_RUNS_LABELS"),
+			Destination: &cfg.Target.CheckRuns.Labels,
+		},
+		&cli.StringSliceFlag{
+			Name:        "collector.check_runs.suite_labels",
+			Value:       config.CheckSuiteLabels(),
+			Usage:       "List of labels used for check suites",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_CHECK_RUNS_SUITE_LABELS"),
+			Destination: &cfg.Target.CheckRuns.SuiteLabels,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.pull_requests",
+			Value:       false,
+			Usage:       "Enable collector for pull requests",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_COLLECTOR_PULL_REQUESTS"),
+			Destination: &cfg.Collector.PullRequests,
+		},
+		&cli.DurationFlag{
+			Name:        "collector.pull_requests.window",
+			Value:       7 * 24 * time.Hour,
+			Usage:       "History window for querying closed pull requests",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_PULL_REQUESTS_WINDOW"),
+			Destination: &cfg.Target.PullRequests.Window,
+		},
+		&cli.DurationFlag{
+			Name:        "collector.pull_requests.purge_window",
+			Value:       7 * 24 * time.Hour,
+			Usage:       "History window for keeping closed pull requests in database. Defaults to the query window",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_PULL_REQUESTS_PURGE_WINDOW"),
+			Destination: &cfg.Target.PullRequests.PurgeWindow,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.deployments",
+			Value:       false,
+			Usage:       "Enable collector for deployments",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_COLLECTOR_DEPLOYMENTS"),
+			Destination: &cfg.Collector.Deployments,
+		},
+		&cli.DurationFlag{
+			Name:        "collector.deployments.window",
+			Value:       7 * 24 * time.Hour,
+			Usage:       "History window for querying deployments",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_DEPLOYMENTS_WINDOW"),
+			Destination: &cfg.Target.Deployments.Window,
+		},
+		&cli.DurationFlag{
+			Name:        "collector.deployments.purge_window",
+			Value:       7 * 24 * time.Hour,
+			Usage:       "History window for keeping data in database. Defaults to the query window",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_DEPLOYMENTS_PURGE_WINDOW"),
+			Destination: &cfg.Target.Deployments.PurgeWindow,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.workflow_summaries",
+			Value:       false,
+			Usage:       "Enable collector for workflow duration summaries",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_COLLECTOR_WORKFLOW_SUMMARIES"),
+			Destination: &cfg.Collector.Summaries,
+		},
+		&cli.StringSliceFlag{
+			Name:        "collector.workflow_summaries.windows",
+			Value:       []string{"1d", "7d", "30d"},
+			Usage:       "List of windows used for workflow duration summaries",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_SUMMARIES_WINDOWS"),
+			Destination: &cfg.Target.Summaries.Windows,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.workflow_health",
+			Value:       false,
+			Usage:       "Enable collector for the health of workflows on the default branch",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_COLLECTOR_WORKFLOW_HEALTH"),
+			Destination: &cfg.Collector.Health,
+		},
+		&cli.DurationFlag{
+			Name:        "collector.workflow_health.window",
+			Value:       7 * 24 * time.Hour,
+			Usage:       "History window for calculating the health of workflows",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_HEALTH_WINDOW"),
+			Destination: &cfg.Target.Health.Window,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.workflow_regressions",
+			Value:       false,
+			Usage:       "Enable collector for duration regressions of workflows",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_COLLECTOR_WORKFLOW_REGRESSIONS"),
+			Destination: &cfg.Collector.Regressions,
+		},
+		&cli.DurationFlag{
+			Name:        "collector.workflow_regressions.window",
+			Value:       7 * 24 * time.Hour,
+			Usage:       "History window for detecting duration regressions of workflows",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_REGRESSIONS_WINDOW"),
+			Destination: &cfg.Target.Regressions.Window,
+		},
+		&cli.IntFlag{
+			Name:        "collector.workflow_regressions.baseline",
+			Value:       10,
+			Usage:       "Number of previous runs used for the median baseline duration",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_REGRESSIONS_BASELINE"),
+			Destination: &cfg.Target.Regressions.Baseline,
+		},
+		&cli.FloatFlag{
+			Name:        "collector.workflow_regressions.factor",
+			Value:       1.5,
+			Usage:       "Factor of the baseline duration a run has to exceed to count as regression",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_WORKFLOW_REGRESSIONS_FACTOR"),
+			Destination: &cfg.Target.Regressions.Factor,
+		},
 		&cli.BoolFlag{
 			Name:        "collector.runners",
 			Value:       false,
@@ -370,5 +814,33 @@ func RootFlags(cfg *config.Config) []cli.Flag {
 			Sources:     cli.EnvVars("GITHUB_EXPORTER_RUNNERS_LABELS"),
 			Destination: &cfg.Target.Runners.Labels,
 		},
+		&cli.StringSliceFlag{
+			Name:        "collector.runners.relabel",
+			Value:       []string{},
+			Usage:       "List of relabel rules for runners like replace:name:gpu:gpu-.*",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_RUNNERS_RELABEL"),
+			Destination: &cfg.Target.Runners.Relabel,
+		},
+		&cli.IntFlag{
+			Name:        "collector.series_limit",
+			Value:       0,
+			Usage:       "Maximum number of series per collector, 0 disables the limit",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_COLLECTOR_SERIES_LIMIT"),
+			Destination: &cfg.Limits.Series,
+		},
+		&cli.StringSliceFlag{
+			Name:        "collector.series_limit.overrides",
+			Value:       []string{},
+			Usage:       "List of series limits per collector like workflow_job=5000",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_COLLECTOR_SERIES_LIMIT_OVERRIDES"),
+			Destination: &cfg.Limits.Overrides,
+		},
+		&cli.BoolFlag{
+			Name:        "collector.series_limit.overflow",
+			Value:       false,
+			Usage:       "Fold series beyond the limit into an overflow series instead of dropping them",
+			Sources:     cli.EnvVars("GITHUB_EXPORTER_COLLECTOR_SERIES_LIMIT_OVERFLOW"),
+			Destination: &cfg.Limits.Overflow,
+		},
 	}
 }
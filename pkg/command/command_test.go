@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v3"
+)
+
+func testRootFlags(t *testing.T, args ...string) *config.Config {
+	t.Helper()
+
+	cfg := config.Load()
+
+	cmd := &cli.Command{
+		Name:  "github_exporter",
+		Flags: RootFlags(cfg),
+		Action: func(_ context.Context, _ *cli.Command) error {
+			return nil
+		},
+	}
+
+	assert.NoError(t, cmd.Run(context.Background(), append([]string{"github_exporter"}, args...)))
+	return cfg
+}
+
+func TestWebhookSecretFlag(t *testing.T) {
+	cfg := testRootFlags(t, "--webhook.secret", "with,comma")
+
+	assert.Equal(t, "with,comma", cfg.Webhook.Secret)
+	assert.Empty(t, cfg.Webhook.Secrets)
+}
+
+func TestWebhookSecretEnv(t *testing.T) {
+	t.Setenv("GITHUB_EXPORTER_WEBHOOK_SECRET", "with,comma")
+	t.Setenv("GITHUB_EXPORTER_WEBHOOK_SECRETS", "previous,current")
+
+	cfg := testRootFlags(t)
+
+	assert.Equal(t, "with,comma", cfg.Webhook.Secret)
+	assert.Equal(t, []string{"previous", "current"}, cfg.Webhook.Secrets)
+}
@@ -48,5 +48,9 @@ func setupStorage(cfg *config.Config, logger *slog.Logger) (store.Store, error)
 		return nil, fmt.Errorf("failed to read dsn: %w", err)
 	}
 
+	if strings.HasPrefix(dsn, "memory://") {
+		logger.Warn("Using the memory store, all records get lost on restart")
+	}
+
 	return store.New(dsn, logger)
 }
@@ -4,6 +4,8 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,10 +19,58 @@ type Server struct {
 	Pprof   bool
 }
 
+// Queue defines the webhook queue specific configuration.
+type Queue struct {
+	Enabled bool
+	Size    int
+	Workers int
+	Retry   time.Duration
+}
+
 // Webhook defines the webhook specific configuration.
 type Webhook struct {
-	Path   string
-	Secret string
+	Path    string
+	Secret  string
+	Secrets []string
+	Queue   Queue
+}
+
+// Reconciler defines the reconciler specific configuration.
+type Reconciler struct {
+	Enabled   bool
+	Interval  time.Duration
+	Threshold time.Duration
+}
+
+// Limits defines the cardinality limits of the collectors.
+type Limits struct {
+	Series    int
+	Overrides []string
+	Overflow  bool
+}
+
+// Collector returns the series limit for a collector, overrides are defined like
+// workflow_job=5000 and take precedence over the default limit.
+func (l Limits) Collector(name string) (int, error) {
+	for _, override := range l.Overrides {
+		key, val, ok := strings.Cut(override, "=")
+
+		if !ok {
+			return 0, fmt.Errorf("invalid series limit %q", override)
+		}
+
+		limit, err := strconv.Atoi(val)
+
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse series limit: %w", err)
+		}
+
+		if key == name {
+			return limit, nil
+		}
+	}
+
+	return l.Series, nil
 }
 
 // Logs defines the level and color for log configuration.
@@ -31,21 +81,74 @@ type Logs struct {
 
 // WorkflowRuns defines the workflow run specific configuration.
 type WorkflowRuns struct {
-	Window      time.Duration
-	PurgeWindow time.Duration
-	Labels      []string
+	Window          time.Duration
+	PurgeWindow     time.Duration
+	Labels          []string
+	Relabel         []string
+	Status          bool
+	State           bool
+	Aggregate       bool
+	Poll            bool
+	PollInterval    time.Duration
+	Resolve         bool
+	ResolveInterval time.Duration
 }
 
 // WorkflowJobs defines the workflow job specific configuration.
 type WorkflowJobs struct {
+	Window       time.Duration
+	PurgeWindow  time.Duration
+	Labels       []string
+	Relabel      []string
+	Aggregate    bool
+	Steps        bool
+	Flaky        bool
+	FlakyWindow  time.Duration
+	Poll         bool
+	PollInterval time.Duration
+}
+
+// CheckRuns defines the check run specific configuration.
+type CheckRuns struct {
 	Window      time.Duration
 	PurgeWindow time.Duration
 	Labels      []string
+	SuiteLabels []string
+}
+
+// PullRequests defines the pull request specific configuration.
+type PullRequests struct {
+	Window      time.Duration
+	PurgeWindow time.Duration
+}
+
+// Deployments defines the deployment specific configuration.
+type Deployments struct {
+	Window      time.Duration
+	PurgeWindow time.Duration
+}
+
+// WorkflowSummaries defines the workflow summary specific configuration.
+type WorkflowSummaries struct {
+	Windows []string
+}
+
+// WorkflowHealth defines the workflow health specific configuration.
+type WorkflowHealth struct {
+	Window time.Duration
+}
+
+// WorkflowRegressions defines the workflow regression specific configuration.
+type WorkflowRegressions struct {
+	Window   time.Duration
+	Baseline int
+	Factor   float64
 }
 
 // Runners defines the runner specific configuration.
 type Runners struct {
-	Labels []string
+	Labels  []string
+	Relabel []string
 }
 
 // Target defines the target specific configuration.
@@ -63,6 +166,12 @@ type Target struct {
 	PerPage      int
 	WorkflowRuns WorkflowRuns
 	WorkflowJobs WorkflowJobs
+	CheckRuns    CheckRuns
+	PullRequests PullRequests
+	Deployments  Deployments
+	Summaries    WorkflowSummaries
+	Health       WorkflowHealth
+	Regressions  WorkflowRegressions
 	Runners      Runners
 }
 
@@ -74,6 +183,12 @@ type Collector struct {
 	Billing      bool
 	WorkflowRuns bool
 	WorkflowJobs bool
+	CheckRuns    bool
+	PullRequests bool
+	Deployments  bool
+	Summaries    bool
+	Health       bool
+	Regressions  bool
 	Runners      bool
 }
 
@@ -84,12 +199,14 @@ type Database struct {
 
 // Config is a combination of all available configurations.
 type Config struct {
-	Server    Server
-	Webhook   Webhook
-	Logs      Logs
-	Target    Target
-	Collector Collector
-	Database  Database
+	Server     Server
+	Webhook    Webhook
+	Logs       Logs
+	Target     Target
+	Collector  Collector
+	Reconciler Reconciler
+	Limits     Limits
+	Database   Database
 }
 
 // Load initializes a default configuration struct.
@@ -134,6 +251,32 @@ func JobLabels() []string {
 	}
 }
 
+// CheckRunLabels defines the default labels used by check run collector.
+func CheckRunLabels() []string {
+	return []string{
+		"owner",
+		"repo",
+		"name",
+		"app",
+		"branch",
+		"identifier",
+		"suite_id",
+		"conclusion",
+	}
+}
+
+// CheckSuiteLabels defines the default labels used by check suite collector.
+func CheckSuiteLabels() []string {
+	return []string{
+		"owner",
+		"repo",
+		"app",
+		"branch",
+		"identifier",
+		"conclusion",
+	}
+}
+
 // RunnerLabels defines the default labels used by runner collector.
 func RunnerLabels() []string {
 	return []string{
@@ -145,6 +288,76 @@ func RunnerLabels() []string {
 	}
 }
 
+// Relabel defines a single rule to rewrite or drop records by label values.
+type Relabel struct {
+	Action      string
+	Label       string
+	Replacement string
+	Regex       *regexp.Regexp
+}
+
+// Relabels parses rules defined like replace:branch:feature:feature/.* or
+// drop:repo:sandbox-.*, the regex always matches the whole label value.
+func Relabels(vals []string) ([]Relabel, error) {
+	result := make([]Relabel, 0, len(vals))
+
+	for _, val := range vals {
+		parts := strings.SplitN(val, ":", 3)
+
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid relabel rule %q", val)
+		}
+
+		rule := Relabel{
+			Action: parts[0],
+			Label:  parts[1],
+		}
+
+		expr := parts[2]
+
+		switch rule.Action {
+		case "replace":
+			replacement, rest, ok := strings.Cut(expr, ":")
+
+			if !ok {
+				return nil, fmt.Errorf("missing replacement for relabel rule %q", val)
+			}
+
+			rule.Replacement = replacement
+			expr = rest
+		case "drop", "keep":
+		default:
+			return nil, fmt.Errorf("invalid action for relabel rule %q", val)
+		}
+
+		regex, err := regexp.Compile("^(?:" + expr + ")$")
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse relabel rule: %w", err)
+		}
+
+		rule.Regex = regex
+		result = append(result, rule)
+	}
+
+	return result, nil
+}
+
+// Window parses a duration which additionally supports a day suffix like 7d.
+func Window(val string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(val, "d"); ok {
+		num, err := strconv.Atoi(days)
+
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse window: %w", err)
+		}
+
+		return time.Duration(num) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(val)
+}
+
 // Value returns the config value based on a DSN.
 func Value(val string) (string, error) {
 	if strings.HasPrefix(val, "file://") {
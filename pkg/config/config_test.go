@@ -0,0 +1,103 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestRelabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		vals    []string
+		want    []Relabel
+		invalid bool
+	}{
+		{
+			name: "no rules",
+			vals: []string{},
+			want: []Relabel{},
+		},
+		{
+			name: "replace rule",
+			vals: []string{"replace:branch:feature:feature/.*"},
+			want: []Relabel{{Action: "replace", Label: "branch", Replacement: "feature"}},
+		},
+		{
+			name: "drop and keep rules",
+			vals: []string{"drop:repo:sandbox-.*", "keep:owner:promhippie"},
+			want: []Relabel{{Action: "drop", Label: "repo"}, {Action: "keep", Label: "owner"}},
+		},
+		{
+			name: "regex containing colons",
+			vals: []string{"drop:name:build:.*"},
+			want: []Relabel{{Action: "drop", Label: "name"}},
+		},
+		{
+			name:    "missing regex",
+			vals:    []string{"drop:repo"},
+			invalid: true,
+		},
+		{
+			name:    "missing replacement",
+			vals:    []string{"replace:branch:feature"},
+			invalid: true,
+		},
+		{
+			name:    "unknown action",
+			vals:    []string{"rename:branch:main"},
+			invalid: true,
+		},
+		{
+			name:    "invalid regex",
+			vals:    []string{"drop:repo:sandbox-("},
+			invalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Relabels(tt.vals)
+
+			if tt.invalid {
+				if err == nil {
+					t.Errorf("Expected an error for %v", tt.vals)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d rules, got %d", len(tt.want), len(got))
+			}
+
+			for i, rule := range got {
+				if rule.Action != tt.want[i].Action || rule.Label != tt.want[i].Label || rule.Replacement != tt.want[i].Replacement {
+					t.Errorf("Expected rule %+v, got %+v", tt.want[i], rule)
+				}
+			}
+		})
+	}
+}
+
+func TestRelabelsAnchored(t *testing.T) {
+	rules, err := Relabels([]string{"drop:name:build:.*"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for val, want := range map[string]bool{
+		"build:linux":     true,
+		"build:":          true,
+		"prebuild:linux":  false,
+		"build":           false,
+		"test:build:fast": false,
+	} {
+		if got := rules[0].Regex.MatchString(val); got != want {
+			t.Errorf("Expected match of %q to be %v, got %v", val, want, got)
+		}
+	}
+}
@@ -382,7 +382,7 @@ func (c *AdminCollector) Collect(ch chan<- prometheus.Metric) {
 	now := time.Now()
 	record, resp, err := c.client.Admin.GetAdminStats(ctx)
 	c.duration.WithLabelValues("admin").Observe(time.Since(now).Seconds())
-	defer closeBody(resp)
+	defer CloseBody(resp)
 
 	if err != nil {
 		c.logger.Error("Failed to fetch admin stats",
@@ -370,7 +370,7 @@ func (c *BillingCollector) getActionBilling() []*actionBilling {
 			continue
 		}
 
-		defer closeBody(resp)
+		defer CloseBody(resp)
 
 		result = append(result, &actionBilling{
 			Type:          "enterprise",
@@ -381,7 +381,7 @@ func (c *BillingCollector) getActionBilling() []*actionBilling {
 
 	for _, name := range c.config.Orgs {
 		record, resp, err := c.client.Billing.GetActionsBillingOrg(ctx, name)
-		defer closeBody(resp)
+		defer CloseBody(resp)
 
 		if err != nil {
 			c.logger.Error("Failed to fetch action billing",
@@ -448,7 +448,7 @@ func (c *BillingCollector) getPackageBilling() []*packageBilling {
 			continue
 		}
 
-		defer closeBody(resp)
+		defer CloseBody(resp)
 
 		result = append(result, &packageBilling{
 			Type:           "enterprise",
@@ -462,7 +462,7 @@ func (c *BillingCollector) getPackageBilling() []*packageBilling {
 		defer cancel()
 
 		record, resp, err := c.client.Billing.GetPackagesBillingOrg(ctx, name)
-		defer closeBody(resp)
+		defer CloseBody(resp)
 
 		if err != nil {
 			c.logger.Error("Failed to fetch package billing",
@@ -529,7 +529,7 @@ func (c *BillingCollector) getStorageBilling() []*storageBilling {
 			continue
 		}
 
-		defer closeBody(resp)
+		defer CloseBody(resp)
 
 		result = append(result, &storageBilling{
 			Type:           "enterprise",
@@ -543,7 +543,7 @@ func (c *BillingCollector) getStorageBilling() []*storageBilling {
 		defer cancel()
 
 		record, resp, err := c.client.Billing.GetStorageBillingOrg(ctx, name)
-		defer closeBody(resp)
+		defer CloseBody(resp)
 
 		if err != nil {
 			c.logger.Error("Failed to fetch storage billing",
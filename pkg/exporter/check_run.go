@@ -0,0 +1,259 @@
+package exporter
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+// CheckRunCollector collects metrics about check suites and check runs.
+type CheckRunCollector struct {
+	client   *github.Client
+	logger   *slog.Logger
+	db       store.Store
+	failures *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	config   config.Target
+
+	Status        *prometheus.Desc
+	Duration      *prometheus.Desc
+	Creation      *prometheus.Desc
+	Started       *prometheus.Desc
+	Completed     *prometheus.Desc
+	SuiteStatus   *prometheus.Desc
+	SuiteDuration *prometheus.Desc
+}
+
+// NewCheckRunCollector returns a new CheckRunCollector.
+func NewCheckRunCollector(logger *slog.Logger, client *github.Client, db store.Store, failures *prometheus.CounterVec, duration *prometheus.HistogramVec, cfg config.Target) *CheckRunCollector {
+	if failures != nil {
+		failures.WithLabelValues("check_run").Add(0)
+	}
+
+	labels := cfg.CheckRuns.Labels
+	suiteLabels := cfg.CheckRuns.SuiteLabels
+	return &CheckRunCollector{
+		client:   client,
+		logger:   logger.With("collector", "check_run"),
+		db:       db,
+		failures: failures,
+		duration: duration,
+		config:   cfg,
+
+		Status: prometheus.NewDesc(
+			"github_check_run_status",
+			"Status of check runs",
+			labels,
+			nil,
+		),
+		Duration: prometheus.NewDesc(
+			"github_check_run_duration_ms",
+			"Duration of check runs",
+			labels,
+			nil,
+		),
+		Creation: prometheus.NewDesc(
+			"github_check_run_duration_run_started_minutes",
+			"Duration since the check run start time in minutes",
+			labels,
+			nil,
+		),
+		Started: prometheus.NewDesc(
+			"github_check_run_started_timestamp",
+			"Timestamp when the check run have been started",
+			labels,
+			nil,
+		),
+		Completed: prometheus.NewDesc(
+			"github_check_run_completed_timestamp",
+			"Timestamp when the check run have been completed",
+			labels,
+			nil,
+		),
+		SuiteStatus: prometheus.NewDesc(
+			"github_check_suite_status",
+			"Status of check suites",
+			suiteLabels,
+			nil,
+		),
+		SuiteDuration: prometheus.NewDesc(
+			"github_check_suite_duration_ms",
+			"Duration of check suites",
+			suiteLabels,
+			nil,
+		),
+	}
+}
+
+// Metrics simply returns the list metric descriptors for generating a documentation.
+func (c *CheckRunCollector) Metrics() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		c.Status,
+		c.Duration,
+		c.Creation,
+		c.Started,
+		c.Completed,
+		c.SuiteStatus,
+		c.SuiteDuration,
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics collected by this Collector.
+func (c *CheckRunCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Status
+	ch <- c.Duration
+	ch <- c.Creation
+	ch <- c.Started
+	ch <- c.Completed
+	ch <- c.SuiteStatus
+	ch <- c.SuiteDuration
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *CheckRunCollector) Collect(ch chan<- prometheus.Metric) {
+	if err := c.db.PruneCheckSuites(
+		c.config.CheckRuns.PurgeWindow,
+	); err != nil {
+		c.logger.Error("Failed to prune check suites",
+			"err", err,
+		)
+	}
+
+	if err := c.db.PruneCheckRuns(
+		c.config.CheckRuns.PurgeWindow,
+	); err != nil {
+		c.logger.Error("Failed to prune check runs",
+			"err", err,
+		)
+	}
+
+	{
+		now := time.Now()
+		records, err := c.db.GetCheckSuites(c.config.CheckRuns.Window)
+		c.duration.WithLabelValues("check_run").Observe(time.Since(now).Seconds())
+
+		if err != nil {
+			c.logger.Error("Failed to fetch check suites",
+				"err", err,
+			)
+
+			c.failures.WithLabelValues("check_run").Inc()
+		} else {
+			c.logger.Debug("Fetched check suites",
+				"count", len(records),
+				"duration", time.Since(now),
+			)
+
+			for _, record := range records {
+				c.logger.Debug("Collecting check suite",
+					"owner", record.Owner,
+					"repo", record.Repo,
+					"id", record.Identifier,
+					"app", record.App,
+				)
+
+				labels := []string{}
+
+				for _, label := range c.config.CheckRuns.SuiteLabels {
+					labels = append(
+						labels,
+						record.ByLabel(label),
+					)
+				}
+
+				ch <- prometheus.MustNewConstMetric(
+					c.SuiteStatus,
+					prometheus.GaugeValue,
+					jobStatusToGauge(record.Status),
+					labels...,
+				)
+
+				ch <- prometheus.MustNewConstMetric(
+					c.SuiteDuration,
+					prometheus.GaugeValue,
+					float64((record.UpdatedAt-record.CreatedAt)*1000),
+					labels...,
+				)
+			}
+		}
+	}
+
+	{
+		now := time.Now()
+		records, err := c.db.GetCheckRuns(c.config.CheckRuns.Window)
+		c.duration.WithLabelValues("check_run").Observe(time.Since(now).Seconds())
+
+		if err != nil {
+			c.logger.Error("Failed to fetch check runs",
+				"err", err,
+			)
+
+			c.failures.WithLabelValues("check_run").Inc()
+			return
+		}
+
+		c.logger.Debug("Fetched check runs",
+			"count", len(records),
+			"duration", time.Since(now),
+		)
+
+		for _, record := range records {
+			c.logger.Debug("Collecting check run",
+				"owner", record.Owner,
+				"repo", record.Repo,
+				"id", record.Identifier,
+				"app", record.App,
+			)
+
+			labels := []string{}
+
+			for _, label := range c.config.CheckRuns.Labels {
+				labels = append(
+					labels,
+					record.ByLabel(label),
+				)
+			}
+
+			ch <- prometheus.MustNewConstMetric(
+				c.Status,
+				prometheus.GaugeValue,
+				jobStatusToGauge(record.Status),
+				labels...,
+			)
+
+			if record.CompletedAt > 0 {
+				ch <- prometheus.MustNewConstMetric(
+					c.Duration,
+					prometheus.GaugeValue,
+					float64((record.CompletedAt-record.StartedAt)*1000),
+					labels...,
+				)
+			}
+
+			ch <- prometheus.MustNewConstMetric(
+				c.Creation,
+				prometheus.GaugeValue,
+				time.Since(time.Unix(record.StartedAt, 0)).Minutes(),
+				labels...,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.Started,
+				prometheus.GaugeValue,
+				float64(record.StartedAt),
+				labels...,
+			)
+
+			ch <- prometheus.MustNewConstMetric(
+				c.Completed,
+				prometheus.GaugeValue,
+				float64(record.CompletedAt),
+				labels...,
+			)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package exporter
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+func TestCheckRunCollectorSuiteLabels(t *testing.T) {
+	mockLogger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		}),
+	)
+
+	mockStore, err := store.New("memory://", mockLogger)
+
+	if err != nil {
+		t.Fatalf("Failed to setup store: %v", err)
+	}
+
+	now := time.Now()
+
+	if err := mockStore.StoreCheckSuiteEvent(&github.CheckSuiteEvent{
+		Repo: &github.Repository{
+			Name: github.Ptr("repo"),
+			Owner: &github.User{
+				Login: github.Ptr("owner"),
+			},
+		},
+		CheckSuite: &github.CheckSuite{
+			ID:         github.Ptr(int64(1)),
+			App:        &github.App{Slug: github.Ptr("buildkite")},
+			Status:     github.Ptr("completed"),
+			Conclusion: github.Ptr("success"),
+			HeadBranch: github.Ptr("main"),
+			HeadSHA:    github.Ptr("abc"),
+			CreatedAt:  &github.Timestamp{Time: now.Add(-time.Minute)},
+			UpdatedAt:  &github.Timestamp{Time: now},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to store check suite: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		labels []string
+		want   string
+	}{
+		{
+			name:   "defaults",
+			labels: config.CheckSuiteLabels(),
+			want:   `github_check_suite_status{app="buildkite",branch="main",conclusion="success",identifier="1",owner="owner",repo="repo"} 4`,
+		},
+		{
+			name:   "custom",
+			labels: []string{"owner", "repo", "app"},
+			want:   `github_check_suite_status{app="buildkite",owner="owner",repo="repo"} 4`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewCheckRunCollector(
+				mockLogger,
+				&github.Client{},
+				mockStore,
+				prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_failures_total"}, []string{"collector"}),
+				prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_duration_seconds"}, []string{"collector"}),
+				config.Target{
+					CheckRuns: config.CheckRuns{
+						Window:      time.Hour,
+						PurgeWindow: time.Hour,
+						Labels:      config.CheckRunLabels(),
+						SuiteLabels: tt.labels,
+					},
+				},
+			)
+
+			if err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP github_check_suite_status Status of check suites
+# TYPE github_check_suite_status gauge
+`+tt.want+`
+`), "github_check_suite_status"); err != nil {
+				t.Errorf("Unexpected check suite status: %v", err)
+			}
+		})
+	}
+}
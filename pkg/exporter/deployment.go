@@ -0,0 +1,205 @@
+package exporter
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+// DeploymentCollector collects DORA metrics based on deployments.
+type DeploymentCollector struct {
+	client   *github.Client
+	logger   *slog.Logger
+	db       store.Store
+	failures *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	config   config.Target
+
+	Successful  *prometheus.Desc
+	Failed      *prometheus.Desc
+	FailureRate *prometheus.Desc
+	LeadTime    *prometheus.Desc
+	Restore     *prometheus.Desc
+}
+
+// NewDeploymentCollector returns a new DeploymentCollector.
+func NewDeploymentCollector(logger *slog.Logger, client *github.Client, db store.Store, failures *prometheus.CounterVec, duration *prometheus.HistogramVec, cfg config.Target) *DeploymentCollector {
+	if failures != nil {
+		failures.WithLabelValues("deployment").Add(0)
+	}
+
+	labels := []string{"owner", "repo", "environment"}
+	return &DeploymentCollector{
+		client:   client,
+		logger:   logger.With("collector", "deployment"),
+		db:       db,
+		failures: failures,
+		duration: duration,
+		config:   cfg,
+
+		Successful: prometheus.NewDesc(
+			"github_deployment_successful",
+			"Number of successful deployments within the window",
+			labels,
+			nil,
+		),
+		Failed: prometheus.NewDesc(
+			"github_deployment_failed",
+			"Number of failed deployments within the window",
+			labels,
+			nil,
+		),
+		FailureRate: prometheus.NewDesc(
+			"github_deployment_change_failure_ratio",
+			"Ratio of failed deployments to finished deployments within the window",
+			labels,
+			nil,
+		),
+		LeadTime: prometheus.NewDesc(
+			"github_deployment_lead_time_seconds",
+			"Histogram of the time from the commit to its successful deployment",
+			labels,
+			nil,
+		),
+		Restore: prometheus.NewDesc(
+			"github_deployment_time_to_restore_seconds",
+			"Histogram of the time from a failed deployment to the next successful deployment",
+			labels,
+			nil,
+		),
+	}
+}
+
+// Metrics simply returns the list metric descriptors for generating a documentation.
+func (c *DeploymentCollector) Metrics() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		c.Successful,
+		c.Failed,
+		c.FailureRate,
+		c.LeadTime,
+		c.Restore,
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics collected by this Collector.
+func (c *DeploymentCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Successful
+	ch <- c.Failed
+	ch <- c.FailureRate
+	ch <- c.LeadTime
+	ch <- c.Restore
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *DeploymentCollector) Collect(ch chan<- prometheus.Metric) {
+	if err := c.db.PruneDeployments(
+		c.config.Deployments.PurgeWindow,
+	); err != nil {
+		c.logger.Error("Failed to prune deployments",
+			"err", err,
+		)
+	}
+
+	now := time.Now()
+	records, err := c.db.GetDeployments(c.config.Deployments.Window)
+	c.duration.WithLabelValues("deployment").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch deployments",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("deployment").Inc()
+		return
+	}
+
+	c.logger.Debug("Fetched deployments",
+		"count", len(records),
+		"duration", time.Since(now),
+	)
+
+	type dora struct {
+		labels     []string
+		successful float64
+		failed     float64
+	}
+
+	keys := make([]string, 0)
+	envs := make(map[string]*dora)
+
+	for _, record := range records {
+		key := record.Owner + "/" + record.Repo + ":" + record.Environment
+
+		if _, ok := envs[key]; !ok {
+			keys = append(keys, key)
+			envs[key] = &dora{
+				labels: []string{
+					record.Owner,
+					record.Repo,
+					record.Environment,
+				},
+			}
+		}
+
+		switch record.Status {
+		case "success":
+			envs[key].successful++
+		case "failure", "error":
+			envs[key].failed++
+		}
+	}
+
+	for _, key := range keys {
+		row := envs[key]
+
+		ch <- prometheus.MustNewConstMetric(
+			c.Successful,
+			prometheus.GaugeValue,
+			row.successful,
+			row.labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			c.Failed,
+			prometheus.GaugeValue,
+			row.failed,
+			row.labels...,
+		)
+
+		if finished := row.successful + row.failed; finished > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				c.FailureRate,
+				prometheus.GaugeValue,
+				row.failed/finished,
+				row.labels...,
+			)
+		}
+	}
+
+	c.collectDurations(ch, "deployment_lead_time", c.LeadTime)
+	c.collectDurations(ch, "deployment_restore", c.Restore)
+}
+
+func (c *DeploymentCollector) collectDurations(ch chan<- prometheus.Metric, kind string, desc *prometheus.Desc) {
+	now := time.Now()
+	records, err := c.db.GetDurationTotals(kind)
+	c.duration.WithLabelValues("deployment").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch deployment totals",
+			"kind", kind,
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("deployment").Inc()
+		return
+	}
+
+	collectDurations(ch, records, desc, func(values []string) ([]string, bool) {
+		return values, len(values) == 3
+	}, nil)
+}
@@ -0,0 +1,142 @@
+package exporter
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+func TestDeploymentCollectorCollect(t *testing.T) {
+	mockLogger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		}),
+	)
+
+	mockStore, err := store.New("memory://", mockLogger)
+
+	if err != nil {
+		t.Fatalf("Failed to setup store: %v", err)
+	}
+
+	now := time.Now()
+
+	repo := &github.Repository{
+		Name: github.Ptr("repo"),
+		Owner: &github.User{
+			Login: github.Ptr("owner"),
+		},
+	}
+
+	if err := mockStore.StoreWorkflowRunEvent(&github.WorkflowRunEvent{
+		Repo: repo,
+		WorkflowRun: &github.WorkflowRun{
+			ID:         github.Ptr(int64(1)),
+			WorkflowID: github.Ptr(int64(2)),
+			RunNumber:  github.Ptr(3),
+			RunAttempt: github.Ptr(1),
+			HeadSHA:    github.Ptr("abc"),
+			Status:     github.Ptr("completed"),
+			HeadCommit: &github.HeadCommit{
+				Timestamp: &github.Timestamp{Time: now.Add(-3 * time.Hour)},
+			},
+			CreatedAt: &github.Timestamp{Time: now.Add(-2 * time.Hour)},
+			UpdatedAt: &github.Timestamp{Time: now},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to store workflow run: %v", err)
+	}
+
+	status := func(id int64, sha, state string, createdAt time.Time) *github.DeploymentStatusEvent {
+		return &github.DeploymentStatusEvent{
+			Repo: repo,
+			Deployment: &github.Deployment{
+				ID:          github.Ptr(id),
+				SHA:         github.Ptr(sha),
+				Environment: github.Ptr("production"),
+				CreatedAt:   &github.Timestamp{Time: createdAt},
+				UpdatedAt:   &github.Timestamp{Time: createdAt},
+			},
+			DeploymentStatus: &github.DeploymentStatus{
+				State:     github.Ptr(state),
+				CreatedAt: &github.Timestamp{Time: createdAt},
+			},
+		}
+	}
+
+	for _, event := range []*github.DeploymentStatusEvent{
+		status(4, "def", "failure", now.Add(-2*time.Hour)),
+		status(5, "abc", "success", now.Add(-time.Hour)),
+	} {
+		if err := mockStore.StoreDeploymentStatusEvent(event); err != nil {
+			t.Fatalf("Failed to store deployment status: %v", err)
+		}
+	}
+
+	collector := NewDeploymentCollector(
+		mockLogger,
+		&github.Client{},
+		mockStore,
+		prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_failures_total"}, []string{"collector"}),
+		prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_duration_seconds"}, []string{"collector"}),
+		config.Target{
+			Deployments: config.Deployments{
+				Window:      3 * time.Hour,
+				PurgeWindow: 3 * time.Hour,
+			},
+		},
+	)
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP github_deployment_change_failure_ratio Ratio of failed deployments to finished deployments within the window
+# TYPE github_deployment_change_failure_ratio gauge
+github_deployment_change_failure_ratio{environment="production",owner="owner",repo="repo"} 0.5
+`), "github_deployment_change_failure_ratio"); err != nil {
+		t.Errorf("Unexpected change failure ratio: %v", err)
+	}
+
+	if err := mockStore.PruneDeployments(-time.Minute); err != nil {
+		t.Fatalf("Failed to prune deployments: %v", err)
+	}
+
+	if count := testutil.CollectAndCount(collector, "github_deployment_change_failure_ratio"); count != 0 {
+		t.Errorf("Expected pruned deployments, got %d", count)
+	}
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP github_deployment_lead_time_seconds Histogram of the time from the commit to its successful deployment
+# TYPE github_deployment_lead_time_seconds histogram
+github_deployment_lead_time_seconds_bucket{environment="production",owner="owner",repo="repo",le="600"} 0
+github_deployment_lead_time_seconds_bucket{environment="production",owner="owner",repo="repo",le="1800"} 0
+github_deployment_lead_time_seconds_bucket{environment="production",owner="owner",repo="repo",le="3600"} 0
+github_deployment_lead_time_seconds_bucket{environment="production",owner="owner",repo="repo",le="14400"} 1
+github_deployment_lead_time_seconds_bucket{environment="production",owner="owner",repo="repo",le="86400"} 1
+github_deployment_lead_time_seconds_bucket{environment="production",owner="owner",repo="repo",le="604800"} 1
+github_deployment_lead_time_seconds_bucket{environment="production",owner="owner",repo="repo",le="2.592e+06"} 1
+github_deployment_lead_time_seconds_bucket{environment="production",owner="owner",repo="repo",le="+Inf"} 1
+github_deployment_lead_time_seconds_sum{environment="production",owner="owner",repo="repo"} 7200
+github_deployment_lead_time_seconds_count{environment="production",owner="owner",repo="repo"} 1
+# HELP github_deployment_time_to_restore_seconds Histogram of the time from a failed deployment to the next successful deployment
+# TYPE github_deployment_time_to_restore_seconds histogram
+github_deployment_time_to_restore_seconds_bucket{environment="production",owner="owner",repo="repo",le="600"} 0
+github_deployment_time_to_restore_seconds_bucket{environment="production",owner="owner",repo="repo",le="1800"} 0
+github_deployment_time_to_restore_seconds_bucket{environment="production",owner="owner",repo="repo",le="3600"} 1
+github_deployment_time_to_restore_seconds_bucket{environment="production",owner="owner",repo="repo",le="14400"} 1
+github_deployment_time_to_restore_seconds_bucket{environment="production",owner="owner",repo="repo",le="86400"} 1
+github_deployment_time_to_restore_seconds_bucket{environment="production",owner="owner",repo="repo",le="604800"} 1
+github_deployment_time_to_restore_seconds_bucket{environment="production",owner="owner",repo="repo",le="2.592e+06"} 1
+github_deployment_time_to_restore_seconds_bucket{environment="production",owner="owner",repo="repo",le="+Inf"} 1
+github_deployment_time_to_restore_seconds_sum{environment="production",owner="owner",repo="repo"} 3600
+github_deployment_time_to_restore_seconds_count{environment="production",owner="owner",repo="repo"} 1
+`), "github_deployment_lead_time_seconds", "github_deployment_time_to_restore_seconds"); err != nil {
+		t.Errorf("Unexpected persisted deployment histograms: %v", err)
+	}
+}
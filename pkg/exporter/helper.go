@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/promhippie/github_exporter/pkg/store"
 )
 
-func closeBody(resp *github.Response) {
+// CloseBody closes the body of the response if there is any.
+func CloseBody(resp *github.Response) {
 	if resp != nil {
 		resp.Body.Close()
 	}
@@ -32,7 +36,22 @@ func boolToFloat64(val bool) float64 {
 	return 0.0
 }
 
-func reposByOwnerAndName(ctx context.Context, client *github.Client, owner, repo string, perPage int) ([]*github.Repository, error) {
+// stateSet maps all known states to 0 and the current state to 1, unknown
+// states get added to the set as well.
+func stateSet(states []string, current string) map[string]float64 {
+	result := make(map[string]float64, len(states)+1)
+
+	for _, state := range states {
+		result[state] = 0
+	}
+
+	result[current] = 1
+	return result
+}
+
+// ReposByOwnerAndName fetches a single repo, or all repos of the owner if the
+// name contains a wildcard.
+func ReposByOwnerAndName(ctx context.Context, client *github.Client, owner, repo string, perPage int) ([]*github.Repository, error) {
 	if strings.Contains(repo, "*") {
 		opts := &github.SearchOptions{
 			ListOptions: github.ListOptions{
@@ -52,7 +71,7 @@ func reposByOwnerAndName(ctx context.Context, client *github.Client, owner, repo
 			)
 
 			if err != nil {
-				closeBody(resp)
+				CloseBody(resp)
 				return nil, err
 			}
 
@@ -62,11 +81,11 @@ func reposByOwnerAndName(ctx context.Context, client *github.Client, owner, repo
 			)
 
 			if resp.NextPage == 0 {
-				closeBody(resp)
+				CloseBody(resp)
 				break
 			}
 
-			closeBody(resp)
+			CloseBody(resp)
 			opts.Page = resp.NextPage
 		}
 
@@ -83,3 +102,222 @@ func reposByOwnerAndName(ctx context.Context, client *github.Client, owner, repo
 		res,
 	}, nil
 }
+
+// ReposByOrg fetches all repos of the org.
+func ReposByOrg(ctx context.Context, client *github.Client, name string, perPage int) ([]*github.Repository, error) {
+	opts := &github.RepositoryListByOrgOptions{
+		ListOptions: github.ListOptions{
+			PerPage: perPage,
+		},
+	}
+
+	var (
+		repos []*github.Repository
+	)
+
+	for {
+		result, resp, err := client.Repositories.ListByOrg(
+			ctx,
+			name,
+			opts,
+		)
+
+		if err != nil {
+			CloseBody(resp)
+			return nil, err
+		}
+
+		repos = append(
+			repos,
+			result...,
+		)
+
+		if resp.NextPage == 0 {
+			CloseBody(resp)
+			break
+		}
+
+		CloseBody(resp)
+		opts.Page = resp.NextPage
+	}
+
+	return repos, nil
+}
+
+func collectTotals(ch chan<- prometheus.Metric, records []*store.WorkflowTotal, kind string, total, duration *prometheus.Desc, exemplars map[string][]prometheus.Exemplar) {
+	type aggregate struct {
+		labels  []string
+		count   uint64
+		sum     float64
+		buckets map[float64]uint64
+	}
+
+	keys := make([]string, 0)
+	aggregates := make(map[string]*aggregate)
+
+	for _, record := range records {
+		if record.Kind != kind {
+			continue
+		}
+
+		key := record.Owner + "/" + record.Repo + ":" + record.Workflow + ":" + record.Conclusion
+
+		if _, ok := aggregates[key]; !ok {
+			keys = append(keys, key)
+			aggregates[key] = &aggregate{
+				labels: []string{
+					record.Owner,
+					record.Repo,
+					record.Workflow,
+					record.Conclusion,
+				},
+				buckets: make(map[float64]uint64, len(store.TotalBuckets)),
+			}
+		}
+
+		row := aggregates[key]
+		row.count += uint64(record.Count)
+		row.sum += float64(record.Duration)
+
+		for _, bound := range store.TotalBuckets {
+			if _, ok := row.buckets[float64(bound)]; !ok {
+				row.buckets[float64(bound)] = 0
+			}
+
+			if record.Bucket != -1 && record.Bucket <= bound {
+				row.buckets[float64(bound)] += uint64(record.Count)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		row := aggregates[key]
+
+		ch <- withExemplars(
+			prometheus.MustNewConstMetric(
+				total,
+				prometheus.CounterValue,
+				float64(row.count),
+				row.labels...,
+			),
+			counterExemplars(exemplars[key]),
+		)
+
+		ch <- withExemplars(
+			prometheus.MustNewConstHistogram(
+				duration,
+				row.count,
+				row.sum,
+				row.buckets,
+				row.labels...,
+			),
+			exemplars[key],
+		)
+	}
+}
+
+// collectDurations builds histograms from the persisted duration buckets. The
+// labels function maps the stored label values to the exposed ones, buckets
+// get dropped if it returns false and merged if they share the same labels.
+func collectDurations(ch chan<- prometheus.Metric, records []*store.DurationTotal, desc *prometheus.Desc, labels func([]string) ([]string, bool), exemplars map[string][]prometheus.Exemplar) {
+	type aggregate struct {
+		labels  []string
+		count   uint64
+		sum     float64
+		buckets map[float64]uint64
+	}
+
+	keys := make([]string, 0)
+	aggregates := make(map[string]*aggregate)
+
+	for _, record := range records {
+		values, ok := labels(record.Values())
+
+		if !ok {
+			continue
+		}
+
+		key := strings.Join(values, "\x00")
+		bounds := store.DurationBuckets[record.Kind]
+
+		if _, ok := aggregates[key]; !ok {
+			keys = append(keys, key)
+			aggregates[key] = &aggregate{
+				labels:  values,
+				buckets: make(map[float64]uint64, len(bounds)),
+			}
+
+			for _, bound := range bounds {
+				aggregates[key].buckets[float64(bound)] = 0
+			}
+		}
+
+		row := aggregates[key]
+		row.count += uint64(record.Count)
+		row.sum += float64(record.Duration)
+
+		for _, bound := range bounds {
+			if record.Bucket != -1 && record.Bucket <= bound {
+				row.buckets[float64(bound)] += uint64(record.Count)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		row := aggregates[key]
+
+		ch <- withExemplars(
+			prometheus.MustNewConstHistogram(
+				desc,
+				row.count,
+				row.sum,
+				row.buckets,
+				row.labels...,
+			),
+			exemplars[key],
+		)
+	}
+}
+
+// urlExemplar builds an exemplar linking a sample to the run or job on GitHub.
+func urlExemplar(value float64, url string, timestamp int64) prometheus.Exemplar {
+	return prometheus.Exemplar{
+		Value: value,
+		Labels: prometheus.Labels{
+			"url": url,
+		},
+		Timestamp: time.Unix(timestamp, 0),
+	}
+}
+
+// counterExemplars reduces the exemplars to the latest one counting a single
+// occurrence, counters are only able to carry one exemplar.
+func counterExemplars(exemplars []prometheus.Exemplar) []prometheus.Exemplar {
+	if len(exemplars) == 0 {
+		return nil
+	}
+
+	latest := exemplars[len(exemplars)-1]
+	latest.Value = 1
+
+	return []prometheus.Exemplar{
+		latest,
+	}
+}
+
+// withExemplars attaches the exemplars to the metric. They are only exposed if
+// the scrape negotiates OpenMetrics, invalid exemplars like too long URLs are
+// skipped and the plain metric gets returned.
+func withExemplars(metric prometheus.Metric, exemplars []prometheus.Exemplar) prometheus.Metric {
+	if len(exemplars) == 0 {
+		return metric
+	}
+
+	result, err := prometheus.NewMetricWithExemplars(metric, exemplars...)
+
+	if err != nil {
+		return metric
+	}
+
+	return result
+}
@@ -0,0 +1,268 @@
+package exporter
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	// overflowLabel defines the label value used for folded series.
+	overflowLabel = "__overflow__"
+)
+
+// LimitCollector bounds the number of series of another collector.
+type LimitCollector struct {
+	collector prometheus.Collector
+	logger    *slog.Logger
+	name      string
+	dropped   *prometheus.CounterVec
+	limit     int
+	overflow  bool
+}
+
+// NewLimitCollector returns a new LimitCollector.
+func NewLimitCollector(logger *slog.Logger, collector prometheus.Collector, name string, dropped *prometheus.CounterVec, limit int, overflow bool) *LimitCollector {
+	if dropped != nil {
+		dropped.WithLabelValues(name).Add(0)
+	}
+
+	return &LimitCollector{
+		collector: collector,
+		logger:    logger.With("collector", name),
+		name:      name,
+		dropped:   dropped,
+		limit:     limit,
+		overflow:  overflow,
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics collected by this Collector.
+func (c *LimitCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.collector.Describe(ch)
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *LimitCollector) Collect(ch chan<- prometheus.Metric) {
+	metrics := make(chan prometheus.Metric)
+
+	go func() {
+		c.collector.Collect(metrics)
+		close(metrics)
+	}()
+
+	keys := make([]*prometheus.Desc, 0)
+	series := make(map[*prometheus.Desc][]prometheus.Metric)
+
+	for metric := range metrics {
+		desc := metric.Desc()
+
+		if _, ok := series[desc]; !ok {
+			keys = append(keys, desc)
+		}
+
+		series[desc] = append(series[desc], metric)
+	}
+
+	sizes := make([]int, 0, len(keys))
+
+	for _, desc := range keys {
+		sizes = append(sizes, len(series[desc]))
+	}
+
+	allowed := allocate(sizes, c.limit)
+
+	for i, desc := range keys {
+		records := series[desc]
+
+		// The collectors are emitting records ordered by creation, so we are
+		// keeping the latest series and drop or fold the oldest ones. The
+		// folded series counts against the limit as well.
+		if allowed[i] < len(records) {
+			keep := allowed[i]
+
+			if c.overflow && keep > 0 {
+				keep--
+			}
+
+			excess := records[:len(records)-keep]
+			records = records[len(records)-keep:]
+
+			c.logger.Debug("Exceeded series limit",
+				"desc", desc.String(),
+				"limit", c.limit,
+				"excess", len(excess),
+			)
+
+			c.dropped.WithLabelValues(c.name).Add(float64(len(excess)))
+
+			if c.overflow && allowed[i] > 0 {
+				if folded := c.fold(desc, excess); folded != nil {
+					ch <- folded
+				}
+			}
+		}
+
+		for _, metric := range records {
+			ch <- metric
+		}
+	}
+}
+
+// allocate distributes the limit of the collector across its metrics. Metrics
+// with the most series get cut first, that way aggregated metrics with a few
+// series stay untouched while the metrics per record all lose the same oldest
+// records. A limit of 0 disables the limit.
+func allocate(sizes []int, limit int) []int {
+	result := slices.Clone(sizes)
+
+	if limit <= 0 {
+		return result
+	}
+
+	total := 0
+
+	for _, size := range sizes {
+		total += size
+	}
+
+	if total <= limit {
+		return result
+	}
+
+	// Find the largest number of series per metric which still fits into the
+	// limit, this is bound by the largest metric.
+	capped := func(bound int) int {
+		sum := 0
+
+		for _, size := range sizes {
+			sum += min(size, bound)
+		}
+
+		return sum
+	}
+
+	lower, upper := 0, slices.Max(sizes)
+
+	for lower < upper {
+		mid := (lower + upper + 1) / 2
+
+		if capped(mid) <= limit {
+			lower = mid
+		} else {
+			upper = mid - 1
+		}
+	}
+
+	remaining := limit - capped(lower)
+
+	for i, size := range sizes {
+		result[i] = min(size, lower)
+
+		if size > lower && remaining > 0 {
+			result[i]++
+			remaining--
+		}
+	}
+
+	return result
+}
+
+// fold merges the series into a single one where every label is set to the
+// overflow value. Counters, histograms and summaries get summed up, while
+// gauges and untyped metrics use the maximum value, a sum of values like
+// timestamps or ratios wouldn't make any sense.
+func (c *LimitCollector) fold(desc *prometheus.Desc, metrics []prometheus.Metric) prometheus.Metric {
+	var (
+		labels    []string
+		valueType prometheus.ValueType
+		value     float64
+		count     uint64
+		sum       float64
+		buckets   = make(map[float64]uint64)
+		histogram bool
+		summary   bool
+	)
+
+	for _, metric := range metrics {
+		record := &dto.Metric{}
+
+		if err := metric.Write(record); err != nil {
+			c.logger.Error("Failed to fold series",
+				"err", err,
+			)
+
+			continue
+		}
+
+		if labels == nil {
+			labels = make([]string, len(record.GetLabel()))
+
+			for i := range labels {
+				labels[i] = overflowLabel
+			}
+		}
+
+		switch {
+		case record.Counter != nil:
+			valueType = prometheus.CounterValue
+			value += record.GetCounter().GetValue()
+		case record.Gauge != nil:
+			if valueType != prometheus.GaugeValue || record.GetGauge().GetValue() > value {
+				value = record.GetGauge().GetValue()
+			}
+
+			valueType = prometheus.GaugeValue
+		case record.Untyped != nil:
+			if valueType != prometheus.UntypedValue || record.GetUntyped().GetValue() > value {
+				value = record.GetUntyped().GetValue()
+			}
+
+			valueType = prometheus.UntypedValue
+		case record.Histogram != nil:
+			histogram = true
+			count += record.GetHistogram().GetSampleCount()
+			sum += record.GetHistogram().GetSampleSum()
+
+			for _, bucket := range record.GetHistogram().GetBucket() {
+				buckets[bucket.GetUpperBound()] += bucket.GetCumulativeCount()
+			}
+		case record.Summary != nil:
+			summary = true
+			count += record.GetSummary().GetSampleCount()
+			sum += record.GetSummary().GetSampleSum()
+		}
+	}
+
+	if labels == nil {
+		return nil
+	}
+
+	switch {
+	case histogram:
+		return prometheus.MustNewConstHistogram(
+			desc,
+			count,
+			sum,
+			buckets,
+			labels...,
+		)
+	case summary:
+		return prometheus.MustNewConstSummary(
+			desc,
+			count,
+			sum,
+			nil,
+			labels...,
+		)
+	}
+
+	return prometheus.MustNewConstMetric(
+		desc,
+		valueType,
+		value,
+		labels...,
+	)
+}
@@ -0,0 +1,285 @@
+package exporter
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+type limitTestCollector struct {
+	metrics []prometheus.Metric
+}
+
+func (c *limitTestCollector) Describe(_ chan<- *prometheus.Desc) {}
+
+func (c *limitTestCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []int
+		limit int
+		want  []int
+	}{
+		{
+			name:  "disabled",
+			sizes: []int{5, 5},
+			limit: 0,
+			want:  []int{5, 5},
+		},
+		{
+			name:  "below limit",
+			sizes: []int{2, 3},
+			limit: 5,
+			want:  []int{2, 3},
+		},
+		{
+			name:  "equal records",
+			sizes: []int{5, 5, 5},
+			limit: 9,
+			want:  []int{3, 3, 3},
+		},
+		{
+			name:  "aggregates untouched",
+			sizes: []int{1, 10, 10},
+			limit: 11,
+			want:  []int{1, 5, 5},
+		},
+		{
+			name:  "remainder in order",
+			sizes: []int{10, 10, 10},
+			limit: 20,
+			want:  []int{7, 7, 6},
+		},
+		{
+			name:  "limit below metrics",
+			sizes: []int{3, 3, 3},
+			limit: 2,
+			want:  []int{1, 1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allocate(tt.sizes, tt.limit); !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLimitCollectorFold(t *testing.T) {
+	desc := prometheus.NewDesc("test_metric", "Test metric", []string{"name"}, nil)
+
+	collector := NewLimitCollector(
+		slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		&limitTestCollector{},
+		"test",
+		nil,
+		0,
+		true,
+	)
+
+	tests := []struct {
+		name    string
+		metrics []prometheus.Metric
+		check   func(*testing.T, *dto.Metric)
+	}{
+		{
+			name: "counters get summed",
+			metrics: []prometheus.Metric{
+				prometheus.MustNewConstMetric(desc, prometheus.CounterValue, 2, "a"),
+				prometheus.MustNewConstMetric(desc, prometheus.CounterValue, 3, "b"),
+			},
+			check: func(t *testing.T, m *dto.Metric) {
+				if got := m.GetCounter().GetValue(); got != 5 {
+					t.Errorf("Expected sum of 5, got %v", got)
+				}
+			},
+		},
+		{
+			name: "gauges use maximum",
+			metrics: []prometheus.Metric{
+				prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, -2, "a"),
+				prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 7, "b"),
+				prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 3, "c"),
+			},
+			check: func(t *testing.T, m *dto.Metric) {
+				if got := m.GetGauge().GetValue(); got != 7 {
+					t.Errorf("Expected maximum of 7, got %v", got)
+				}
+			},
+		},
+		{
+			name: "negative gauges use maximum",
+			metrics: []prometheus.Metric{
+				prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, -5, "a"),
+				prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, -2, "b"),
+			},
+			check: func(t *testing.T, m *dto.Metric) {
+				if got := m.GetGauge().GetValue(); got != -2 {
+					t.Errorf("Expected maximum of -2, got %v", got)
+				}
+			},
+		},
+		{
+			name: "histograms get summed",
+			metrics: []prometheus.Metric{
+				prometheus.MustNewConstHistogram(desc, 2, 10, map[float64]uint64{5: 1, 10: 2}, "a"),
+				prometheus.MustNewConstHistogram(desc, 3, 20, map[float64]uint64{5: 2, 10: 3}, "b"),
+			},
+			check: func(t *testing.T, m *dto.Metric) {
+				if got := m.GetHistogram().GetSampleCount(); got != 5 {
+					t.Errorf("Expected count of 5, got %v", got)
+				}
+
+				if got := m.GetHistogram().GetSampleSum(); got != 30 {
+					t.Errorf("Expected sum of 30, got %v", got)
+				}
+
+				for _, bucket := range m.GetHistogram().GetBucket() {
+					want := map[float64]uint64{5: 3, 10: 5}[bucket.GetUpperBound()]
+
+					if bucket.GetCumulativeCount() != want {
+						t.Errorf("Expected bucket %v to be %d, got %d", bucket.GetUpperBound(), want, bucket.GetCumulativeCount())
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := collector.fold(desc, tt.metrics)
+
+			if folded == nil {
+				t.Fatalf("Expected folded metric")
+			}
+
+			result := &dto.Metric{}
+
+			if err := folded.Write(result); err != nil {
+				t.Fatalf("Failed to write metric: %v", err)
+			}
+
+			if got := result.GetLabel()[0].GetValue(); got != overflowLabel {
+				t.Errorf("Expected overflow label, got %s", got)
+			}
+
+			tt.check(t, result)
+		})
+	}
+
+	if folded := collector.fold(desc, nil); folded != nil {
+		t.Errorf("Expected no folded metric without series, got %v", folded)
+	}
+}
+
+func TestLimitCollectorCollect(t *testing.T) {
+	total := prometheus.NewDesc("test_total", "Test total", nil, nil)
+	status := prometheus.NewDesc("test_status", "Test status", []string{"id"}, nil)
+	duration := prometheus.NewDesc("test_duration", "Test duration", []string{"id"}, nil)
+
+	source := &limitTestCollector{
+		metrics: []prometheus.Metric{
+			prometheus.MustNewConstMetric(total, prometheus.CounterValue, 10),
+		},
+	}
+
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		source.metrics = append(
+			source.metrics,
+			prometheus.MustNewConstMetric(status, prometheus.GaugeValue, 1, id),
+			prometheus.MustNewConstMetric(duration, prometheus.GaugeValue, 1, id),
+		)
+	}
+
+	tests := []struct {
+		name     string
+		overflow bool
+		want     map[string]int
+		ids      []string
+		dropped  float64
+	}{
+		{
+			name:     "drop",
+			overflow: false,
+			want: map[string]int{
+				"test_total":    1,
+				"test_status":   3,
+				"test_duration": 3,
+			},
+			ids:     []string{"3", "4", "5"},
+			dropped: 4,
+		},
+		{
+			name:     "overflow",
+			overflow: true,
+			want: map[string]int{
+				"test_total":    1,
+				"test_status":   3,
+				"test_duration": 3,
+			},
+			ids:     []string{overflowLabel, "4", "5"},
+			dropped: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dropped := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_dropped_total"}, []string{"collector"})
+
+			collector := NewLimitCollector(
+				slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				source,
+				"test",
+				dropped,
+				7,
+				tt.overflow,
+			)
+
+			ch := make(chan prometheus.Metric, len(source.metrics))
+			collector.Collect(ch)
+			close(ch)
+
+			ids := make([]string, 0)
+
+			for metric := range ch {
+				if metric.Desc() != status {
+					continue
+				}
+
+				result := &dto.Metric{}
+
+				if err := metric.Write(result); err != nil {
+					t.Fatalf("Failed to write metric: %v", err)
+				}
+
+				ids = append(ids, result.GetLabel()[0].GetValue())
+			}
+
+			if !slices.Equal(ids, tt.ids) {
+				t.Errorf("Expected latest series %v, got %v", tt.ids, ids)
+			}
+
+			if got := testutil.ToFloat64(dropped.WithLabelValues("test")); got != tt.dropped {
+				t.Errorf("Expected %v dropped series, got %v", tt.dropped, got)
+			}
+
+			for name, want := range tt.want {
+				if count := testutil.CollectAndCount(collector, name); count != want {
+					t.Errorf("Expected %d series of %s, got %d", want, name, count)
+				}
+			}
+		})
+	}
+}
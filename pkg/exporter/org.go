@@ -189,7 +189,7 @@ func (c *OrgCollector) Collect(ch chan<- prometheus.Metric) {
 		now := time.Now()
 		record, resp, err := c.client.Organizations.Get(ctx, name)
 		c.duration.WithLabelValues("org").Observe(time.Since(now).Seconds())
-		defer closeBody(resp)
+		defer CloseBody(resp)
 
 		if err != nil {
 			c.logger.Error("Failed to fetch org",
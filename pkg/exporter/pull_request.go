@@ -0,0 +1,213 @@
+package exporter
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+// PullRequestCollector collects metrics about pull requests.
+type PullRequestCollector struct {
+	client   *github.Client
+	logger   *slog.Logger
+	db       store.Store
+	failures *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	config   config.Target
+
+	Open        *prometheus.Desc
+	Draft       *prometheus.Desc
+	Oldest      *prometheus.Desc
+	FirstReview *prometheus.Desc
+	Merge       *prometheus.Desc
+}
+
+// NewPullRequestCollector returns a new PullRequestCollector.
+func NewPullRequestCollector(logger *slog.Logger, client *github.Client, db store.Store, failures *prometheus.CounterVec, duration *prometheus.HistogramVec, cfg config.Target) *PullRequestCollector {
+	if failures != nil {
+		failures.WithLabelValues("pull_request").Add(0)
+	}
+
+	labels := []string{"owner", "repo", "base"}
+	return &PullRequestCollector{
+		client:   client,
+		logger:   logger.With("collector", "pull_request"),
+		db:       db,
+		failures: failures,
+		duration: duration,
+		config:   cfg,
+
+		Open: prometheus.NewDesc(
+			"github_pull_request_open",
+			"Number of open pull requests",
+			labels,
+			nil,
+		),
+		Draft: prometheus.NewDesc(
+			"github_pull_request_draft",
+			"Number of open pull requests marked as draft",
+			labels,
+			nil,
+		),
+		Oldest: prometheus.NewDesc(
+			"github_pull_request_open_oldest_seconds",
+			"Age of the oldest open pull request",
+			labels,
+			nil,
+		),
+		FirstReview: prometheus.NewDesc(
+			"github_pull_request_first_review_seconds",
+			"Histogram of the time from creation to first review of pull requests",
+			labels,
+			nil,
+		),
+		Merge: prometheus.NewDesc(
+			"github_pull_request_merge_seconds",
+			"Histogram of the time from creation to merge of pull requests",
+			labels,
+			nil,
+		),
+	}
+}
+
+// Metrics simply returns the list metric descriptors for generating a documentation.
+func (c *PullRequestCollector) Metrics() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		c.Open,
+		c.Draft,
+		c.Oldest,
+		c.FirstReview,
+		c.Merge,
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics collected by this Collector.
+func (c *PullRequestCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Open
+	ch <- c.Draft
+	ch <- c.Oldest
+	ch <- c.FirstReview
+	ch <- c.Merge
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *PullRequestCollector) Collect(ch chan<- prometheus.Metric) {
+	if err := c.db.PrunePullRequests(
+		c.config.PullRequests.PurgeWindow,
+	); err != nil {
+		c.logger.Error("Failed to prune pull requests",
+			"err", err,
+		)
+	}
+
+	now := time.Now()
+	records, err := c.db.GetPullRequests(c.config.PullRequests.Window)
+	c.duration.WithLabelValues("pull_request").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch pull requests",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("pull_request").Inc()
+		return
+	}
+
+	c.logger.Debug("Fetched pull requests",
+		"count", len(records),
+		"duration", time.Since(now),
+	)
+
+	type flow struct {
+		labels []string
+		open   float64
+		draft  float64
+		oldest float64
+	}
+
+	keys := make([]string, 0)
+	flows := make(map[string]*flow)
+
+	for _, record := range records {
+		if record.State != "open" {
+			continue
+		}
+
+		key := record.Owner + "/" + record.Repo + ":" + record.Base
+
+		if _, ok := flows[key]; !ok {
+			keys = append(keys, key)
+			flows[key] = &flow{
+				labels: []string{
+					record.Owner,
+					record.Repo,
+					record.Base,
+				},
+			}
+		}
+
+		row := flows[key]
+		row.open++
+
+		if record.Draft {
+			row.draft++
+		}
+
+		row.oldest = max(
+			row.oldest,
+			now.Sub(time.Unix(record.CreatedAt, 0)).Seconds(),
+		)
+	}
+
+	for _, key := range keys {
+		row := flows[key]
+
+		ch <- prometheus.MustNewConstMetric(
+			c.Open,
+			prometheus.GaugeValue,
+			row.open,
+			row.labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			c.Draft,
+			prometheus.GaugeValue,
+			row.draft,
+			row.labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			c.Oldest,
+			prometheus.GaugeValue,
+			row.oldest,
+			row.labels...,
+		)
+	}
+
+	c.collectDurations(ch, "pull_request_first_review", c.FirstReview)
+	c.collectDurations(ch, "pull_request_merge", c.Merge)
+}
+
+func (c *PullRequestCollector) collectDurations(ch chan<- prometheus.Metric, kind string, desc *prometheus.Desc) {
+	now := time.Now()
+	records, err := c.db.GetDurationTotals(kind)
+	c.duration.WithLabelValues("pull_request").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch pull request totals",
+			"kind", kind,
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("pull_request").Inc()
+		return
+	}
+
+	collectDurations(ch, records, desc, func(values []string) ([]string, bool) {
+		return values, len(values) == 3
+	}, nil)
+}
@@ -0,0 +1,141 @@
+package exporter
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+func TestPullRequestCollectorCollect(t *testing.T) {
+	mockLogger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		}),
+	)
+
+	mockStore, err := store.New("memory://", mockLogger)
+
+	if err != nil {
+		t.Fatalf("Failed to setup store: %v", err)
+	}
+
+	now := time.Now()
+
+	repo := &github.Repository{
+		Name: github.Ptr("repo"),
+		Owner: &github.User{
+			Login: github.Ptr("owner"),
+		},
+	}
+
+	pr := func(number int, state string) *github.PullRequest {
+		result := &github.PullRequest{
+			ID:        github.Ptr(int64(number)),
+			Number:    github.Ptr(number),
+			State:     github.Ptr(state),
+			User:      &github.User{Login: github.Ptr("author")},
+			Base:      &github.PullRequestBranch{Ref: github.Ptr("main")},
+			CreatedAt: &github.Timestamp{Time: now.Add(-time.Duration(number) * time.Hour)},
+			UpdatedAt: &github.Timestamp{Time: now},
+		}
+
+		if state == "closed" {
+			result.Merged = github.Ptr(true)
+			result.MergedAt = &github.Timestamp{Time: now}
+		}
+
+		return result
+	}
+
+	for _, event := range []*github.PullRequestEvent{
+		{Repo: repo, PullRequest: pr(1, "open")},
+		{Repo: repo, PullRequest: pr(3, "open")},
+		{Repo: repo, PullRequest: pr(2, "closed")},
+	} {
+		if err := mockStore.StorePullRequestEvent(event); err != nil {
+			t.Fatalf("Failed to store pull request: %v", err)
+		}
+	}
+
+	if err := mockStore.StorePullRequestReviewEvent(&github.PullRequestReviewEvent{
+		Repo:        repo,
+		PullRequest: pr(1, "open"),
+		Review: &github.PullRequestReview{
+			User:        &github.User{Login: github.Ptr("reviewer")},
+			SubmittedAt: &github.Timestamp{Time: now},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to store pull request review: %v", err)
+	}
+
+	collector := NewPullRequestCollector(
+		mockLogger,
+		&github.Client{},
+		mockStore,
+		prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_failures_total"}, []string{"collector"}),
+		prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_duration_seconds"}, []string{"collector"}),
+		config.Target{
+			PullRequests: config.PullRequests{
+				Window:      time.Hour,
+				PurgeWindow: time.Hour,
+			},
+		},
+	)
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP github_pull_request_open Number of open pull requests
+# TYPE github_pull_request_open gauge
+github_pull_request_open{base="main",owner="owner",repo="repo"} 2
+`), "github_pull_request_open"); err != nil {
+		t.Errorf("Unexpected open pull requests: %v", err)
+	}
+
+	if count := testutil.CollectAndCount(collector, "github_pull_request_open_oldest_seconds"); count != 1 {
+		t.Errorf("Expected 1 oldest open pull request, got %d", count)
+	}
+
+	if err := mockStore.PrunePullRequests(-time.Minute); err != nil {
+		t.Fatalf("Failed to prune pull requests: %v", err)
+	}
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP github_pull_request_first_review_seconds Histogram of the time from creation to first review of pull requests
+# TYPE github_pull_request_first_review_seconds histogram
+github_pull_request_first_review_seconds_bucket{base="main",owner="owner",repo="repo",le="3600"} 1
+github_pull_request_first_review_seconds_bucket{base="main",owner="owner",repo="repo",le="14400"} 1
+github_pull_request_first_review_seconds_bucket{base="main",owner="owner",repo="repo",le="28800"} 1
+github_pull_request_first_review_seconds_bucket{base="main",owner="owner",repo="repo",le="86400"} 1
+github_pull_request_first_review_seconds_bucket{base="main",owner="owner",repo="repo",le="172800"} 1
+github_pull_request_first_review_seconds_bucket{base="main",owner="owner",repo="repo",le="259200"} 1
+github_pull_request_first_review_seconds_bucket{base="main",owner="owner",repo="repo",le="604800"} 1
+github_pull_request_first_review_seconds_bucket{base="main",owner="owner",repo="repo",le="1.2096e+06"} 1
+github_pull_request_first_review_seconds_bucket{base="main",owner="owner",repo="repo",le="2.592e+06"} 1
+github_pull_request_first_review_seconds_bucket{base="main",owner="owner",repo="repo",le="+Inf"} 1
+github_pull_request_first_review_seconds_sum{base="main",owner="owner",repo="repo"} 3600
+github_pull_request_first_review_seconds_count{base="main",owner="owner",repo="repo"} 1
+# HELP github_pull_request_merge_seconds Histogram of the time from creation to merge of pull requests
+# TYPE github_pull_request_merge_seconds histogram
+github_pull_request_merge_seconds_bucket{base="main",owner="owner",repo="repo",le="3600"} 0
+github_pull_request_merge_seconds_bucket{base="main",owner="owner",repo="repo",le="14400"} 1
+github_pull_request_merge_seconds_bucket{base="main",owner="owner",repo="repo",le="28800"} 1
+github_pull_request_merge_seconds_bucket{base="main",owner="owner",repo="repo",le="86400"} 1
+github_pull_request_merge_seconds_bucket{base="main",owner="owner",repo="repo",le="172800"} 1
+github_pull_request_merge_seconds_bucket{base="main",owner="owner",repo="repo",le="259200"} 1
+github_pull_request_merge_seconds_bucket{base="main",owner="owner",repo="repo",le="604800"} 1
+github_pull_request_merge_seconds_bucket{base="main",owner="owner",repo="repo",le="1.2096e+06"} 1
+github_pull_request_merge_seconds_bucket{base="main",owner="owner",repo="repo",le="2.592e+06"} 1
+github_pull_request_merge_seconds_bucket{base="main",owner="owner",repo="repo",le="+Inf"} 1
+github_pull_request_merge_seconds_sum{base="main",owner="owner",repo="repo"} 7200
+github_pull_request_merge_seconds_count{base="main",owner="owner",repo="repo"} 1
+`), "github_pull_request_first_review_seconds", "github_pull_request_merge_seconds"); err != nil {
+		t.Errorf("Unexpected persisted pull request histograms: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package exporter
+
+import (
+	"log/slog"
+
+	"github.com/promhippie/github_exporter/pkg/config"
+)
+
+type relabeler []config.Relabel
+
+func newRelabeler(logger *slog.Logger, vals []string) relabeler {
+	rules, err := config.Relabels(vals)
+
+	if err != nil {
+		logger.Error("Failed to parse relabel rules",
+			"err", err,
+		)
+
+		return nil
+	}
+
+	return rules
+}
+
+// apply executes the rules against the label values of a record. It returns
+// a lookup for the rewritten values, or false if the record should be dropped.
+func (r relabeler) apply(lookup func(string) string) (func(string) string, bool) {
+	if len(r) == 0 {
+		return lookup, true
+	}
+
+	rewritten := make(map[string]string)
+
+	value := func(label string) string {
+		if val, ok := rewritten[label]; ok {
+			return val
+		}
+
+		return lookup(label)
+	}
+
+	for _, rule := range r {
+		val := value(rule.Label)
+		matched := rule.Regex.MatchString(val)
+
+		switch rule.Action {
+		case "replace":
+			if matched {
+				rewritten[rule.Label] = rule.Regex.ReplaceAllString(val, rule.Replacement)
+			}
+		case "drop":
+			if matched {
+				return nil, false
+			}
+		case "keep":
+			if !matched {
+				return nil, false
+			}
+		}
+	}
+
+	return value, true
+}
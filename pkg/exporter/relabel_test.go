@@ -0,0 +1,107 @@
+package exporter
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestRelabelerApply(t *testing.T) {
+	record := map[string]string{
+		"owner":  "promhippie",
+		"repo":   "github_exporter",
+		"branch": "feature/relabel",
+		"name":   "gpu-runner-1",
+	}
+
+	lookup := func(label string) string {
+		return record[label]
+	}
+
+	tests := []struct {
+		name    string
+		rules   []string
+		want    map[string]string
+		dropped bool
+	}{
+		{
+			name:  "no rules",
+			rules: nil,
+			want:  record,
+		},
+		{
+			name:  "replace with capture group",
+			rules: []string{"replace:branch:$1:(feature)/.*"},
+			want:  map[string]string{"branch": "feature", "repo": "github_exporter"},
+		},
+		{
+			name:  "replace without match",
+			rules: []string{"replace:branch:main:release/.*"},
+			want:  map[string]string{"branch": "feature/relabel"},
+		},
+		{
+			name:  "chained replaces",
+			rules: []string{"replace:name:gpu:gpu-.*", "replace:name:accelerated:gpu"},
+			want:  map[string]string{"name": "accelerated"},
+		},
+		{
+			name:    "drop on match",
+			rules:   []string{"drop:repo:github_.*"},
+			dropped: true,
+		},
+		{
+			name:  "drop without match",
+			rules: []string{"drop:repo:sandbox-.*"},
+			want:  map[string]string{"repo": "github_exporter"},
+		},
+		{
+			name:  "keep on match",
+			rules: []string{"keep:owner:promhippie"},
+			want:  map[string]string{"owner": "promhippie"},
+		},
+		{
+			name:    "keep without match",
+			rules:   []string{"keep:owner:other"},
+			dropped: true,
+		},
+		{
+			name:    "drop after replace",
+			rules:   []string{"replace:branch:feature:feature/.*", "drop:branch:feature"},
+			dropped: true,
+		},
+		{
+			name:  "partial match is no match",
+			rules: []string{"drop:repo:github"},
+			want:  map[string]string{"repo": "github_exporter"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRelabeler(slog.New(slog.NewTextHandler(os.Stdout, nil)), tt.rules)
+			byLabel, ok := r.apply(lookup)
+
+			if ok == tt.dropped {
+				t.Fatalf("Expected dropped to be %v, got %v", tt.dropped, !ok)
+			}
+
+			for label, want := range tt.want {
+				if got := byLabel(label); got != want {
+					t.Errorf("Expected %s to be %q, got %q", label, want, got)
+				}
+			}
+
+			if record["branch"] != "feature/relabel" {
+				t.Errorf("Expected original record to be untouched")
+			}
+		})
+	}
+}
+
+func TestNewRelabelerInvalid(t *testing.T) {
+	r := newRelabeler(slog.New(slog.NewTextHandler(os.Stdout, nil)), []string{"rename:branch:main"})
+
+	if r != nil {
+		t.Errorf("Expected no rules for invalid config, got %v", r)
+	}
+}
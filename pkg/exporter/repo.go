@@ -263,7 +263,7 @@ func (c *RepoCollector) Collect(ch chan<- prometheus.Metric) {
 		defer cancel()
 
 		now := time.Now()
-		records, err := reposByOwnerAndName(ctx, c.client, owner, repo, c.config.PerPage)
+		records, err := ReposByOwnerAndName(ctx, c.client, owner, repo, c.config.PerPage)
 		c.duration.WithLabelValues("repo").Observe(time.Since(now).Seconds())
 
 		if err != nil {
@@ -300,6 +300,20 @@ func (c *RepoCollector) Collect(ch chan<- prometheus.Metric) {
 				"name", record.GetFullName(),
 			)
 
+			// The default branches get stored for the workflow health collector,
+			// that way we don't have to request the repositories twice.
+			if err := c.db.StoreRepository(&store.Repository{
+				Owner:         record.GetOwner().GetLogin(),
+				Repo:          record.GetName(),
+				DefaultBranch: record.GetDefaultBranch(),
+				UpdatedAt:     time.Now().Unix(),
+			}); err != nil {
+				c.logger.Error("Failed to store repo",
+					"name", record.GetFullName(),
+					"err", err,
+				)
+			}
+
 			labels := []string{
 				owner,
 				record.GetName(),
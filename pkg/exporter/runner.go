@@ -23,6 +23,7 @@ type RunnerCollector struct {
 	failures *prometheus.CounterVec
 	duration *prometheus.HistogramVec
 	config   config.Target
+	relabel  relabeler
 
 	RepoOnline       *prometheus.Desc
 	RepoBusy         *prometheus.Desc
@@ -46,6 +47,7 @@ func NewRunnerCollector(logger *slog.Logger, client *github.Client, db store.Sto
 		failures: failures,
 		duration: duration,
 		config:   cfg,
+		relabel:  newRelabeler(logger, cfg.Runners.Relabel),
 
 		RepoOnline: prometheus.NewDesc(
 			"github_runner_repo_online",
@@ -111,8 +113,6 @@ func (c *RunnerCollector) Describe(ch chan<- *prometheus.Desc) {
 // Collect is called by the Prometheus registry when collecting metrics.
 func (c *RunnerCollector) Collect(ch chan<- prometheus.Metric) {
 	{
-		collected := make([]string, 0)
-
 		now := time.Now()
 		records := c.repoRunners()
 		c.duration.WithLabelValues("runner").Observe(time.Since(now).Seconds())
@@ -122,57 +122,10 @@ func (c *RunnerCollector) Collect(ch chan<- prometheus.Metric) {
 			"duration", time.Since(now),
 		)
 
-		for _, record := range records {
-			if alreadyCollected(collected, record.GetName()) {
-				c.logger.Debug("Already collected repo runner",
-					"name", record.GetName(),
-				)
-
-				continue
-			}
-
-			collected = append(collected, record.GetName())
-
-			var (
-				online float64
-			)
-
-			c.logger.Debug("Collecting repo runner",
-				"name", record.GetName(),
-			)
-
-			labels := []string{}
-
-			for _, label := range c.config.Runners.Labels {
-				labels = append(
-					labels,
-					record.ByLabel(label),
-				)
-			}
-
-			if record.GetStatus() == "online" {
-				online = 1.0
-			}
-
-			ch <- prometheus.MustNewConstMetric(
-				c.RepoOnline,
-				prometheus.GaugeValue,
-				online,
-				labels...,
-			)
-
-			ch <- prometheus.MustNewConstMetric(
-				c.RepoBusy,
-				prometheus.GaugeValue,
-				boolToFloat64(*record.Busy),
-				labels...,
-			)
-		}
+		c.collectRunners(ch, "repo", records, c.RepoOnline, c.RepoBusy)
 	}
 
 	{
-		collected := make([]string, 0)
-
 		now := time.Now()
 		records := c.enterpriseRunners()
 		c.duration.WithLabelValues("runner").Observe(time.Since(now).Seconds())
@@ -182,57 +135,10 @@ func (c *RunnerCollector) Collect(ch chan<- prometheus.Metric) {
 			"duration", time.Since(now),
 		)
 
-		for _, record := range records {
-			if alreadyCollected(collected, record.GetName()) {
-				c.logger.Debug("Already collected enterprise runner",
-					"name", record.GetName(),
-				)
-
-				continue
-			}
-
-			collected = append(collected, record.GetName())
-
-			var (
-				online float64
-			)
-
-			c.logger.Debug("Collecting enterprise runner",
-				"name", record.GetName(),
-			)
-
-			labels := []string{}
-
-			for _, label := range c.config.Runners.Labels {
-				labels = append(
-					labels,
-					record.ByLabel(label),
-				)
-			}
-
-			if record.GetStatus() == "online" {
-				online = 1.0
-			}
-
-			ch <- prometheus.MustNewConstMetric(
-				c.EnterpriseOnline,
-				prometheus.GaugeValue,
-				online,
-				labels...,
-			)
-
-			ch <- prometheus.MustNewConstMetric(
-				c.EnterpriseBusy,
-				prometheus.GaugeValue,
-				boolToFloat64(*record.Busy),
-				labels...,
-			)
-		}
+		c.collectRunners(ch, "enterprise", records, c.EnterpriseOnline, c.EnterpriseBusy)
 	}
 
 	{
-		collected := make([]string, 0)
-
 		now := time.Now()
 		records := c.orgRunners()
 		c.duration.WithLabelValues("runner").Observe(time.Since(now).Seconds())
@@ -242,52 +148,90 @@ func (c *RunnerCollector) Collect(ch chan<- prometheus.Metric) {
 			"duration", time.Since(now),
 		)
 
-		for _, record := range records {
-			if alreadyCollected(collected, record.GetName()) {
-				c.logger.Debug("Already collected org runner",
-					"name", record.GetName(),
-				)
+		c.collectRunners(ch, "org", records, c.OrgOnline, c.OrgBusy)
+	}
+}
 
-				continue
-			}
+// collectRunners sums up the runners sharing the same labels, that way
+// relabeling can map runner names to pools of runners.
+func (c *RunnerCollector) collectRunners(ch chan<- prometheus.Metric, kind string, records []runner, online, busy *prometheus.Desc) {
+	type pool struct {
+		labels []string
+		online float64
+		busy   float64
+	}
 
-			collected = append(collected, record.GetName())
+	collected := make([]string, 0)
+	keys := make([]string, 0)
+	pools := make(map[string]*pool)
 
-			var (
-				online float64
+	for _, record := range records {
+		if alreadyCollected(collected, record.GetName()) {
+			c.logger.Debug("Already collected runner",
+				"type", kind,
+				"name", record.GetName(),
 			)
 
-			c.logger.Debug("Collecting org runner",
+			continue
+		}
+
+		collected = append(collected, record.GetName())
+
+		byLabel, ok := c.relabel.apply(record.ByLabel)
+
+		if !ok {
+			c.logger.Debug("Dropped runner by relabeling",
+				"type", kind,
 				"name", record.GetName(),
 			)
 
-			labels := []string{}
+			continue
+		}
 
-			for _, label := range c.config.Runners.Labels {
-				labels = append(
-					labels,
-					record.ByLabel(label),
-				)
-			}
+		c.logger.Debug("Collecting runner",
+			"type", kind,
+			"name", record.GetName(),
+		)
 
-			if record.GetStatus() == "online" {
-				online = 1.0
-			}
+		labels := []string{}
 
-			ch <- prometheus.MustNewConstMetric(
-				c.OrgOnline,
-				prometheus.GaugeValue,
-				online,
-				labels...,
+		for _, label := range c.config.Runners.Labels {
+			labels = append(
+				labels,
+				byLabel(label),
 			)
+		}
 
-			ch <- prometheus.MustNewConstMetric(
-				c.OrgBusy,
-				prometheus.GaugeValue,
-				boolToFloat64(*record.Busy),
-				labels...,
-			)
+		key := strings.Join(labels, "\x00")
+
+		if _, ok := pools[key]; !ok {
+			keys = append(keys, key)
+			pools[key] = &pool{
+				labels: labels,
+			}
+		}
+
+		if record.GetStatus() == "online" {
+			pools[key].online++
 		}
+
+		pools[key].busy += boolToFloat64(record.GetBusy())
+	}
+
+	for _, key := range keys {
+		ch <- prometheus.MustNewConstMetric(
+			online,
+			prometheus.GaugeValue,
+			pools[key].online,
+			pools[key].labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			busy,
+			prometheus.GaugeValue,
+			pools[key].busy,
+			pools[key].labels...,
+		)
 	}
 }
 
@@ -312,7 +256,7 @@ func (c *RunnerCollector) repoRunners() []runner {
 		ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
 		defer cancel()
 
-		repos, err := reposByOwnerAndName(ctx, c.client, splitOwner, splitName, c.config.PerPage)
+		repos, err := ReposByOwnerAndName(ctx, c.client, splitOwner, splitName, c.config.PerPage)
 
 		if err != nil {
 			c.logger.Error("Failed to fetch repos",
@@ -387,7 +331,7 @@ func (c *RunnerCollector) pagedRepoRunners(ctx context.Context, owner, name stri
 		)
 
 		if err != nil {
-			closeBody(resp)
+			CloseBody(resp)
 			return nil, err
 		}
 
@@ -397,11 +341,11 @@ func (c *RunnerCollector) pagedRepoRunners(ctx context.Context, owner, name stri
 		)
 
 		if resp.NextPage == 0 {
-			closeBody(resp)
+			CloseBody(resp)
 			break
 		}
 
-		closeBody(resp)
+		CloseBody(resp)
 		opts.Page = resp.NextPage
 	}
 
@@ -457,7 +401,7 @@ func (c *RunnerCollector) pagedEnterpriseRunners(ctx context.Context, name strin
 		)
 
 		if err != nil {
-			closeBody(resp)
+			CloseBody(resp)
 			return nil, err
 		}
 
@@ -467,11 +411,11 @@ func (c *RunnerCollector) pagedEnterpriseRunners(ctx context.Context, name strin
 		)
 
 		if resp.NextPage == 0 {
-			closeBody(resp)
+			CloseBody(resp)
 			break
 		}
 
-		closeBody(resp)
+		CloseBody(resp)
 		opts.Page = resp.NextPage
 	}
 
@@ -527,7 +471,7 @@ func (c *RunnerCollector) pagedOrgRunners(ctx context.Context, name string) ([]*
 		)
 
 		if err != nil {
-			closeBody(resp)
+			CloseBody(resp)
 			return nil, err
 		}
 
@@ -537,11 +481,11 @@ func (c *RunnerCollector) pagedOrgRunners(ctx context.Context, name string) ([]*
 		)
 
 		if resp.NextPage == 0 {
-			closeBody(resp)
+			CloseBody(resp)
 			break
 		}
 
-		closeBody(resp)
+		CloseBody(resp)
 		opts.Page = resp.NextPage
 	}
 
@@ -0,0 +1,220 @@
+package exporter
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+// WorkflowHealthCollector collects the health of workflows on the default branch.
+type WorkflowHealthCollector struct {
+	client   *github.Client
+	logger   *slog.Logger
+	db       store.Store
+	failures *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	config   config.Target
+
+	BrokenSince *prometheus.Desc
+	Broken      *prometheus.Desc
+	Recoveries  *prometheus.Desc
+	Recovery    *prometheus.Desc
+}
+
+// NewWorkflowHealthCollector returns a new WorkflowHealthCollector.
+func NewWorkflowHealthCollector(logger *slog.Logger, client *github.Client, db store.Store, failures *prometheus.CounterVec, duration *prometheus.HistogramVec, cfg config.Target) *WorkflowHealthCollector {
+	if failures != nil {
+		failures.WithLabelValues("workflow_health").Add(0)
+	}
+
+	labels := []string{"owner", "repo", "workflow", "branch"}
+	return &WorkflowHealthCollector{
+		client:   client,
+		logger:   logger.With("collector", "workflow_health"),
+		db:       db,
+		failures: failures,
+		duration: duration,
+		config:   cfg,
+
+		BrokenSince: prometheus.NewDesc(
+			"github_workflow_health_broken_since_timestamp",
+			"Timestamp when the workflow went red on the default branch, 0 if it's green",
+			labels,
+			nil,
+		),
+		Broken: prometheus.NewDesc(
+			"github_workflow_health_broken_seconds",
+			"Total time the workflow has been red on the default branch within the window",
+			labels,
+			nil,
+		),
+		Recoveries: prometheus.NewDesc(
+			"github_workflow_health_recoveries",
+			"Number of times the workflow went green again on the default branch within the window",
+			labels,
+			nil,
+		),
+		Recovery: prometheus.NewDesc(
+			"github_workflow_health_recovery_seconds",
+			"Mean time to recovery of the workflow on the default branch within the window",
+			labels,
+			nil,
+		),
+	}
+}
+
+// Metrics simply returns the list metric descriptors for generating a documentation.
+func (c *WorkflowHealthCollector) Metrics() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		c.BrokenSince,
+		c.Broken,
+		c.Recoveries,
+		c.Recovery,
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics collected by this Collector.
+func (c *WorkflowHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.BrokenSince
+	ch <- c.Broken
+	ch <- c.Recoveries
+	ch <- c.Recovery
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *WorkflowHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	repos, err := c.db.GetRepositories()
+	c.duration.WithLabelValues("workflow_health").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch repositories",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("workflow_health").Inc()
+		return
+	}
+
+	branches := make(map[string]string, len(repos))
+
+	for _, repo := range repos {
+		branches[repo.Owner+"/"+repo.Repo] = repo.DefaultBranch
+	}
+
+	now = time.Now()
+	records, err := c.db.GetWorkflowRuns(c.config.Health.Window)
+	c.duration.WithLabelValues("workflow_health").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch workflow runs",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("workflow_health").Inc()
+		return
+	}
+
+	c.logger.Debug("Fetched workflow runs",
+		"count", len(records),
+		"duration", time.Since(now),
+	)
+
+	type health struct {
+		labels      []string
+		brokenSince int64
+		broken      int64
+		recoveries  int64
+		recovery    int64
+	}
+
+	keys := make([]string, 0)
+	workflows := make(map[string]*health)
+
+	// The runs are ordered by their last update, so we walk through them
+	// chronologically and track the transitions between red and green.
+	for _, record := range records {
+		if record.Status != "completed" {
+			continue
+		}
+
+		branch, ok := branches[record.Owner+"/"+record.Repo]
+
+		if !ok || branch == "" || record.Branch != branch {
+			continue
+		}
+
+		key := record.Owner + "/" + record.Repo + ":" + record.Name
+
+		if _, ok := workflows[key]; !ok {
+			keys = append(keys, key)
+			workflows[key] = &health{
+				labels: []string{
+					record.Owner,
+					record.Repo,
+					record.Name,
+					branch,
+				},
+			}
+		}
+
+		workflow := workflows[key]
+
+		switch record.Conclusion {
+		case "failure", "timed_out", "startup_failure":
+			if workflow.brokenSince == 0 {
+				workflow.brokenSince = record.UpdatedAt
+			}
+		case "success":
+			if workflow.brokenSince > 0 {
+				workflow.broken += record.UpdatedAt - workflow.brokenSince
+				workflow.recoveries++
+				workflow.recovery += record.UpdatedAt - workflow.brokenSince
+				workflow.brokenSince = 0
+			}
+		}
+	}
+
+	for _, key := range keys {
+		workflow := workflows[key]
+		broken := workflow.broken
+
+		if workflow.brokenSince > 0 {
+			broken += max(time.Now().Unix()-workflow.brokenSince, 0)
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			c.BrokenSince,
+			prometheus.GaugeValue,
+			float64(workflow.brokenSince),
+			workflow.labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			c.Broken,
+			prometheus.GaugeValue,
+			float64(broken),
+			workflow.labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			c.Recoveries,
+			prometheus.GaugeValue,
+			float64(workflow.recoveries),
+			workflow.labels...,
+		)
+
+		if workflow.recoveries > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				c.Recovery,
+				prometheus.GaugeValue,
+				float64(workflow.recovery)/float64(workflow.recoveries),
+				workflow.labels...,
+			)
+		}
+	}
+}
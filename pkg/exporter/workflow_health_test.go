@@ -0,0 +1,219 @@
+package exporter
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+func TestWorkflowHealthCollectorCollect(t *testing.T) {
+	mockLogger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		}),
+	)
+
+	base := time.Now().Add(-5 * time.Hour).Truncate(time.Second)
+
+	type run struct {
+		branch     string
+		conclusion string
+		offset     time.Duration
+	}
+
+	tests := []struct {
+		name        string
+		runs        []run
+		brokenSince string
+		broken      string
+		recoveries  string
+		recovery    string
+	}{
+		{
+			name: "always green",
+			runs: []run{
+				{branch: "main", conclusion: "success", offset: 0},
+				{branch: "main", conclusion: "success", offset: time.Minute},
+			},
+			brokenSince: "0",
+			broken:      "0",
+			recoveries:  "0",
+		},
+		{
+			name: "single recovery",
+			runs: []run{
+				{branch: "main", conclusion: "failure", offset: 0},
+				{branch: "main", conclusion: "success", offset: 10 * time.Minute},
+			},
+			brokenSince: "0",
+			broken:      "600",
+			recoveries:  "1",
+			recovery:    "600",
+		},
+		{
+			name: "consecutive failures keep the first one",
+			runs: []run{
+				{branch: "main", conclusion: "failure", offset: 0},
+				{branch: "main", conclusion: "timed_out", offset: time.Minute},
+				{branch: "main", conclusion: "startup_failure", offset: 2 * time.Minute},
+				{branch: "main", conclusion: "success", offset: 5 * time.Minute},
+			},
+			brokenSince: "0",
+			broken:      "300",
+			recoveries:  "1",
+			recovery:    "300",
+		},
+		{
+			name: "cancelled runs keep the state",
+			runs: []run{
+				{branch: "main", conclusion: "failure", offset: 0},
+				{branch: "main", conclusion: "cancelled", offset: time.Minute},
+				{branch: "main", conclusion: "success", offset: 2 * time.Minute},
+			},
+			brokenSince: "0",
+			broken:      "120",
+			recoveries:  "1",
+			recovery:    "120",
+		},
+		{
+			name: "multiple recoveries",
+			runs: []run{
+				{branch: "main", conclusion: "failure", offset: 0},
+				{branch: "main", conclusion: "success", offset: 100 * time.Second},
+				{branch: "main", conclusion: "failure", offset: 200 * time.Second},
+				{branch: "main", conclusion: "success", offset: 500 * time.Second},
+			},
+			brokenSince: "0",
+			broken:      "400",
+			recoveries:  "2",
+			recovery:    "200",
+		},
+		{
+			name: "other branches get ignored",
+			runs: []run{
+				{branch: "main", conclusion: "success", offset: 0},
+				{branch: "feature", conclusion: "failure", offset: time.Minute},
+			},
+			brokenSince: "0",
+			broken:      "0",
+			recoveries:  "0",
+		},
+		{
+			name: "still broken",
+			runs: []run{
+				{branch: "main", conclusion: "success", offset: 0},
+				{branch: "main", conclusion: "failure", offset: time.Minute},
+			},
+			brokenSince: fmt.Sprintf("%d", base.Add(time.Minute).Unix()),
+			recoveries:  "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore, err := store.New("memory://", mockLogger)
+
+			if err != nil {
+				t.Fatalf("Failed to setup store: %v", err)
+			}
+
+			if err := mockStore.StoreRepository(&store.Repository{
+				Owner:         "owner",
+				Repo:          "repo",
+				DefaultBranch: "main",
+				UpdatedAt:     base.Unix(),
+			}); err != nil {
+				t.Fatalf("Failed to store repository: %v", err)
+			}
+
+			for i, r := range tt.runs {
+				if err := mockStore.StoreWorkflowRunEvent(&github.WorkflowRunEvent{
+					Repo: &github.Repository{
+						Name: github.Ptr("repo"),
+						Owner: &github.User{
+							Login: github.Ptr("owner"),
+						},
+					},
+					WorkflowRun: &github.WorkflowRun{
+						ID:           github.Ptr(int64(i + 1)),
+						WorkflowID:   github.Ptr(int64(1)),
+						RunNumber:    github.Ptr(i + 1),
+						RunAttempt:   github.Ptr(1),
+						Name:         github.Ptr("CI"),
+						HeadBranch:   github.Ptr(r.branch),
+						Status:       github.Ptr("completed"),
+						Conclusion:   github.Ptr(r.conclusion),
+						CreatedAt:    &github.Timestamp{Time: base.Add(r.offset)},
+						RunStartedAt: &github.Timestamp{Time: base.Add(r.offset)},
+						UpdatedAt:    &github.Timestamp{Time: base.Add(r.offset)},
+					},
+				}); err != nil {
+					t.Fatalf("Failed to store workflow run: %v", err)
+				}
+			}
+
+			collector := NewWorkflowHealthCollector(
+				mockLogger,
+				&github.Client{},
+				mockStore,
+				prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_failures_total"}, []string{"collector"}),
+				prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_duration_seconds"}, []string{"collector"}),
+				config.Target{
+					Health: config.WorkflowHealth{
+						Window: 24 * time.Hour,
+					},
+				},
+			)
+
+			labels := `{branch="main",owner="owner",repo="repo",workflow="CI"}`
+
+			expected := `
+# HELP github_workflow_health_broken_since_timestamp Timestamp when the workflow went red on the default branch, 0 if it's green
+# TYPE github_workflow_health_broken_since_timestamp gauge
+github_workflow_health_broken_since_timestamp` + labels + ` ` + tt.brokenSince + `
+# HELP github_workflow_health_recoveries Number of times the workflow went green again on the default branch within the window
+# TYPE github_workflow_health_recoveries gauge
+github_workflow_health_recoveries` + labels + ` ` + tt.recoveries + `
+`
+			metrics := []string{
+				"github_workflow_health_broken_since_timestamp",
+				"github_workflow_health_recoveries",
+			}
+
+			if tt.broken != "" {
+				expected += `# HELP github_workflow_health_broken_seconds Total time the workflow has been red on the default branch within the window
+# TYPE github_workflow_health_broken_seconds gauge
+github_workflow_health_broken_seconds` + labels + ` ` + tt.broken + `
+`
+				metrics = append(metrics, "github_workflow_health_broken_seconds")
+			}
+
+			if err := testutil.CollectAndCompare(collector, strings.NewReader(expected), metrics...); err != nil {
+				t.Errorf("Unexpected workflow health: %v", err)
+			}
+
+			if count := testutil.CollectAndCount(collector, "github_workflow_health_recovery_seconds"); (count > 0) != (tt.recovery != "") {
+				t.Errorf("Expected recovery series to be %v, got %d", tt.recovery != "", count)
+			}
+
+			if tt.recovery != "" {
+				if err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP github_workflow_health_recovery_seconds Mean time to recovery of the workflow on the default branch within the window
+# TYPE github_workflow_health_recovery_seconds gauge
+github_workflow_health_recovery_seconds`+labels+` `+tt.recovery+`
+`), "github_workflow_health_recovery_seconds"); err != nil {
+					t.Errorf("Unexpected recovery time: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package exporter
 
 import (
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/v72/github"
@@ -18,12 +19,22 @@ type WorkflowJobCollector struct {
 	failures *prometheus.CounterVec
 	duration *prometheus.HistogramVec
 	config   config.Target
+	relabel  relabeler
 
 	Status   *prometheus.Desc
 	Duration *prometheus.Desc
 	Creation *prometheus.Desc
 	Created  *prometheus.Desc
 	Started  *prometheus.Desc
+	Queue    *prometheus.Desc
+	Queued   *prometheus.Desc
+	Waiting  *prometheus.Desc
+	Total    *prometheus.Desc
+	Time     *prometheus.Desc
+	Step     *prometheus.Desc
+	Steps    *prometheus.Desc
+	Flaky    *prometheus.Desc
+	Flakes   *prometheus.Desc
 }
 
 // NewWorkflowJobCollector returns a new WorkflowCollector.
@@ -40,6 +51,7 @@ func NewWorkflowJobCollector(logger *slog.Logger, client *github.Client, db stor
 		failures: failures,
 		duration: duration,
 		config:   cfg,
+		relabel:  newRelabeler(logger, cfg.WorkflowJobs.Relabel),
 
 		Status: prometheus.NewDesc(
 			"github_workflow_job_status",
@@ -71,6 +83,60 @@ func NewWorkflowJobCollector(logger *slog.Logger, client *github.Client, db stor
 			labels,
 			nil,
 		),
+		Queue: prometheus.NewDesc(
+			"github_workflow_job_queue_duration_seconds",
+			"Duration the workflow job waited for a runner in seconds",
+			labels,
+			nil,
+		),
+		Queued: prometheus.NewDesc(
+			"github_workflow_job_queued_seconds",
+			"Duration since the creation of currently queued workflow jobs in seconds",
+			labels,
+			nil,
+		),
+		Waiting: prometheus.NewDesc(
+			"github_workflow_job_queue_wait_seconds",
+			"Histogram of the time workflow jobs waited for a runner per runner labels and group, with exemplars linking to the jobs",
+			[]string{"owner", "labels", "runner_group_name"},
+			nil,
+		),
+		Total: prometheus.NewDesc(
+			"github_workflow_jobs_total",
+			"Total number of finished workflow jobs, with exemplars linking to the jobs",
+			[]string{"owner", "repo", "workflow", "conclusion"},
+			nil,
+		),
+		Time: prometheus.NewDesc(
+			"github_workflow_job_duration_seconds",
+			"Histogram of the duration of finished workflow jobs, with exemplars linking to the jobs",
+			[]string{"owner", "repo", "workflow", "conclusion"},
+			nil,
+		),
+		Step: prometheus.NewDesc(
+			"github_workflow_job_step_duration_seconds",
+			"Histogram of the duration of finished workflow job steps",
+			[]string{"owner", "repo", "workflow", "job", "step"},
+			nil,
+		),
+		Steps: prometheus.NewDesc(
+			"github_workflow_job_step_conclusions",
+			"Number of finished workflow job steps per conclusion",
+			[]string{"owner", "repo", "workflow", "job", "step", "conclusion"},
+			nil,
+		),
+		Flaky: prometheus.NewDesc(
+			"github_workflow_job_flaky",
+			"Number of runs within the flaky window where a job failed and succeeded on a later attempt, a gauge as it decreases when runs leave the window",
+			[]string{"owner", "repo", "workflow_name", "name"},
+			nil,
+		),
+		Flakes: prometheus.NewDesc(
+			"github_workflow_job_flaky_ratio",
+			"Ratio of flaky to finished runs of a job within the flaky window",
+			[]string{"owner", "repo", "workflow_name", "name"},
+			nil,
+		),
 	}
 }
 
@@ -82,6 +148,15 @@ func (c *WorkflowJobCollector) Metrics() []*prometheus.Desc {
 		c.Creation,
 		c.Created,
 		c.Started,
+		c.Queue,
+		c.Queued,
+		c.Waiting,
+		c.Total,
+		c.Time,
+		c.Step,
+		c.Steps,
+		c.Flaky,
+		c.Flakes,
 	}
 }
 
@@ -92,6 +167,15 @@ func (c *WorkflowJobCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Creation
 	ch <- c.Created
 	ch <- c.Started
+	ch <- c.Queue
+	ch <- c.Queued
+	ch <- c.Waiting
+	ch <- c.Total
+	ch <- c.Time
+	ch <- c.Step
+	ch <- c.Steps
+	ch <- c.Flaky
+	ch <- c.Flakes
 }
 
 // Collect is called by the Prometheus registry when collecting metrics.
@@ -122,7 +206,49 @@ func (c *WorkflowJobCollector) Collect(ch chan<- prometheus.Metric) {
 		"duration", time.Since(now),
 	)
 
+	if c.config.WorkflowJobs.Aggregate {
+		c.collectTotals(ch, records)
+	}
+
+	if c.config.WorkflowJobs.Steps {
+		c.collectSteps(ch)
+		c.collectStepDurations(ch)
+	}
+
+	if c.config.WorkflowJobs.Flaky {
+		c.collectFlakes(ch)
+	}
+
+	queues := make(map[string][]prometheus.Exemplar)
+	collected := make(map[string]bool)
+
 	for _, record := range records {
+		byLabel, ok := c.relabel.apply(record.ByLabel)
+
+		if !ok {
+			c.logger.Debug("Dropped workflow job by relabeling",
+				"owner", record.Owner,
+				"repo", record.Repo,
+				"id", record.Identifier,
+				"run_id", record.RunID,
+			)
+
+			continue
+		}
+
+		if record.StartedAt > 0 && record.StartedAt >= record.CreatedAt && record.HTMLURL != "" {
+			key := strings.Join([]string{byLabel("owner"), byLabel("labels"), byLabel("runner_group_name")}, "\x00")
+
+			queues[key] = append(
+				queues[key],
+				urlExemplar(float64(record.StartedAt-record.CreatedAt), record.HTMLURL, record.StartedAt),
+			)
+		}
+
+		if c.config.WorkflowJobs.Aggregate {
+			continue
+		}
+
 		c.logger.Debug("Collecting workflow job",
 			"owner", record.Owner,
 			"repo", record.Repo,
@@ -135,10 +261,20 @@ func (c *WorkflowJobCollector) Collect(ch chan<- prometheus.Metric) {
 		for _, label := range c.config.WorkflowJobs.Labels {
 			labels = append(
 				labels,
-				record.ByLabel(label),
+				byLabel(label),
 			)
 		}
 
+		// Relabeling could merge multiple records into the same labels, we are
+		// only exposing the first one to avoid duplicated series.
+		key := strings.Join(labels, "\x00")
+
+		if collected[key] {
+			continue
+		}
+
+		collected[key] = true
+
 		ch <- prometheus.MustNewConstMetric(
 			c.Status,
 			prometheus.GaugeValue,
@@ -173,6 +309,219 @@ func (c *WorkflowJobCollector) Collect(ch chan<- prometheus.Metric) {
 			float64(record.StartedAt),
 			labels...,
 		)
+
+		if record.Status == "queued" {
+			ch <- prometheus.MustNewConstMetric(
+				c.Queued,
+				prometheus.GaugeValue,
+				time.Since(time.Unix(record.CreatedAt, 0)).Seconds(),
+				labels...,
+			)
+		}
+
+		if record.StartedAt > 0 && record.StartedAt >= record.CreatedAt {
+			ch <- prometheus.MustNewConstMetric(
+				c.Queue,
+				prometheus.GaugeValue,
+				float64(record.StartedAt-record.CreatedAt),
+				labels...,
+			)
+		}
+	}
+
+	c.collectQueues(ch, queues)
+}
+
+func (c *WorkflowJobCollector) collectQueues(ch chan<- prometheus.Metric, exemplars map[string][]prometheus.Exemplar) {
+	now := time.Now()
+	records, err := c.db.GetDurationTotals("workflow_job_queue")
+	c.duration.WithLabelValues("workflow_job").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch workflow job queue totals",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("workflow_job").Inc()
+		return
+	}
+
+	collectDurations(ch, records, c.Waiting, func(values []string) ([]string, bool) {
+		if len(values) != 4 {
+			return nil, false
+		}
+
+		// Only the owner, repo, runner labels and group get persisted, so
+		// relabel rules can't refer to other labels of the jobs here.
+		job := &store.WorkflowJob{
+			Owner:           values[0],
+			Repo:            values[1],
+			Labels:          values[2],
+			RunnerGroupName: values[3],
+		}
+
+		byLabel, ok := c.relabel.apply(job.ByLabel)
+
+		if !ok {
+			return nil, false
+		}
+
+		return []string{
+			byLabel("owner"),
+			byLabel("labels"),
+			byLabel("runner_group_name"),
+		}, true
+	}, exemplars)
+}
+
+func (c *WorkflowJobCollector) collectTotals(ch chan<- prometheus.Metric, jobs []*store.WorkflowJob) {
+	now := time.Now()
+	records, err := c.db.GetWorkflowTotals()
+	c.duration.WithLabelValues("workflow_job").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch workflow totals",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("workflow_job").Inc()
+		return
+	}
+
+	exemplars := make(map[string][]prometheus.Exemplar)
+
+	for _, job := range jobs {
+		if job.Status != "completed" || job.HTMLURL == "" {
+			continue
+		}
+
+		key := job.Owner + "/" + job.Repo + ":" + job.WorkflowName + ":" + job.Conclusion
+
+		exemplars[key] = append(
+			exemplars[key],
+			urlExemplar(float64(max(job.CompletedAt-job.StartedAt, 0)), job.HTMLURL, job.CompletedAt),
+		)
+	}
+
+	collectTotals(ch, records, "workflow_job", c.Total, c.Time, exemplars)
+}
+
+func (c *WorkflowJobCollector) collectSteps(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	records, err := c.db.GetWorkflowJobSteps(c.config.WorkflowJobs.Window)
+	c.duration.WithLabelValues("workflow_job").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch workflow job steps",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("workflow_job").Inc()
+		return
+	}
+
+	type step struct {
+		labels      []string
+		conclusions map[string]float64
+	}
+
+	keys := make([]string, 0)
+	steps := make(map[string]*step)
+
+	for _, record := range records {
+		if record.Status != "completed" {
+			continue
+		}
+
+		key := record.Owner + "/" + record.Repo + ":" + record.WorkflowName + ":" + record.JobName + ":" + record.Name
+
+		if _, ok := steps[key]; !ok {
+			keys = append(keys, key)
+			steps[key] = &step{
+				labels: []string{
+					record.Owner,
+					record.Repo,
+					record.WorkflowName,
+					record.JobName,
+					record.Name,
+				},
+				conclusions: make(map[string]float64),
+			}
+		}
+
+		steps[key].conclusions[record.Conclusion]++
+	}
+
+	for _, key := range keys {
+		for conclusion, value := range steps[key].conclusions {
+			ch <- prometheus.MustNewConstMetric(
+				c.Steps,
+				prometheus.GaugeValue,
+				value,
+				append(steps[key].labels, conclusion)...,
+			)
+		}
+	}
+}
+
+func (c *WorkflowJobCollector) collectStepDurations(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	records, err := c.db.GetDurationTotals("workflow_job_step")
+	c.duration.WithLabelValues("workflow_job").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch workflow job step totals",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("workflow_job").Inc()
+		return
+	}
+
+	collectDurations(ch, records, c.Step, func(values []string) ([]string, bool) {
+		return values, len(values) == 5
+	}, nil)
+}
+
+func (c *WorkflowJobCollector) collectFlakes(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	records, err := c.db.GetWorkflowJobFlakes(c.config.WorkflowJobs.FlakyWindow)
+	c.duration.WithLabelValues("workflow_job").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch flaky workflow jobs",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("workflow_job").Inc()
+		return
+	}
+
+	for _, record := range records {
+		if record.Runs == 0 {
+			continue
+		}
+
+		labels := []string{
+			record.Owner,
+			record.Repo,
+			record.WorkflowName,
+			record.Name,
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			c.Flaky,
+			prometheus.GaugeValue,
+			float64(record.Flaky),
+			labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			c.Flakes,
+			prometheus.GaugeValue,
+			float64(record.Flaky)/float64(record.Runs),
+			labels...,
+		)
 	}
 }
 
@@ -1,7 +1,6 @@
 package exporter
 
 import (
-	"fmt"
 	"log/slog"
 	"os"
 	"reflect"
@@ -10,64 +9,11 @@ import (
 
 	"github.com/google/go-github/v72/github"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/promhippie/github_exporter/pkg/config"
 	"github.com/promhippie/github_exporter/pkg/store"
 )
 
-type StaticStore struct{}
-
-func (s StaticStore) GetWorkflowJobRuns(owner, repo, workflow string) ([]*store.WorkflowRun, error) {
-	fmt.Fprintf(
-		os.Stdout,
-		"GetWorkflowJobRuns for %s/%s %s \n",
-		owner,
-		repo,
-		workflow,
-	)
-
-	return nil, nil
-}
-
-func (s StaticStore) StoreWorkflowRunEvent(*github.WorkflowRunEvent) error {
-	return nil
-}
-
-func (s StaticStore) GetWorkflowRuns(time.Duration) ([]*store.WorkflowRun, error) {
-	return nil, nil
-}
-
-func (s StaticStore) PruneWorkflowRuns(time.Duration) error {
-	return nil
-}
-
-func (s StaticStore) StoreWorkflowJobEvent(*github.WorkflowJobEvent) error {
-	return nil
-}
-
-func (s StaticStore) GetWorkflowJobs(time.Duration) ([]*store.WorkflowJob, error) {
-	return nil, nil
-}
-
-func (s StaticStore) PruneWorkflowJobs(time.Duration) error {
-	return nil
-}
-
-func (s StaticStore) Open() (bool, error) {
-	return true, nil
-}
-
-func (s StaticStore) Close() error {
-	return nil
-}
-
-func (s StaticStore) Ping() (bool, error) {
-	return true, nil
-}
-
-func (s StaticStore) Migrate() error {
-	return nil
-}
-
 func TestWorkflowJobCollector(t *testing.T) {
 	mockClient := &github.Client{}
 
@@ -77,7 +23,11 @@ func TestWorkflowJobCollector(t *testing.T) {
 		}),
 	)
 
-	mockStore := StaticStore{}
+	mockStore, err := store.New("memory://", mockLogger)
+
+	if err != nil {
+		t.Fatalf("Failed to setup store: %v", err)
+	}
 
 	mockFailures := prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "test_failures_total",
@@ -139,3 +89,94 @@ func TestWorkflowJobCollector(t *testing.T) {
 		t.Errorf("Expected config to be %v, got %v", mockConfig, collector.config)
 	}
 }
+
+func TestWorkflowJobCollectorCollect(t *testing.T) {
+	mockLogger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		}),
+	)
+
+	mockStore, err := store.New("memory://", mockLogger)
+
+	if err != nil {
+		t.Fatalf("Failed to setup store: %v", err)
+	}
+
+	now := time.Now()
+
+	if err := mockStore.StoreWorkflowJobEvent(&github.WorkflowJobEvent{
+		Repo: &github.Repository{
+			Name: github.Ptr("repo"),
+			Owner: &github.User{
+				Login: github.Ptr("owner"),
+			},
+		},
+		WorkflowJob: &github.WorkflowJob{
+			ID:           github.Ptr(int64(1)),
+			RunID:        github.Ptr(int64(2)),
+			RunAttempt:   github.Ptr(int64(1)),
+			Name:         github.Ptr("build"),
+			Status:       github.Ptr("completed"),
+			Conclusion:   github.Ptr("success"),
+			WorkflowName: github.Ptr("CI"),
+			CreatedAt:    &github.Timestamp{Time: now.Add(-2 * time.Minute)},
+			StartedAt:    &github.Timestamp{Time: now.Add(-time.Minute)},
+			CompletedAt:  &github.Timestamp{Time: now},
+			Steps: []*github.TaskStep{
+				{
+					Number:      github.Ptr(int64(1)),
+					Name:        github.Ptr("checkout"),
+					Status:      github.Ptr("completed"),
+					Conclusion:  github.Ptr("success"),
+					StartedAt:   &github.Timestamp{Time: now.Add(-time.Minute)},
+					CompletedAt: &github.Timestamp{Time: now},
+				},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to store workflow job: %v", err)
+	}
+
+	mockConfig := config.Target{
+		WorkflowJobs: config.WorkflowJobs{
+			Window:      time.Hour,
+			PurgeWindow: time.Hour,
+			Labels:      config.JobLabels(),
+			Steps:       true,
+		},
+	}
+
+	collector := NewWorkflowJobCollector(
+		mockLogger,
+		&github.Client{},
+		mockStore,
+		prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_failures_total"}, []string{"collector"}),
+		prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_duration_seconds"}, []string{"collector"}),
+		mockConfig,
+	)
+
+	if count := testutil.CollectAndCount(collector, "github_workflow_job_status"); count != 1 {
+		t.Errorf("Expected 1 workflow job status, got %d", count)
+	}
+
+	if err := mockStore.PruneWorkflowJobs(-time.Minute); err != nil {
+		t.Fatalf("Failed to prune workflow jobs: %v", err)
+	}
+
+	if count := testutil.CollectAndCount(collector, "github_workflow_job_status"); count != 0 {
+		t.Errorf("Expected pruned workflow jobs, got %d", count)
+	}
+
+	if count := testutil.CollectAndCount(collector, "github_workflow_job_queue_wait_seconds"); count != 1 {
+		t.Errorf("Expected persisted queue histogram, got %d", count)
+	}
+
+	if count := testutil.CollectAndCount(collector, "github_workflow_job_step_conclusions"); count != 0 {
+		t.Errorf("Expected pruned workflow job steps, got %d", count)
+	}
+
+	if count := testutil.CollectAndCount(collector, "github_workflow_job_step_duration_seconds"); count != 1 {
+		t.Errorf("Expected persisted step histogram, got %d", count)
+	}
+}
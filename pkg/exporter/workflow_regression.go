@@ -0,0 +1,278 @@
+package exporter
+
+import (
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+// WorkflowRegressionCollector collects duration regressions of workflows.
+type WorkflowRegressionCollector struct {
+	client   *github.Client
+	logger   *slog.Logger
+	db       store.Store
+	failures *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	config   config.Target
+
+	RunRatio       *prometheus.Desc
+	RunBaseline    *prometheus.Desc
+	RunRegressions *prometheus.Desc
+	JobRatio       *prometheus.Desc
+	JobBaseline    *prometheus.Desc
+	JobRegressions *prometheus.Desc
+}
+
+// NewWorkflowRegressionCollector returns a new WorkflowRegressionCollector.
+func NewWorkflowRegressionCollector(logger *slog.Logger, client *github.Client, db store.Store, failures *prometheus.CounterVec, duration *prometheus.HistogramVec, cfg config.Target) *WorkflowRegressionCollector {
+	if failures != nil {
+		failures.WithLabelValues("workflow_regression").Add(0)
+	}
+
+	runLabels := []string{"owner", "repo", "workflow", "branch"}
+	jobLabels := []string{"owner", "repo", "workflow", "name", "branch"}
+	return &WorkflowRegressionCollector{
+		client:   client,
+		logger:   logger.With("collector", "workflow_regression"),
+		db:       db,
+		failures: failures,
+		duration: duration,
+		config:   cfg,
+
+		RunRatio: prometheus.NewDesc(
+			"github_workflow_run_regression_ratio",
+			"Ratio of the duration of the latest workflow run to the baseline",
+			runLabels,
+			nil,
+		),
+		RunBaseline: prometheus.NewDesc(
+			"github_workflow_run_baseline_seconds",
+			"Median duration of the previous workflow runs used as baseline",
+			runLabels,
+			nil,
+		),
+		RunRegressions: prometheus.NewDesc(
+			"github_workflow_run_regressions",
+			"Number of workflow runs within the window slower than the baseline multiplied by the factor, a gauge as it decreases when runs leave the window",
+			runLabels,
+			nil,
+		),
+		JobRatio: prometheus.NewDesc(
+			"github_workflow_job_regression_ratio",
+			"Ratio of the duration of the latest workflow job to the baseline",
+			jobLabels,
+			nil,
+		),
+		JobBaseline: prometheus.NewDesc(
+			"github_workflow_job_baseline_seconds",
+			"Median duration of the previous workflow jobs used as baseline",
+			jobLabels,
+			nil,
+		),
+		JobRegressions: prometheus.NewDesc(
+			"github_workflow_job_regressions",
+			"Number of workflow jobs within the window slower than the baseline multiplied by the factor, a gauge as it decreases when jobs leave the window",
+			jobLabels,
+			nil,
+		),
+	}
+}
+
+// Metrics simply returns the list metric descriptors for generating a documentation.
+func (c *WorkflowRegressionCollector) Metrics() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		c.RunRatio,
+		c.RunBaseline,
+		c.RunRegressions,
+		c.JobRatio,
+		c.JobBaseline,
+		c.JobRegressions,
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics collected by this Collector.
+func (c *WorkflowRegressionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.RunRatio
+	ch <- c.RunBaseline
+	ch <- c.RunRegressions
+	ch <- c.JobRatio
+	ch <- c.JobBaseline
+	ch <- c.JobRegressions
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *WorkflowRegressionCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	runs, err := c.db.GetWorkflowRuns(c.config.Regressions.Window)
+	c.duration.WithLabelValues("workflow_regression").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch workflow runs",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("workflow_regression").Inc()
+	} else {
+		samples := make([]regressionSample, 0, len(runs))
+
+		for _, record := range runs {
+			if record.Status != "completed" || record.Conclusion != "success" || record.StartedAt == 0 {
+				continue
+			}
+
+			samples = append(samples, regressionSample{
+				labels: []string{
+					record.Owner,
+					record.Repo,
+					record.Name,
+					record.Branch,
+				},
+				duration: float64(record.UpdatedAt - record.StartedAt),
+				finished: record.UpdatedAt,
+			})
+		}
+
+		c.collectRegressions(ch, samples, c.RunRatio, c.RunBaseline, c.RunRegressions)
+	}
+
+	now = time.Now()
+	jobs, err := c.db.GetWorkflowJobs(c.config.Regressions.Window)
+	c.duration.WithLabelValues("workflow_regression").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch workflow jobs",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("workflow_regression").Inc()
+		return
+	}
+
+	samples := make([]regressionSample, 0, len(jobs))
+
+	for _, record := range jobs {
+		if record.Status != "completed" || record.Conclusion != "success" || record.StartedAt == 0 {
+			continue
+		}
+
+		samples = append(samples, regressionSample{
+			labels: []string{
+				record.Owner,
+				record.Repo,
+				record.WorkflowName,
+				record.Name,
+				record.Branch,
+			},
+			duration: float64(record.CompletedAt - record.StartedAt),
+			finished: record.CompletedAt,
+		})
+	}
+
+	c.collectRegressions(ch, samples, c.JobRatio, c.JobBaseline, c.JobRegressions)
+}
+
+type regressionSample struct {
+	labels   []string
+	duration float64
+	finished int64
+}
+
+func (c *WorkflowRegressionCollector) collectRegressions(ch chan<- prometheus.Metric, samples []regressionSample, ratio, baseline, regressions *prometheus.Desc) {
+	keys := make([]string, 0)
+	groups := make(map[string][]regressionSample)
+
+	for _, sample := range samples {
+		key := strings.Join(sample.labels, "\x00")
+
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		groups[key] = append(groups[key], sample)
+	}
+
+	size := c.config.Regressions.Baseline
+
+	for _, key := range keys {
+		group := groups[key]
+
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].finished < group[j].finished
+		})
+
+		var (
+			latest float64
+			median float64
+			slower float64
+		)
+
+		// Every run gets compared against the median of the previous runs, that
+		// way a single slow run doesn't shift the baseline for the next ones.
+		for i := size; i < len(group); i++ {
+			previous := make([]float64, 0, size)
+
+			for _, sample := range group[i-size : i] {
+				previous = append(previous, sample.duration)
+			}
+
+			slices.Sort(previous)
+			median = medianOf(previous)
+
+			if median <= 0 {
+				latest = 0
+				continue
+			}
+
+			latest = group[i].duration / median
+
+			if latest >= c.config.Regressions.Factor {
+				slower++
+			}
+		}
+
+		if len(group) <= size {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			ratio,
+			prometheus.GaugeValue,
+			latest,
+			group[0].labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			baseline,
+			prometheus.GaugeValue,
+			median,
+			group[0].labels...,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			regressions,
+			prometheus.GaugeValue,
+			slower,
+			group[0].labels...,
+		)
+	}
+}
+
+// medianOf returns the median of the sorted values.
+func medianOf(sorted []float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	if len(sorted)%2 == 1 {
+		return sorted[len(sorted)/2]
+	}
+
+	return (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+}
@@ -0,0 +1,139 @@
+package exporter
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/promhippie/github_exporter/pkg/config"
+)
+
+func TestMedianOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"single", []float64{5}, 5},
+		{"odd", []float64{1, 3, 9}, 3},
+		{"even", []float64{1, 3, 5, 9}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := medianOf(tt.values); got != tt.want {
+				t.Errorf("Expected median %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWorkflowRegressionCollectorCollectRegressions(t *testing.T) {
+	tests := []struct {
+		name      string
+		durations []float64
+		factor    float64
+		want      map[string]float64
+	}{
+		{
+			name:      "not enough samples",
+			durations: []float64{10, 10, 10},
+			factor:    2,
+			want:      map[string]float64{},
+		},
+		{
+			name:      "below factor",
+			durations: []float64{10, 10, 10, 15},
+			factor:    2,
+			want: map[string]float64{
+				"ratio":       1.5,
+				"baseline":    10,
+				"regressions": 0,
+			},
+		},
+		{
+			name:      "exactly factor",
+			durations: []float64{10, 10, 10, 20},
+			factor:    2,
+			want: map[string]float64{
+				"ratio":       2,
+				"baseline":    10,
+				"regressions": 1,
+			},
+		},
+		{
+			name:      "slow run does not shift baseline",
+			durations: []float64{10, 10, 10, 40, 10},
+			factor:    2,
+			want: map[string]float64{
+				"ratio":       1,
+				"baseline":    10,
+				"regressions": 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewWorkflowRegressionCollector(
+				slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				&github.Client{},
+				nil,
+				nil,
+				nil,
+				config.Target{
+					Regressions: config.WorkflowRegressions{
+						Baseline: 3,
+						Factor:   tt.factor,
+					},
+				},
+			)
+
+			samples := make([]regressionSample, 0, len(tt.durations))
+
+			for i, duration := range tt.durations {
+				samples = append(samples, regressionSample{
+					labels:   []string{"owner", "repo", "CI", "main"},
+					duration: duration,
+					finished: int64(i),
+				})
+			}
+
+			descs := map[*prometheus.Desc]string{
+				collector.RunRatio:       "ratio",
+				collector.RunBaseline:    "baseline",
+				collector.RunRegressions: "regressions",
+			}
+
+			ch := make(chan prometheus.Metric, 10)
+			collector.collectRegressions(ch, samples, collector.RunRatio, collector.RunBaseline, collector.RunRegressions)
+			close(ch)
+
+			got := make(map[string]float64)
+
+			for metric := range ch {
+				result := &dto.Metric{}
+
+				if err := metric.Write(result); err != nil {
+					t.Fatalf("Failed to write metric: %v", err)
+				}
+
+				got[descs[metric.Desc()]] = result.GetGauge().GetValue()
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d metrics, got %d", len(tt.want), len(got))
+			}
+
+			for key, want := range tt.want {
+				if got[key] != want {
+					t.Errorf("Expected %s to be %v, got %v", key, want, got[key])
+				}
+			}
+		})
+	}
+}
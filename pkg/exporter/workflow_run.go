@@ -2,6 +2,9 @@ package exporter
 
 import (
 	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/v72/github"
@@ -18,13 +21,19 @@ type WorkflowRunCollector struct {
 	failures *prometheus.CounterVec
 	duration *prometheus.HistogramVec
 	config   config.Target
+	relabel  relabeler
 
 	Status   *prometheus.Desc
+	State    *prometheus.Desc
 	Duration *prometheus.Desc
 	Creation *prometheus.Desc
 	Created  *prometheus.Desc
 	Updated  *prometheus.Desc
 	Started  *prometheus.Desc
+	Reruns   *prometheus.Desc
+	Total    *prometheus.Desc
+	Time     *prometheus.Desc
+	Info     *prometheus.Desc
 }
 
 // NewWorkflowRunCollector returns a new WorkflowRunCollector.
@@ -41,6 +50,7 @@ func NewWorkflowRunCollector(logger *slog.Logger, client *github.Client, db stor
 		failures: failures,
 		duration: duration,
 		config:   cfg,
+		relabel:  newRelabeler(logger, cfg.WorkflowRuns.Relabel),
 
 		Status: prometheus.NewDesc(
 			"github_workflow_run_status",
@@ -48,6 +58,12 @@ func NewWorkflowRunCollector(logger *slog.Logger, client *github.Client, db stor
 			labels,
 			nil,
 		),
+		State: prometheus.NewDesc(
+			"github_workflow_run_state",
+			"State of workflow runs, 1 for the conclusion if completed, otherwise for the status",
+			append(slices.Clone(labels), "state"),
+			nil,
+		),
 		Duration: prometheus.NewDesc(
 			"github_workflow_run_duration_ms",
 			"Duration of workflow runs",
@@ -78,6 +94,30 @@ func NewWorkflowRunCollector(logger *slog.Logger, client *github.Client, db stor
 			labels,
 			nil,
 		),
+		Reruns: prometheus.NewDesc(
+			"github_workflow_run_reruns",
+			"Number of re-run attempts of workflow runs within the window",
+			[]string{"owner", "repo", "workflow"},
+			nil,
+		),
+		Total: prometheus.NewDesc(
+			"github_workflow_runs_total",
+			"Total number of finished workflow runs, with exemplars linking to the runs",
+			[]string{"owner", "repo", "workflow", "conclusion"},
+			nil,
+		),
+		Time: prometheus.NewDesc(
+			"github_workflow_run_duration_seconds",
+			"Histogram of the duration of finished workflow runs, with exemplars linking to the runs",
+			[]string{"owner", "repo", "workflow", "conclusion"},
+			nil,
+		),
+		Info: prometheus.NewDesc(
+			"github_workflow_info",
+			"Information about workflows resolved from the API",
+			[]string{"owner", "repo", "workflow", "name", "path", "state"},
+			nil,
+		),
 	}
 }
 
@@ -85,22 +125,32 @@ func NewWorkflowRunCollector(logger *slog.Logger, client *github.Client, db stor
 func (c *WorkflowRunCollector) Metrics() []*prometheus.Desc {
 	return []*prometheus.Desc{
 		c.Status,
+		c.State,
 		c.Duration,
 		c.Creation,
 		c.Created,
 		c.Updated,
 		c.Started,
+		c.Reruns,
+		c.Total,
+		c.Time,
+		c.Info,
 	}
 }
 
 // Describe sends the super-set of all possible descriptors of metrics collected by this Collector.
 func (c *WorkflowRunCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Status
+	ch <- c.State
 	ch <- c.Duration
 	ch <- c.Creation
 	ch <- c.Created
 	ch <- c.Updated
 	ch <- c.Started
+	ch <- c.Reruns
+	ch <- c.Total
+	ch <- c.Time
+	ch <- c.Info
 }
 
 // Collect is called by the Prometheus registry when collecting metrics.
@@ -131,7 +181,83 @@ func (c *WorkflowRunCollector) Collect(ch chan<- prometheus.Metric) {
 		"duration", time.Since(now),
 	)
 
+	c.collectWorkflows(ch, records)
+
+	type rerun struct {
+		labels []string
+		count  float64
+	}
+
+	latest := make(map[string]int)
+	rerunKeys := make([]string, 0)
+	reruns := make(map[string]*rerun)
+
 	for _, record := range records {
+		if _, ok := c.relabel.apply(record.ByLabel); !ok {
+			continue
+		}
+
+		key := record.Owner + "/" + record.Repo + "/" + strconv.FormatInt(record.Identifier, 10)
+
+		if val, ok := latest[key]; !ok || record.Attempt > val {
+			latest[key] = record.Attempt
+		}
+
+		if record.Attempt > 1 {
+			workflow := record.Owner + "/" + record.Repo + ":" + record.Name
+
+			if _, ok := reruns[workflow]; !ok {
+				rerunKeys = append(rerunKeys, workflow)
+				reruns[workflow] = &rerun{
+					labels: []string{
+						record.Owner,
+						record.Repo,
+						record.Name,
+					},
+				}
+			}
+
+			reruns[workflow].count++
+		}
+	}
+
+	for _, key := range rerunKeys {
+		ch <- prometheus.MustNewConstMetric(
+			c.Reruns,
+			prometheus.GaugeValue,
+			reruns[key].count,
+			reruns[key].labels...,
+		)
+	}
+
+	if c.config.WorkflowRuns.Aggregate {
+		c.collectTotals(ch, records)
+		return
+	}
+
+	attempts := slices.Contains(c.config.WorkflowRuns.Labels, "attempt")
+	collected := make(map[string]bool)
+
+	for _, record := range records {
+		// Without the attempt label all attempts of a run share the same
+		// labels, so we only expose the latest attempt in that case.
+		if !attempts && record.Attempt < latest[record.Owner+"/"+record.Repo+"/"+strconv.FormatInt(record.Identifier, 10)] {
+			continue
+		}
+
+		byLabel, ok := c.relabel.apply(record.ByLabel)
+
+		if !ok {
+			c.logger.Debug("Dropped workflow run by relabeling",
+				"owner", record.Owner,
+				"repo", record.Repo,
+				"workflow", record.WorkflowID,
+				"number", record.Number,
+			)
+
+			continue
+		}
+
 		c.logger.Debug("Collecting workflow run",
 			"owner", record.Owner,
 			"repo", record.Repo,
@@ -144,16 +270,39 @@ func (c *WorkflowRunCollector) Collect(ch chan<- prometheus.Metric) {
 		for _, label := range c.config.WorkflowRuns.Labels {
 			labels = append(
 				labels,
-				record.ByLabel(label),
+				byLabel(label),
 			)
 		}
 
-		ch <- prometheus.MustNewConstMetric(
-			c.Status,
-			prometheus.GaugeValue,
-			statusToGauge(record.Status),
-			labels...,
-		)
+		// Relabeling could merge multiple records into the same labels, we are
+		// only exposing the first one to avoid duplicated series.
+		key := strings.Join(labels, "\x00")
+
+		if collected[key] {
+			continue
+		}
+
+		collected[key] = true
+
+		if c.config.WorkflowRuns.Status {
+			ch <- prometheus.MustNewConstMetric(
+				c.Status,
+				prometheus.GaugeValue,
+				statusToGauge(record.State()),
+				labels...,
+			)
+		}
+
+		if c.config.WorkflowRuns.State {
+			for state, value := range stateSet(workflowRunStates, record.State()) {
+				ch <- prometheus.MustNewConstMetric(
+					c.State,
+					prometheus.GaugeValue,
+					value,
+					append(slices.Clone(labels), state)...,
+				)
+			}
+		}
 
 		ch <- prometheus.MustNewConstMetric(
 			c.Duration,
@@ -192,6 +341,97 @@ func (c *WorkflowRunCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 }
 
+func (c *WorkflowRunCollector) collectWorkflows(ch chan<- prometheus.Metric, runs []*store.WorkflowRun) {
+	now := time.Now()
+	records, err := c.db.GetWorkflows()
+	c.duration.WithLabelValues("workflow_run").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch workflows",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("workflow_run").Inc()
+		return
+	}
+
+	workflows := make(map[string]*store.Workflow, len(records))
+
+	for _, record := range records {
+		workflows[record.Owner+"/"+record.Repo+"/"+strconv.FormatInt(record.Identifier, 10)] = record
+
+		ch <- prometheus.MustNewConstMetric(
+			c.Info,
+			prometheus.GaugeValue,
+			1.0,
+			record.Owner,
+			record.Repo,
+			strconv.FormatInt(record.Identifier, 10),
+			record.Name,
+			record.Path,
+			record.State,
+		)
+	}
+
+	for _, run := range runs {
+		if workflow, ok := workflows[run.Owner+"/"+run.Repo+"/"+strconv.FormatInt(run.WorkflowID, 10)]; ok {
+			run.WorkflowName = workflow.Name
+			run.WorkflowPath = workflow.Path
+		}
+	}
+}
+
+func (c *WorkflowRunCollector) collectTotals(ch chan<- prometheus.Metric, runs []*store.WorkflowRun) {
+	now := time.Now()
+	records, err := c.db.GetWorkflowTotals()
+	c.duration.WithLabelValues("workflow_run").Observe(time.Since(now).Seconds())
+
+	if err != nil {
+		c.logger.Error("Failed to fetch workflow totals",
+			"err", err,
+		)
+
+		c.failures.WithLabelValues("workflow_run").Inc()
+		return
+	}
+
+	exemplars := make(map[string][]prometheus.Exemplar)
+
+	for _, run := range runs {
+		if run.Status != "completed" || run.HTMLURL == "" {
+			continue
+		}
+
+		key := run.Owner + "/" + run.Repo + ":" + run.Name + ":" + run.Conclusion
+
+		exemplars[key] = append(
+			exemplars[key],
+			urlExemplar(float64(max(run.UpdatedAt-run.StartedAt, 0)), run.HTMLURL, run.UpdatedAt),
+		)
+	}
+
+	collectTotals(ch, records, "workflow_run", c.Total, c.Time, exemplars)
+}
+
+// workflowRunStates defines all known statuses and conclusions of workflow runs.
+var workflowRunStates = []string{
+	"requested",
+	"queued",
+	"waiting",
+	"pending",
+	"in_progress",
+	"completed",
+	"action_required",
+	"cancelled",
+	"failure",
+	"neutral",
+	"skipped",
+	"stale",
+	"startup_failure",
+	"success",
+	"timed_out",
+}
+
 func statusToGauge(conclusion string) float64 {
 	switch conclusion {
 	case "completed":
@@ -0,0 +1,215 @@
+package exporter
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+func TestWorkflowRunCollectorCollect(t *testing.T) {
+	mockLogger := slog.New(
+		slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+		}),
+	)
+
+	mockStore, err := store.New("memory://", mockLogger)
+
+	if err != nil {
+		t.Fatalf("Failed to setup store: %v", err)
+	}
+
+	now := time.Now()
+
+	if err := mockStore.StoreWorkflowRunEvent(&github.WorkflowRunEvent{
+		Repo: &github.Repository{
+			Name: github.Ptr("repo"),
+			Owner: &github.User{
+				Login: github.Ptr("owner"),
+			},
+		},
+		WorkflowRun: &github.WorkflowRun{
+			ID:           github.Ptr(int64(1)),
+			WorkflowID:   github.Ptr(int64(2)),
+			RunNumber:    github.Ptr(3),
+			RunAttempt:   github.Ptr(1),
+			Name:         github.Ptr("CI"),
+			Event:        github.Ptr("push"),
+			HeadBranch:   github.Ptr("main"),
+			Status:       github.Ptr("completed"),
+			Conclusion:   github.Ptr("failure"),
+			CreatedAt:    &github.Timestamp{Time: now.Add(-time.Minute)},
+			RunStartedAt: &github.Timestamp{Time: now.Add(-time.Minute)},
+			UpdatedAt:    &github.Timestamp{Time: now},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to store workflow run: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		status bool
+		state  bool
+		want   map[string]int
+	}{
+		{
+			name:   "defaults",
+			status: false,
+			state:  true,
+			want: map[string]int{
+				"github_workflow_run_status": 0,
+				"github_workflow_run_state":  len(workflowRunStates),
+			},
+		},
+		{
+			name:   "compatibility",
+			status: true,
+			state:  false,
+			want: map[string]int{
+				"github_workflow_run_status": 1,
+				"github_workflow_run_state":  0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewWorkflowRunCollector(
+				mockLogger,
+				&github.Client{},
+				mockStore,
+				prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_failures_total"}, []string{"collector"}),
+				prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_duration_seconds"}, []string{"collector"}),
+				config.Target{
+					WorkflowRuns: config.WorkflowRuns{
+						Window:      time.Hour,
+						PurgeWindow: time.Hour,
+						Labels:      config.RunLabels(),
+						Status:      tt.status,
+						State:       tt.state,
+					},
+				},
+			)
+
+			for name, want := range tt.want {
+				if count := testutil.CollectAndCount(collector, name); count != want {
+					t.Errorf("Expected %d series of %s, got %d", want, name, count)
+				}
+			}
+		})
+	}
+
+	collector := NewWorkflowRunCollector(
+		mockLogger,
+		&github.Client{},
+		mockStore,
+		prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_failures_total"}, []string{"collector"}),
+		prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_duration_seconds"}, []string{"collector"}),
+		config.Target{
+			WorkflowRuns: config.WorkflowRuns{
+				Window:      time.Hour,
+				PurgeWindow: time.Hour,
+				Labels:      []string{"owner", "repo", "status", "conclusion"},
+				State:       true,
+			},
+		},
+	)
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP github_workflow_run_state State of workflow runs, 1 for the conclusion if completed, otherwise for the status
+# TYPE github_workflow_run_state gauge
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="action_required",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="cancelled",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="completed",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="failure",status="failure"} 1
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="in_progress",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="neutral",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="pending",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="queued",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="requested",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="skipped",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="stale",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="startup_failure",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="success",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="timed_out",status="failure"} 0
+github_workflow_run_state{conclusion="failure",owner="owner",repo="repo",state="waiting",status="failure"} 0
+`), "github_workflow_run_state"); err != nil {
+		t.Errorf("Unexpected workflow run state: %v", err)
+	}
+
+	if err := mockStore.StoreWorkflowRunEvent(&github.WorkflowRunEvent{
+		Repo: &github.Repository{
+			Name: github.Ptr("repo"),
+			Owner: &github.User{
+				Login: github.Ptr("owner"),
+			},
+		},
+		WorkflowRun: &github.WorkflowRun{
+			ID:           github.Ptr(int64(1)),
+			WorkflowID:   github.Ptr(int64(2)),
+			RunNumber:    github.Ptr(3),
+			RunAttempt:   github.Ptr(2),
+			Name:         github.Ptr("CI"),
+			Event:        github.Ptr("push"),
+			HeadBranch:   github.Ptr("main"),
+			Status:       github.Ptr("completed"),
+			Conclusion:   github.Ptr("success"),
+			CreatedAt:    &github.Timestamp{Time: now.Add(-time.Minute)},
+			RunStartedAt: &github.Timestamp{Time: now},
+			UpdatedAt:    &github.Timestamp{Time: now},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to store workflow run: %v", err)
+	}
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP github_workflow_run_reruns Number of re-run attempts of workflow runs within the window
+# TYPE github_workflow_run_reruns gauge
+github_workflow_run_reruns{owner="owner",repo="repo",workflow="CI"} 1
+`), "github_workflow_run_reruns"); err != nil {
+		t.Errorf("Unexpected workflow run reruns: %v", err)
+	}
+}
+
+func TestStateSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		want    map[string]float64
+	}{
+		{
+			name:    "known state",
+			current: "success",
+			want:    map[string]float64{"failure": 0, "success": 1},
+		},
+		{
+			name:    "unknown state",
+			current: "other",
+			want:    map[string]float64{"failure": 0, "success": 0, "other": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stateSet([]string{"failure", "success"}, tt.current)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d states, got %d", len(tt.want), len(got))
+			}
+
+			for state, want := range tt.want {
+				if got[state] != want {
+					t.Errorf("Expected %s to be %v, got %v", state, want, got[state])
+				}
+			}
+		})
+	}
+}
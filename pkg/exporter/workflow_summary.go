@@ -0,0 +1,178 @@
+package exporter
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/promhippie/github_exporter/pkg/config"
+	"github.com/promhippie/github_exporter/pkg/store"
+)
+
+// WorkflowSummaryCollector collects duration percentiles of workflows.
+type WorkflowSummaryCollector struct {
+	client   *github.Client
+	logger   *slog.Logger
+	db       store.Store
+	failures *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	config   config.Target
+
+	RunDuration *prometheus.Desc
+	RunSuccess  *prometheus.Desc
+	JobDuration *prometheus.Desc
+	JobSuccess  *prometheus.Desc
+}
+
+// NewWorkflowSummaryCollector returns a new WorkflowSummaryCollector.
+func NewWorkflowSummaryCollector(logger *slog.Logger, client *github.Client, db store.Store, failures *prometheus.CounterVec, duration *prometheus.HistogramVec, cfg config.Target) *WorkflowSummaryCollector {
+	if failures != nil {
+		failures.WithLabelValues("workflow_summary").Add(0)
+	}
+
+	runLabels := []string{"owner", "repo", "workflow", "window"}
+	jobLabels := []string{"owner", "repo", "workflow", "name", "window"}
+	return &WorkflowSummaryCollector{
+		client:   client,
+		logger:   logger.With("collector", "workflow_summary"),
+		db:       db,
+		failures: failures,
+		duration: duration,
+		config:   cfg,
+
+		RunDuration: prometheus.NewDesc(
+			"github_workflow_run_duration_summary_seconds",
+			"Summary of the duration of finished workflow runs within the window",
+			runLabels,
+			nil,
+		),
+		RunSuccess: prometheus.NewDesc(
+			"github_workflow_run_success_ratio",
+			"Ratio of successful to finished workflow runs within the window",
+			runLabels,
+			nil,
+		),
+		JobDuration: prometheus.NewDesc(
+			"github_workflow_job_duration_summary_seconds",
+			"Summary of the duration of finished workflow jobs within the window",
+			jobLabels,
+			nil,
+		),
+		JobSuccess: prometheus.NewDesc(
+			"github_workflow_job_success_ratio",
+			"Ratio of successful to finished workflow jobs within the window",
+			jobLabels,
+			nil,
+		),
+	}
+}
+
+// Metrics simply returns the list metric descriptors for generating a documentation.
+func (c *WorkflowSummaryCollector) Metrics() []*prometheus.Desc {
+	return []*prometheus.Desc{
+		c.RunDuration,
+		c.RunSuccess,
+		c.JobDuration,
+		c.JobSuccess,
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics collected by this Collector.
+func (c *WorkflowSummaryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.RunDuration
+	ch <- c.RunSuccess
+	ch <- c.JobDuration
+	ch <- c.JobSuccess
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *WorkflowSummaryCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, name := range c.config.Summaries.Windows {
+		window, err := config.Window(name)
+
+		if err != nil {
+			c.logger.Error("Failed to parse window",
+				"window", name,
+				"err", err,
+			)
+
+			continue
+		}
+
+		now := time.Now()
+		runs, err := c.db.GetWorkflowRunSummaries(window)
+		c.duration.WithLabelValues("workflow_summary").Observe(time.Since(now).Seconds())
+
+		if err != nil {
+			c.logger.Error("Failed to fetch workflow run summaries",
+				"window", name,
+				"err", err,
+			)
+
+			c.failures.WithLabelValues("workflow_summary").Inc()
+		}
+
+		for _, record := range runs {
+			labels := []string{
+				record.Owner,
+				record.Repo,
+				record.Workflow,
+				name,
+			}
+
+			c.collectSummary(ch, c.RunDuration, c.RunSuccess, record, labels)
+		}
+
+		now = time.Now()
+		jobs, err := c.db.GetWorkflowJobSummaries(window)
+		c.duration.WithLabelValues("workflow_summary").Observe(time.Since(now).Seconds())
+
+		if err != nil {
+			c.logger.Error("Failed to fetch workflow job summaries",
+				"window", name,
+				"err", err,
+			)
+
+			c.failures.WithLabelValues("workflow_summary").Inc()
+		}
+
+		for _, record := range jobs {
+			labels := []string{
+				record.Owner,
+				record.Repo,
+				record.Workflow,
+				record.Name,
+				name,
+			}
+
+			c.collectSummary(ch, c.JobDuration, c.JobSuccess, record, labels)
+		}
+	}
+}
+
+func (c *WorkflowSummaryCollector) collectSummary(ch chan<- prometheus.Metric, duration, success *prometheus.Desc, record *store.WorkflowSummary, labels []string) {
+	if record.Count == 0 {
+		return
+	}
+
+	ch <- prometheus.MustNewConstSummary(
+		duration,
+		uint64(record.Count),
+		record.Sum,
+		map[float64]float64{
+			0.5:  record.P50,
+			0.9:  record.P90,
+			0.95: record.P95,
+			0.99: record.P99,
+		},
+		labels...,
+	)
+
+	ch <- prometheus.MustNewConstMetric(
+		success,
+		prometheus.GaugeValue,
+		float64(record.Successful)/float64(record.Count),
+		labels...,
+	)
+}
@@ -0,0 +1,1145 @@
+//go:build bolt
+
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	boltWorkflowRuns = boltTable[WorkflowRun]{
+		name:  []byte("workflow_runs"),
+		index: []byte("workflow_runs_updated_at"),
+		at:    func(r *WorkflowRun) int64 { return r.UpdatedAt },
+	}
+
+	boltWorkflows = boltTable[Workflow]{
+		name: []byte("workflows"),
+	}
+
+	boltRepositories = boltTable[Repository]{
+		name: []byte("repositories"),
+	}
+
+	boltWorkflowJobs = boltTable[WorkflowJob]{
+		name:  []byte("workflow_jobs"),
+		index: []byte("workflow_jobs_created_at"),
+		at:    func(r *WorkflowJob) int64 { return r.CreatedAt },
+	}
+
+	boltWorkflowJobSteps = boltTable[WorkflowJobStep]{
+		name:  []byte("workflow_job_steps"),
+		index: []byte("workflow_job_steps_created_at"),
+		at:    func(r *WorkflowJobStep) int64 { return r.CreatedAt },
+	}
+
+	boltWorkflowTotals = boltTable[WorkflowTotal]{
+		name: []byte("workflow_totals"),
+	}
+
+	boltDurationTotals = boltTable[DurationTotal]{
+		name: []byte("duration_totals"),
+	}
+
+	boltCheckSuites = boltTable[CheckSuite]{
+		name:  []byte("check_suites"),
+		index: []byte("check_suites_updated_at"),
+		at:    func(r *CheckSuite) int64 { return r.UpdatedAt },
+	}
+
+	boltCheckRuns = boltTable[CheckRun]{
+		name:  []byte("check_runs"),
+		index: []byte("check_runs_started_at"),
+		at:    func(r *CheckRun) int64 { return r.StartedAt },
+	}
+
+	boltPullRequests = boltTable[PullRequest]{
+		name:  []byte("pull_requests"),
+		index: []byte("pull_requests_updated_at"),
+		at:    func(r *PullRequest) int64 { return r.UpdatedAt },
+	}
+
+	boltDeployments = boltTable[Deployment]{
+		name:  []byte("deployments"),
+		index: []byte("deployments_created_at"),
+		at:    func(r *Deployment) int64 { return r.CreatedAt },
+	}
+
+	boltWebhookDeliveries = boltTable[WebhookDelivery]{
+		name:  []byte("webhook_deliveries"),
+		index: []byte("webhook_deliveries_created_at"),
+		at:    func(r *WebhookDelivery) int64 { return r.CreatedAt },
+	}
+
+	boltBuckets = [][]byte{
+		boltWorkflowRuns.name,
+		boltWorkflowRuns.index,
+		boltWorkflows.name,
+		boltRepositories.name,
+		boltWorkflowJobs.name,
+		boltWorkflowJobs.index,
+		boltWorkflowJobSteps.name,
+		boltWorkflowJobSteps.index,
+		boltWorkflowTotals.name,
+		boltDurationTotals.name,
+		boltCheckSuites.name,
+		boltCheckSuites.index,
+		boltCheckRuns.name,
+		boltCheckRuns.index,
+		boltPullRequests.name,
+		boltPullRequests.index,
+		boltDeployments.name,
+		boltDeployments.index,
+		boltWebhookDeliveries.name,
+		boltWebhookDeliveries.index,
+	}
+)
+
+type boltStore struct {
+	logger   *slog.Logger
+	database string
+	timeout  time.Duration
+	handle   *bolt.DB
+}
+
+func init() {
+	register("bolt", NewBoltStore)
+}
+
+// Open simply opens the database file.
+func (s *boltStore) Open() (res bool, err error) {
+	if dir := path.Dir(s.database); dir != "." {
+		if err := os.MkdirAll(dir, 0770); err != nil {
+			return false, fmt.Errorf("failed to create database dir: %w", err)
+		}
+	}
+
+	s.handle, err = bolt.Open(
+		s.database,
+		0600,
+		&bolt.Options{
+			Timeout: s.timeout,
+		},
+	)
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Close simply closes the database file.
+func (s *boltStore) Close() error {
+	return s.handle.Close()
+}
+
+// Ping just tests the database file.
+func (s *boltStore) Ping() (bool, error) {
+	if err := s.handle.View(func(_ *bolt.Tx) error {
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Migrate creates all required buckets.
+func (s *boltStore) Migrate() error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		for _, name := range boltBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// StoreWorkflowRunEvent implements the Store interface.
+func (s *boltStore) StoreWorkflowRunEvent(event *github.WorkflowRunEvent) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		record := workflowRunRecord(event)
+		key := boltKey(record.Owner, record.Repo, record.WorkflowID, record.Number, record.Attempt)
+		existing, err := boltWorkflowRuns.get(tx, key)
+
+		if err != nil {
+			return err
+		}
+
+		if workflowRunOutdated(existing, record) {
+			return nil
+		}
+
+		if err := boltWorkflowRuns.put(tx, key, record); err != nil {
+			return err
+		}
+
+		if total := workflowRunTotal(existing, record); total != nil {
+			return boltIncrementWorkflowTotal(tx, total)
+		}
+
+		return nil
+	})
+}
+
+// GetWorkflowRuns implements the Store interface.
+func (s *boltStore) GetWorkflowRuns(window time.Duration) (records []*WorkflowRun, err error) {
+	threshold := time.Now().Add(-window).Unix()
+
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		records, err = boltWorkflowRuns.after(tx, threshold, nil)
+		return err
+	})
+
+	return records, err
+}
+
+// GetPendingWorkflowRuns implements the Store interface.
+func (s *boltStore) GetPendingWorkflowRuns(threshold time.Duration) (records []*WorkflowRun, err error) {
+	before := time.Now().Add(-threshold).Unix()
+	pending := []string{"requested", "queued", "waiting", "pending", "in_progress"}
+
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		records, err = boltWorkflowRuns.before(tx, before, func(r *WorkflowRun) bool {
+			return slices.Contains(pending, r.Status)
+		})
+
+		return err
+	})
+
+	return records, err
+}
+
+// DeleteWorkflowRun implements the Store interface.
+func (s *boltStore) DeleteWorkflowRun(record *WorkflowRun) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return boltWorkflowRuns.delete(tx, boltKey(record.Owner, record.Repo, record.WorkflowID, record.Number, record.Attempt))
+	})
+}
+
+// PruneWorkflowRuns implements the Store interface.
+func (s *boltStore) PruneWorkflowRuns(timeframe time.Duration) error {
+	before := time.Now().Add(-timeframe).Unix()
+
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return boltWorkflowRuns.prune(tx, before, nil)
+	})
+}
+
+// StoreWorkflow implements the Store interface.
+func (s *boltStore) StoreWorkflow(record *Workflow) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return boltWorkflows.put(tx, boltKey(record.Owner, record.Repo, record.Identifier), record)
+	})
+}
+
+// GetWorkflows implements the Store interface.
+func (s *boltStore) GetWorkflows() (records []*Workflow, err error) {
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		records, err = boltWorkflows.all(tx, nil)
+		return err
+	})
+
+	return records, err
+}
+
+// StoreRepository implements the Store interface.
+func (s *boltStore) StoreRepository(record *Repository) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return boltRepositories.put(tx, boltKey(record.Owner, record.Repo), record)
+	})
+}
+
+// GetRepositories implements the Store interface.
+func (s *boltStore) GetRepositories() (records []*Repository, err error) {
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		records, err = boltRepositories.all(tx, nil)
+		return err
+	})
+
+	return records, err
+}
+
+// StoreWorkflowJobEvent implements the Store interface.
+func (s *boltStore) StoreWorkflowJobEvent(event *github.WorkflowJobEvent) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		record := workflowJobRecord(event)
+
+		if err := boltCreateOrUpdateWorkflowJob(tx, record); err != nil {
+			return err
+		}
+
+		for _, step := range workflowJobStepRecords(record, event.GetWorkflowJob()) {
+			key := boltKey(step.Owner, step.Repo, step.JobID, step.Number)
+			existing, err := boltWorkflowJobSteps.get(tx, key)
+
+			if err != nil {
+				return err
+			}
+
+			if workflowJobStepOutdated(existing) {
+				continue
+			}
+
+			if err := boltWorkflowJobSteps.put(tx, key, step); err != nil {
+				return err
+			}
+
+			if duration := workflowJobStepDuration(existing, step); duration != nil {
+				if err := boltIncrementDurationTotal(tx, duration); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func boltCreateOrUpdateWorkflowJob(tx *bolt.Tx, record *WorkflowJob) error {
+	key := boltKey(record.Owner, record.Repo, record.Identifier)
+	existing, err := boltWorkflowJobs.get(tx, key)
+
+	if err != nil {
+		return err
+	}
+
+	if workflowJobOutdated(existing, record) {
+		return nil
+	}
+
+	if err := boltWorkflowJobs.put(tx, key, workflowJobUpdate(existing, record)); err != nil {
+		return err
+	}
+
+	if queue := workflowJobQueue(existing, record); queue != nil {
+		if err := boltIncrementDurationTotal(tx, queue); err != nil {
+			return err
+		}
+	}
+
+	if total := workflowJobTotal(existing, record); total != nil {
+		return boltIncrementWorkflowTotal(tx, total)
+	}
+
+	return nil
+}
+
+// GetWorkflowJobs implements the Store interface.
+func (s *boltStore) GetWorkflowJobs(window time.Duration) (records []*WorkflowJob, err error) {
+	threshold := time.Now().Add(-window).Unix()
+
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		records, err = boltWorkflowJobs.after(tx, threshold, nil)
+		return err
+	})
+
+	return records, err
+}
+
+// GetPendingWorkflowJobs implements the Store interface.
+func (s *boltStore) GetPendingWorkflowJobs(threshold time.Duration) (records []*WorkflowJob, err error) {
+	before := time.Now().Add(-threshold).Unix()
+
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		records, err = boltWorkflowJobs.before(tx, before, func(r *WorkflowJob) bool {
+			return r.Status != "completed"
+		})
+
+		return err
+	})
+
+	return records, err
+}
+
+// DeleteWorkflowJob implements the Store interface.
+func (s *boltStore) DeleteWorkflowJob(record *WorkflowJob) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		if err := boltWorkflowJobs.delete(tx, boltKey(record.Owner, record.Repo, record.Identifier)); err != nil {
+			return err
+		}
+
+		steps, err := boltWorkflowJobSteps.all(tx, func(r *WorkflowJobStep) bool {
+			return r.Owner == record.Owner && r.Repo == record.Repo && r.JobID == record.Identifier
+		})
+
+		if err != nil {
+			return err
+		}
+
+		for _, step := range steps {
+			if err := boltWorkflowJobSteps.delete(tx, boltKey(step.Owner, step.Repo, step.JobID, step.Number)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// PruneWorkflowJobs implements the Store interface.
+func (s *boltStore) PruneWorkflowJobs(timeframe time.Duration) error {
+	before := time.Now().Add(-timeframe).Unix()
+
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		if err := boltWorkflowJobs.prune(tx, before, nil); err != nil {
+			return err
+		}
+
+		return boltWorkflowJobSteps.prune(tx, before, nil)
+	})
+}
+
+// GetWorkflowJobSteps implements the Store interface.
+func (s *boltStore) GetWorkflowJobSteps(window time.Duration) (records []*WorkflowJobStep, err error) {
+	threshold := time.Now().Add(-window).Unix()
+
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		records, err = boltWorkflowJobSteps.after(tx, threshold, nil)
+		return err
+	})
+
+	return records, err
+}
+
+// GetWorkflowJobFlakes implements the Store interface.
+func (s *boltStore) GetWorkflowJobFlakes(window time.Duration) (records []*WorkflowJobFlake, err error) {
+	threshold := time.Now().Add(-window).Unix()
+
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		// Jobs are indexed by their creation, but the window applies to the
+		// completion, so we can't seek within the index here.
+		jobs, err := boltWorkflowJobs.after(tx, 0, func(r *WorkflowJob) bool {
+			return r.Status == "completed" && r.CompletedAt > threshold
+		})
+
+		records = detectWorkflowJobFlakes(jobs)
+		return err
+	})
+
+	return records, err
+}
+
+// GetWorkflowTotals implements the Store interface.
+func (s *boltStore) GetWorkflowTotals() (records []*WorkflowTotal, err error) {
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		records, err = boltWorkflowTotals.all(tx, nil)
+		return err
+	})
+
+	return records, err
+}
+
+func boltIncrementWorkflowTotal(tx *bolt.Tx, record *WorkflowTotal) error {
+	record.Bucket = totalBucket(record.Duration)
+
+	key := boltKey(record.Kind, record.Owner, record.Repo, record.Workflow, record.Conclusion, record.Bucket)
+	existing, err := boltWorkflowTotals.get(tx, key)
+
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		existing.Count++
+		existing.Duration += record.Duration
+
+		return boltWorkflowTotals.put(tx, key, existing)
+	}
+
+	record.Count = 1
+	return boltWorkflowTotals.put(tx, key, record)
+}
+
+// GetDurationTotals implements the Store interface.
+func (s *boltStore) GetDurationTotals(kind string) (records []*DurationTotal, err error) {
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		records, err = boltDurationTotals.all(tx, func(r *DurationTotal) bool {
+			return r.Kind == kind
+		})
+
+		return err
+	})
+
+	return records, err
+}
+
+func boltIncrementDurationTotal(tx *bolt.Tx, record *DurationTotal) error {
+	key := boltKey(record.Kind, record.Labels, record.Bucket)
+	existing, err := boltDurationTotals.get(tx, key)
+
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		existing.Count++
+		existing.Duration += record.Duration
+
+		return boltDurationTotals.put(tx, key, existing)
+	}
+
+	return boltDurationTotals.put(tx, key, record)
+}
+
+// GetWorkflowRunSummaries implements the Store interface.
+func (s *boltStore) GetWorkflowRunSummaries(window time.Duration) (records []*WorkflowSummary, err error) {
+	threshold := time.Now().Add(-window).Unix()
+
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		runs, err := boltWorkflowRuns.after(tx, threshold, func(r *WorkflowRun) bool {
+			return r.Status == "completed"
+		})
+
+		if err != nil {
+			return err
+		}
+
+		durations := make([]workflowDuration, 0, len(runs))
+
+		for _, record := range runs {
+			durations = append(durations, workflowDuration{
+				Owner:      record.Owner,
+				Repo:       record.Repo,
+				Workflow:   record.Name,
+				Conclusion: record.Conclusion,
+				Duration:   record.UpdatedAt - record.StartedAt,
+			})
+		}
+
+		records = summarizeWorkflowDurations(durations)
+		return nil
+	})
+
+	return records, err
+}
+
+// GetWorkflowJobSummaries implements the Store interface.
+func (s *boltStore) GetWorkflowJobSummaries(window time.Duration) (records []*WorkflowSummary, err error) {
+	threshold := time.Now().Add(-window).Unix()
+
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		// Jobs are indexed by their creation, but the window applies to the
+		// completion, so we can't seek within the index here.
+		jobs, err := boltWorkflowJobs.after(tx, 0, func(r *WorkflowJob) bool {
+			return r.Status == "completed" && r.StartedAt > 0 && r.CompletedAt > threshold
+		})
+
+		if err != nil {
+			return err
+		}
+
+		durations := make([]workflowDuration, 0, len(jobs))
+
+		for _, record := range jobs {
+			durations = append(durations, workflowDuration{
+				Owner:      record.Owner,
+				Repo:       record.Repo,
+				Workflow:   record.WorkflowName,
+				Name:       record.Name,
+				Conclusion: record.Conclusion,
+				Duration:   record.CompletedAt - record.StartedAt,
+			})
+		}
+
+		records = summarizeWorkflowDurations(durations)
+		return nil
+	})
+
+	return records, err
+}
+
+// StoreCheckSuiteEvent implements the Store interface.
+func (s *boltStore) StoreCheckSuiteEvent(event *github.CheckSuiteEvent) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		record := checkSuiteRecord(event)
+		key := boltKey(record.Owner, record.Repo, record.Identifier)
+		existing, err := boltCheckSuites.get(tx, key)
+
+		if err != nil {
+			return err
+		}
+
+		if checkSuiteOutdated(existing, record) {
+			return nil
+		}
+
+		return boltCheckSuites.put(tx, key, record)
+	})
+}
+
+// GetCheckSuites implements the Store interface.
+func (s *boltStore) GetCheckSuites(window time.Duration) (records []*CheckSuite, err error) {
+	threshold := time.Now().Add(-window).Unix()
+
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		records, err = boltCheckSuites.after(tx, threshold, nil)
+		return err
+	})
+
+	return records, err
+}
+
+// PruneCheckSuites implements the Store interface.
+func (s *boltStore) PruneCheckSuites(timeframe time.Duration) error {
+	before := time.Now().Add(-timeframe).Unix()
+
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return boltCheckSuites.prune(tx, before, nil)
+	})
+}
+
+// StoreCheckRunEvent implements the Store interface.
+func (s *boltStore) StoreCheckRunEvent(event *github.CheckRunEvent) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		record := checkRunRecord(event)
+		key := boltKey(record.Owner, record.Repo, record.Identifier)
+		existing, err := boltCheckRuns.get(tx, key)
+
+		if err != nil {
+			return err
+		}
+
+		if checkRunOutdated(existing, record) {
+			return nil
+		}
+
+		return boltCheckRuns.put(tx, key, record)
+	})
+}
+
+// GetCheckRuns implements the Store interface.
+func (s *boltStore) GetCheckRuns(window time.Duration) (records []*CheckRun, err error) {
+	threshold := time.Now().Add(-window).Unix()
+
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		records, err = boltCheckRuns.after(tx, threshold, nil)
+		return err
+	})
+
+	return records, err
+}
+
+// PruneCheckRuns implements the Store interface.
+func (s *boltStore) PruneCheckRuns(timeframe time.Duration) error {
+	before := time.Now().Add(-timeframe).Unix()
+
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return boltCheckRuns.prune(tx, before, nil)
+	})
+}
+
+// StorePullRequestEvent implements the Store interface.
+func (s *boltStore) StorePullRequestEvent(event *github.PullRequestEvent) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return boltCreateOrUpdatePullRequest(
+			tx,
+			pullRequestRecord(event.GetRepo(), event.GetPullRequest()),
+		)
+	})
+}
+
+// StorePullRequestReviewEvent implements the Store interface.
+func (s *boltStore) StorePullRequestReviewEvent(event *github.PullRequestReviewEvent) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		record := pullRequestRecord(event.GetRepo(), event.GetPullRequest())
+
+		if err := boltCreateOrUpdatePullRequest(tx, record); err != nil {
+			return err
+		}
+
+		key := boltKey(record.Owner, record.Repo, record.Number)
+		existing, err := boltPullRequests.get(tx, key)
+
+		if err != nil {
+			return err
+		}
+
+		submittedAt := pullRequestReviewAt(event)
+
+		if !pullRequestFirstReview(existing, submittedAt) {
+			return nil
+		}
+
+		if review := pullRequestReview(existing, record, submittedAt); review != nil {
+			if err := boltIncrementDurationTotal(tx, review); err != nil {
+				return err
+			}
+		}
+
+		existing.FirstReviewAt = submittedAt
+		return boltPullRequests.put(tx, key, existing)
+	})
+}
+
+func boltCreateOrUpdatePullRequest(tx *bolt.Tx, record *PullRequest) error {
+	key := boltKey(record.Owner, record.Repo, record.Number)
+	existing, err := boltPullRequests.get(tx, key)
+
+	if err != nil {
+		return err
+	}
+
+	if pullRequestOutdated(existing, record) {
+		return nil
+	}
+
+	if existing != nil {
+		record.FirstReviewAt = existing.FirstReviewAt
+	}
+
+	if err := boltPullRequests.put(tx, key, record); err != nil {
+		return err
+	}
+
+	if merge := pullRequestMerge(existing, record); merge != nil {
+		return boltIncrementDurationTotal(tx, merge)
+	}
+
+	return nil
+}
+
+// GetPullRequests implements the Store interface.
+func (s *boltStore) GetPullRequests(window time.Duration) (records []*PullRequest, err error) {
+	threshold := time.Now().Add(-window).Unix()
+
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		// Open pull requests are always included, so we can't seek within the
+		// index here.
+		records, err = boltPullRequests.after(tx, 0, func(r *PullRequest) bool {
+			return r.State == "open" || r.UpdatedAt > threshold
+		})
+
+		return err
+	})
+
+	return records, err
+}
+
+// PrunePullRequests implements the Store interface.
+func (s *boltStore) PrunePullRequests(timeframe time.Duration) error {
+	before := time.Now().Add(-timeframe).Unix()
+
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return boltPullRequests.prune(tx, before, func(r *PullRequest) bool {
+			return r.State != "open"
+		})
+	})
+}
+
+// StoreDeploymentEvent implements the Store interface.
+func (s *boltStore) StoreDeploymentEvent(event *github.DeploymentEvent) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return boltCreateOrUpdateDeployment(
+			tx,
+			deploymentRecord(event.GetRepo(), event.GetDeployment()),
+		)
+	})
+}
+
+// StoreDeploymentStatusEvent implements the Store interface.
+func (s *boltStore) StoreDeploymentStatusEvent(event *github.DeploymentStatusEvent) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		record := deploymentRecord(event.GetRepo(), event.GetDeployment())
+
+		if err := boltCreateOrUpdateDeployment(tx, record); err != nil {
+			return err
+		}
+
+		key := boltKey(record.Owner, record.Repo, record.Identifier)
+		existing, err := boltDeployments.get(tx, key)
+
+		if err != nil {
+			return err
+		}
+
+		status, statusAt, ok := deploymentStatus(event)
+
+		if !ok || deploymentStatusOutdated(existing, statusAt) {
+			return nil
+		}
+
+		updated := *existing
+		updated.Status = status
+		updated.StatusAt = statusAt
+
+		if deploymentSucceeded(existing, &updated) {
+			if err := boltIncrementDeploymentTotals(tx, &updated); err != nil {
+				return err
+			}
+		}
+
+		return boltDeployments.put(tx, key, &updated)
+	})
+}
+
+func boltIncrementDeploymentTotals(tx *bolt.Tx, record *Deployment) error {
+	runs, err := boltWorkflowRuns.all(tx, func(r *WorkflowRun) bool {
+		return r.Owner == record.Owner && r.Repo == record.Repo && r.SHA == record.SHA
+	})
+
+	if err != nil {
+		return err
+	}
+
+	commits := make([]int64, 0, len(runs))
+
+	for _, run := range runs {
+		commits = append(commits, run.CommitAt)
+	}
+
+	previous, err := boltDeployments.all(tx, func(r *Deployment) bool {
+		return r.Owner == record.Owner && r.Repo == record.Repo && r.Environment == record.Environment
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, total := range []*DurationTotal{
+		deploymentLeadTime(record, commits),
+		deploymentRestore(record, previous),
+	} {
+		if total == nil {
+			continue
+		}
+
+		if err := boltIncrementDurationTotal(tx, total); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func boltCreateOrUpdateDeployment(tx *bolt.Tx, record *Deployment) error {
+	key := boltKey(record.Owner, record.Repo, record.Identifier)
+	existing, err := boltDeployments.get(tx, key)
+
+	if err != nil {
+		return err
+	}
+
+	if deploymentOutdated(existing, record) {
+		return nil
+	}
+
+	if existing != nil {
+		record.Status = existing.Status
+		record.StatusAt = existing.StatusAt
+	}
+
+	return boltDeployments.put(tx, key, record)
+}
+
+// GetDeployments implements the Store interface.
+func (s *boltStore) GetDeployments(window time.Duration) (records []*Deployment, err error) {
+	threshold := time.Now().Add(-window).Unix()
+
+	err = s.handle.View(func(tx *bolt.Tx) error {
+		records, err = boltDeployments.after(tx, threshold, nil)
+		return err
+	})
+
+	return records, err
+}
+
+// PruneDeployments implements the Store interface.
+func (s *boltStore) PruneDeployments(timeframe time.Duration) error {
+	before := time.Now().Add(-timeframe).Unix()
+
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return boltDeployments.prune(tx, before, nil)
+	})
+}
+
+// ReserveWebhookDelivery implements the Store interface.
+func (s *boltStore) ReserveWebhookDelivery(record *WebhookDelivery) (res bool, err error) {
+	err = s.handle.Update(func(tx *bolt.Tx) error {
+		key := []byte(record.Delivery)
+		existing, err := boltWebhookDeliveries.get(tx, key)
+
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			return nil
+		}
+
+		res = true
+		return boltWebhookDeliveries.put(tx, key, record)
+	})
+
+	return res, err
+}
+
+// ReleaseWebhookDelivery implements the Store interface.
+func (s *boltStore) ReleaseWebhookDelivery(delivery string) error {
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return boltWebhookDeliveries.delete(tx, []byte(delivery))
+	})
+}
+
+// PruneWebhookDeliveries implements the Store interface.
+func (s *boltStore) PruneWebhookDeliveries(timeframe time.Duration) error {
+	before := time.Now().Add(-timeframe).Unix()
+
+	return s.handle.Update(func(tx *bolt.Tx) error {
+		return boltWebhookDeliveries.prune(tx, before, nil)
+	})
+}
+
+// boltTable maps records to a bucket, optionally together with an index bucket
+// ordered by the timestamp returned by the at function.
+type boltTable[T any] struct {
+	name  []byte
+	index []byte
+	at    func(*T) int64
+}
+
+// get returns the record for the key, or nil if it doesn't exist.
+func (t boltTable[T]) get(tx *bolt.Tx, key []byte) (*T, error) {
+	value := tx.Bucket(t.name).Get(key)
+
+	if value == nil {
+		return nil, nil
+	}
+
+	record := new(T)
+
+	if err := json.Unmarshal(value, record); err != nil {
+		return nil, fmt.Errorf("failed to decode %s record: %w", t.name, err)
+	}
+
+	return record, nil
+}
+
+// put stores the record and moves its index entry to the current timestamp.
+func (t boltTable[T]) put(tx *bolt.Tx, key []byte, record *T) error {
+	if t.index != nil {
+		existing, err := t.get(tx, key)
+
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			if err := tx.Bucket(t.index).Delete(boltIndexKey(t.at(existing), key)); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Bucket(t.index).Put(boltIndexKey(t.at(record), key), key); err != nil {
+			return err
+		}
+	}
+
+	value, err := json.Marshal(record)
+
+	if err != nil {
+		return fmt.Errorf("failed to encode %s record: %w", t.name, err)
+	}
+
+	return tx.Bucket(t.name).Put(key, value)
+}
+
+// delete removes the record together with its index entry.
+func (t boltTable[T]) delete(tx *bolt.Tx, key []byte) error {
+	if t.index != nil {
+		existing, err := t.get(tx, key)
+
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			if err := tx.Bucket(t.index).Delete(boltIndexKey(t.at(existing), key)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Bucket(t.name).Delete(key)
+}
+
+// all returns all matching records ordered by their key.
+func (t boltTable[T]) all(tx *bolt.Tx, match func(*T) bool) ([]*T, error) {
+	result := make([]*T, 0)
+
+	err := tx.Bucket(t.name).ForEach(func(_, value []byte) error {
+		record := new(T)
+
+		if err := json.Unmarshal(value, record); err != nil {
+			return fmt.Errorf("failed to decode %s record: %w", t.name, err)
+		}
+
+		if match == nil || match(record) {
+			result = append(result, record)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// after returns all matching records with a timestamp newer than the threshold
+// ordered ascending by the timestamp.
+func (t boltTable[T]) after(tx *bolt.Tx, threshold int64, match func(*T) bool) ([]*T, error) {
+	result := make([]*T, 0)
+
+	err := t.scan(tx, threshold+1, math.MaxInt64, func(_, _ []byte, record *T) error {
+		if match == nil || match(record) {
+			result = append(result, record)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// before returns all matching records with a timestamp older than the
+// threshold ordered ascending by the timestamp.
+func (t boltTable[T]) before(tx *bolt.Tx, threshold int64, match func(*T) bool) ([]*T, error) {
+	result := make([]*T, 0)
+
+	err := t.scan(tx, 0, threshold, func(_, _ []byte, record *T) error {
+		if match == nil || match(record) {
+			result = append(result, record)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// prune deletes all matching records with a timestamp older than the threshold.
+func (t boltTable[T]) prune(tx *bolt.Tx, threshold int64, match func(*T) bool) error {
+	keys := make([][2][]byte, 0)
+
+	// Deleting while iterating would move the cursor, so we collect the keys
+	// first and delete them afterwards.
+	if err := t.scan(tx, 0, threshold, func(k, key []byte, record *T) error {
+		if match == nil || match(record) {
+			keys = append(keys, [2][]byte{k, key})
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, pair := range keys {
+		if err := tx.Bucket(t.index).Delete(pair[0]); err != nil {
+			return err
+		}
+
+		if err := tx.Bucket(t.name).Delete(pair[1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scan walks through the index from the timestamp up to, but excluding, the
+// limit and passes the index key, the primary key and the record to fn.
+func (t boltTable[T]) scan(tx *bolt.Tx, from, limit int64, fn func(k, key []byte, record *T) error) error {
+	cursor := tx.Bucket(t.index).Cursor()
+	stop := boltTimestamp(limit)
+
+	for k, key := cursor.Seek(boltTimestamp(from)); k != nil && bytes.Compare(k[:8], stop) < 0; k, key = cursor.Next() {
+		record, err := t.get(tx, key)
+
+		if err != nil {
+			return err
+		}
+
+		if record == nil {
+			continue
+		}
+
+		if err := fn(slices.Clone(k), slices.Clone(key), record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// boltKey builds a primary key from the given parts, strings get terminated by
+// a null byte and integers get encoded big endian to keep the ordering.
+func boltKey(parts ...any) []byte {
+	key := make([]byte, 0, 64)
+
+	for _, part := range parts {
+		switch val := part.(type) {
+		case string:
+			key = append(key, val...)
+			key = append(key, 0)
+		case int:
+			key = binary.BigEndian.AppendUint64(key, uint64(val))
+		case int64:
+			key = binary.BigEndian.AppendUint64(key, uint64(val))
+		}
+	}
+
+	return key
+}
+
+// boltTimestamp encodes a timestamp big endian, negative timestamps are
+// clamped to zero to keep the ordering.
+func boltTimestamp(at int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(max(at, 0)))
+}
+
+// boltIndexKey builds an index key from the timestamp and the primary key.
+func boltIndexKey(at int64, key []byte) []byte {
+	return append(boltTimestamp(at), key...)
+}
+
+// NewBoltStore initializes a new bbolt store.
+func NewBoltStore(dsn string, logger *slog.Logger) (Store, error) {
+	parsed, err := url.Parse(dsn)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dsn: %w", err)
+	}
+
+	client := &boltStore{
+		logger:   logger,
+		database: path.Join(parsed.Host, parsed.Path),
+		timeout:  5 * time.Second,
+	}
+
+	if val := parsed.Query().Get("timeout"); val != "" {
+		timeout, err := time.ParseDuration(val)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse timeout: %w", err)
+		}
+
+		client.timeout = timeout
+	}
+
+	return client, nil
+}
@@ -0,0 +1,215 @@
+//go:build bolt
+
+package store
+
+import (
+	"log/slog"
+	"os"
+	"path"
+	"slices"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBoltStore(t *testing.T) {
+	s, err := New("bolt://"+path.Join(t.TempDir(), "exporter.db"), slog.New(slog.NewTextHandler(os.Stdout, nil)))
+
+	if err != nil {
+		t.Fatalf("Failed to setup store: %v", err)
+	}
+
+	if _, err := s.Open(); err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	defer s.Close()
+
+	if err := s.Migrate(); err != nil {
+		t.Fatalf("Failed to migrate store: %v", err)
+	}
+
+	testStore(t, s)
+}
+
+func TestBoltTable(t *testing.T) {
+	table := boltTable[CheckSuite]{
+		name:  []byte("records"),
+		index: []byte("records_updated_at"),
+		at:    func(r *CheckSuite) int64 { return r.UpdatedAt },
+	}
+
+	record := func(id, updatedAt int64) *CheckSuite {
+		return &CheckSuite{
+			Owner:      "owner",
+			Repo:       "repo",
+			Identifier: id,
+			UpdatedAt:  updatedAt,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		records []*CheckSuite
+		prune   int64
+		after   int64
+		before  int64
+		want    []int64
+		older   []int64
+		count   int
+	}{
+		{
+			name:   "empty",
+			after:  0,
+			before: 100,
+			want:   []int64{},
+			older:  []int64{},
+			count:  0,
+		},
+		{
+			name: "ordered by timestamp",
+			records: []*CheckSuite{
+				record(1, 30),
+				record(2, 10),
+				record(3, 20),
+			},
+			after:  0,
+			before: 100,
+			want:   []int64{2, 3, 1},
+			older:  []int64{2, 3, 1},
+			count:  3,
+		},
+		{
+			name: "upsert moves index",
+			records: []*CheckSuite{
+				record(1, 10),
+				record(2, 20),
+				record(1, 30),
+			},
+			after:  15,
+			before: 25,
+			want:   []int64{2, 1},
+			older:  []int64{2},
+			count:  2,
+		},
+		{
+			name: "boundaries are exclusive",
+			records: []*CheckSuite{
+				record(1, 10),
+				record(2, 20),
+				record(3, 30),
+			},
+			after:  10,
+			before: 30,
+			want:   []int64{2, 3},
+			older:  []int64{1, 2},
+			count:  3,
+		},
+		{
+			name: "prune deletes record and index",
+			records: []*CheckSuite{
+				record(1, 10),
+				record(2, 20),
+				record(3, 30),
+			},
+			prune:  20,
+			after:  0,
+			before: 100,
+			want:   []int64{2, 3},
+			older:  []int64{2, 3},
+			count:  2,
+		},
+		{
+			name: "prune after upsert",
+			records: []*CheckSuite{
+				record(1, 10),
+				record(1, 40),
+				record(2, 20),
+			},
+			prune:  30,
+			after:  0,
+			before: 100,
+			want:   []int64{1},
+			older:  []int64{1},
+			count:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := bolt.Open(path.Join(t.TempDir(), "test.db"), 0600, nil)
+
+			if err != nil {
+				t.Fatalf("Failed to open database: %v", err)
+			}
+
+			defer db.Close()
+
+			if err := db.Update(func(tx *bolt.Tx) error {
+				for _, name := range [][]byte{table.name, table.index} {
+					if _, err := tx.CreateBucket(name); err != nil {
+						return err
+					}
+				}
+
+				for _, r := range tt.records {
+					if err := table.put(tx, boltKey(r.Owner, r.Repo, r.Identifier), r); err != nil {
+						return err
+					}
+				}
+
+				if tt.prune > 0 {
+					return table.prune(tx, tt.prune, nil)
+				}
+
+				return nil
+			}); err != nil {
+				t.Fatalf("Failed to write records: %v", err)
+			}
+
+			if err := db.View(func(tx *bolt.Tx) error {
+				after, err := table.after(tx, tt.after, nil)
+
+				if err != nil {
+					return err
+				}
+
+				if got := boltTestIdentifiers(after); !slices.Equal(got, tt.want) {
+					t.Errorf("Expected after to return %v, got %v", tt.want, got)
+				}
+
+				before, err := table.before(tx, tt.before, nil)
+
+				if err != nil {
+					return err
+				}
+
+				if got := boltTestIdentifiers(before); !slices.Equal(got, tt.older) {
+					t.Errorf("Expected before to return %v, got %v", tt.older, got)
+				}
+
+				if count := tx.Bucket(table.name).Stats().KeyN; count != tt.count {
+					t.Errorf("Expected %d records, got %d", tt.count, count)
+				}
+
+				if count := tx.Bucket(table.index).Stats().KeyN; count != tt.count {
+					t.Errorf("Expected %d index entries, got %d", tt.count, count)
+				}
+
+				return nil
+			}); err != nil {
+				t.Fatalf("Failed to read records: %v", err)
+			}
+		})
+	}
+}
+
+func boltTestIdentifiers(records []*CheckSuite) []int64 {
+	result := make([]int64, 0, len(records))
+
+	for _, record := range records {
+		result = append(result, record.Identifier)
+	}
+
+	return result
+}
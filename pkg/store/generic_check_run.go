@@ -0,0 +1,216 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/jmoiron/sqlx"
+)
+
+// storeCheckRunEvent handles check_run events from GitHub.
+func storeCheckRunEvent(handle *sqlx.DB, event *github.CheckRunEvent) error {
+	return createOrUpdateCheckRun(
+		handle,
+		checkRunRecord(event),
+	)
+}
+
+// checkRunRecord maps the check run payload to a record.
+func checkRunRecord(event *github.CheckRunEvent) *CheckRun {
+	run := event.GetCheckRun()
+
+	return &CheckRun{
+		Owner:       event.GetRepo().GetOwner().GetLogin(),
+		Repo:        event.GetRepo().GetName(),
+		Identifier:  run.GetID(),
+		SuiteID:     run.GetCheckSuite().GetID(),
+		Name:        run.GetName(),
+		App:         run.GetApp().GetSlug(),
+		Status:      run.GetStatus(),
+		Conclusion:  run.GetConclusion(),
+		Branch:      run.GetCheckSuite().GetHeadBranch(),
+		SHA:         run.GetHeadSHA(),
+		StartedAt:   run.GetStartedAt().Time.Unix(),
+		CompletedAt: run.GetCompletedAt().Time.Unix(),
+	}
+}
+
+// checkRunOutdated checks if the record would downgrade the existing one.
+// Check runs don't provide an updated timestamp, so we simply never downgrade
+// a completed check run by an out of order delivery.
+func checkRunOutdated(existing, record *CheckRun) bool {
+	return existing != nil && existing.Status == "completed" && record.Status != "completed"
+}
+
+// createOrUpdateCheckRun creates or updates the record.
+func createOrUpdateCheckRun(handle *sqlx.DB, record *CheckRun) error {
+	existing := &CheckRun{}
+	stmt, err := handle.PrepareNamed(findCheckRunQuery)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to prepare find: %w", err)
+	}
+
+	if err := stmt.Get(existing, record); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to find record: %w", err)
+	}
+
+	if existing.Identifier == 0 {
+		if _, err := handle.NamedExec(
+			createCheckRunQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to create record: %w", err)
+		}
+	} else {
+		if checkRunOutdated(existing, record) {
+			return nil
+		}
+
+		if _, err := handle.NamedExec(
+			updateCheckRunQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to update record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// getCheckRuns retrieves the check runs from the database.
+func getCheckRuns(handle *sqlx.DB, window time.Duration) ([]*CheckRun, error) {
+	records := make([]*CheckRun, 0)
+
+	rows, err := handle.NamedQuery(
+		selectCheckRunsQuery,
+		map[string]interface{}{
+			"window": time.Now().Add(-window).Unix(),
+		},
+	)
+
+	if err != nil {
+		return records, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &CheckRun{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return records, err
+		}
+
+		records = append(
+			records,
+			record,
+		)
+	}
+
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+// pruneCheckRuns prunes older check run records.
+func pruneCheckRuns(handle *sqlx.DB, timeframe time.Duration) error {
+	if _, err := handle.NamedExec(
+		purgeCheckRunsQuery,
+		map[string]interface{}{
+			"timeframe": time.Now().Add(-timeframe).Unix(),
+		},
+	); err != nil {
+		return fmt.Errorf("failed to prune check runs: %w", err)
+	}
+
+	return nil
+}
+
+var selectCheckRunsQuery = `
+SELECT
+	owner,
+	repo,
+	identifier,
+	suite_id,
+	name,
+	app,
+	status,
+	conclusion,
+	branch,
+	sha,
+	started_at,
+	completed_at
+FROM
+	check_runs
+WHERE
+	started_at > :window
+ORDER BY
+	started_at ASC;`
+
+var findCheckRunQuery = `
+SELECT
+	identifier,
+	status
+FROM
+	check_runs
+WHERE
+	owner=:owner AND repo=:repo AND identifier=:identifier;`
+
+var createCheckRunQuery = `
+INSERT INTO check_runs (
+	owner,
+	repo,
+	identifier,
+	suite_id,
+	name,
+	app,
+	status,
+	conclusion,
+	branch,
+	sha,
+	started_at,
+	completed_at
+) VALUES (
+	:owner,
+	:repo,
+	:identifier,
+	:suite_id,
+	:name,
+	:app,
+	:status,
+	:conclusion,
+	:branch,
+	:sha,
+	:started_at,
+	:completed_at
+);`
+
+var updateCheckRunQuery = `
+UPDATE
+	check_runs
+SET
+	suite_id=:suite_id,
+	name=:name,
+	app=:app,
+	status=:status,
+	conclusion=:conclusion,
+	branch=:branch,
+	sha=:sha,
+	started_at=:started_at,
+	completed_at=:completed_at
+WHERE
+	owner=:owner AND repo=:repo AND identifier=:identifier;`
+
+var purgeCheckRunsQuery = `
+DELETE FROM
+	check_runs
+WHERE
+	started_at < :timeframe;`
@@ -0,0 +1,215 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/jmoiron/sqlx"
+)
+
+// storeCheckSuiteEvent handles check_suite events from GitHub.
+func storeCheckSuiteEvent(handle *sqlx.DB, event *github.CheckSuiteEvent) error {
+	return createOrUpdateCheckSuite(
+		handle,
+		checkSuiteRecord(event),
+	)
+}
+
+// checkSuiteRecord maps the check suite payload to a record.
+func checkSuiteRecord(event *github.CheckSuiteEvent) *CheckSuite {
+	suite := event.GetCheckSuite()
+
+	return &CheckSuite{
+		Owner:      event.GetRepo().GetOwner().GetLogin(),
+		Repo:       event.GetRepo().GetName(),
+		Identifier: suite.GetID(),
+		App:        suite.GetApp().GetSlug(),
+		Status:     suite.GetStatus(),
+		Conclusion: suite.GetConclusion(),
+		Branch:     suite.GetHeadBranch(),
+		SHA:        suite.GetHeadSHA(),
+		CreatedAt:  suite.GetCreatedAt().Time.Unix(),
+		UpdatedAt:  suite.GetUpdatedAt().Time.Unix(),
+	}
+}
+
+// checkSuiteOutdated checks if the record is older than the existing one. The
+// updated timestamp is in seconds, so a completed suite doesn't get overwritten
+// by a record with the same timestamp either.
+func checkSuiteOutdated(existing, record *CheckSuite) bool {
+	if existing == nil {
+		return false
+	}
+
+	if existing.UpdatedAt > record.UpdatedAt {
+		return true
+	}
+
+	return existing.UpdatedAt == record.UpdatedAt && existing.Status == "completed"
+}
+
+// createOrUpdateCheckSuite creates or updates the record.
+func createOrUpdateCheckSuite(handle *sqlx.DB, record *CheckSuite) error {
+	existing := &CheckSuite{}
+	stmt, err := handle.PrepareNamed(findCheckSuiteQuery)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to prepare find: %w", err)
+	}
+
+	if err := stmt.Get(existing, record); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to find record: %w", err)
+	}
+
+	if existing.Identifier == 0 {
+		if _, err := handle.NamedExec(
+			createCheckSuiteQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to create record: %w", err)
+		}
+	} else {
+		if checkSuiteOutdated(existing, record) {
+			return nil
+		}
+
+		if _, err := handle.NamedExec(
+			updateCheckSuiteQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to update record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// getCheckSuites retrieves the check suites from the database.
+func getCheckSuites(handle *sqlx.DB, window time.Duration) ([]*CheckSuite, error) {
+	records := make([]*CheckSuite, 0)
+
+	rows, err := handle.NamedQuery(
+		selectCheckSuitesQuery,
+		map[string]interface{}{
+			"window": time.Now().Add(-window).Unix(),
+		},
+	)
+
+	if err != nil {
+		return records, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &CheckSuite{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return records, err
+		}
+
+		records = append(
+			records,
+			record,
+		)
+	}
+
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+// pruneCheckSuites prunes older check suite records.
+func pruneCheckSuites(handle *sqlx.DB, timeframe time.Duration) error {
+	if _, err := handle.NamedExec(
+		purgeCheckSuitesQuery,
+		map[string]interface{}{
+			"timeframe": time.Now().Add(-timeframe).Unix(),
+		},
+	); err != nil {
+		return fmt.Errorf("failed to prune check suites: %w", err)
+	}
+
+	return nil
+}
+
+var selectCheckSuitesQuery = `
+SELECT
+	owner,
+	repo,
+	identifier,
+	app,
+	status,
+	conclusion,
+	branch,
+	sha,
+	created_at,
+	updated_at
+FROM
+	check_suites
+WHERE
+	updated_at > :window
+ORDER BY
+	updated_at ASC;`
+
+var findCheckSuiteQuery = `
+SELECT
+	identifier,
+	status,
+	updated_at
+FROM
+	check_suites
+WHERE
+	owner=:owner AND repo=:repo AND identifier=:identifier;`
+
+var createCheckSuiteQuery = `
+INSERT INTO check_suites (
+	owner,
+	repo,
+	identifier,
+	app,
+	status,
+	conclusion,
+	branch,
+	sha,
+	created_at,
+	updated_at
+) VALUES (
+	:owner,
+	:repo,
+	:identifier,
+	:app,
+	:status,
+	:conclusion,
+	:branch,
+	:sha,
+	:created_at,
+	:updated_at
+);`
+
+var updateCheckSuiteQuery = `
+UPDATE
+	check_suites
+SET
+	app=:app,
+	status=:status,
+	conclusion=:conclusion,
+	branch=:branch,
+	sha=:sha,
+	created_at=:created_at,
+	updated_at=:updated_at
+WHERE
+	owner=:owner AND repo=:repo AND identifier=:identifier;`
+
+var purgeCheckSuitesQuery = `
+DELETE FROM
+	check_suites
+WHERE
+	updated_at < :timeframe;`
@@ -0,0 +1,410 @@
+package store
+
+import (
+	"cmp"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/jmoiron/sqlx"
+)
+
+// storeDeploymentEvent handles deployment events from GitHub.
+func storeDeploymentEvent(handle *sqlx.DB, event *github.DeploymentEvent) error {
+	return withTransaction(handle, func(tx *sqlx.Tx) error {
+		_, err := createOrUpdateDeployment(
+			tx,
+			deploymentRecord(event.GetRepo(), event.GetDeployment()),
+		)
+
+		return err
+	})
+}
+
+// storeDeploymentStatusEvent handles deployment_status events from GitHub.
+func storeDeploymentStatusEvent(handle *sqlx.DB, event *github.DeploymentStatusEvent) error {
+	record := deploymentRecord(event.GetRepo(), event.GetDeployment())
+
+	return withTransaction(handle, func(tx *sqlx.Tx) error {
+		existing, err := createOrUpdateDeployment(tx, record)
+
+		if err != nil {
+			return err
+		}
+
+		status, statusAt, ok := deploymentStatus(event)
+
+		if !ok || deploymentStatusOutdated(existing, statusAt) {
+			return nil
+		}
+
+		record.Status = status
+		record.StatusAt = statusAt
+
+		if _, err := tx.NamedExec(
+			updateDeploymentStatusQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to update status: %w", err)
+		}
+
+		if !deploymentSucceeded(existing, record) {
+			return nil
+		}
+
+		return incrementDeploymentTotals(tx, record)
+	})
+}
+
+// incrementDeploymentTotals adds the lead time and the time to restore of the
+// successful deployment to the persistent histograms.
+func incrementDeploymentTotals(handle *sqlx.Tx, record *Deployment) error {
+	commits := make([]int64, 0)
+	stmt, err := handle.PrepareNamed(selectDeploymentCommitsQuery)
+
+	if err != nil {
+		return fmt.Errorf("failed to prepare commits: %w", err)
+	}
+
+	if err := stmt.Select(&commits, record); err != nil {
+		return fmt.Errorf("failed to find commits: %w", err)
+	}
+
+	previous := make([]*Deployment, 0)
+	stmt, err = handle.PrepareNamed(selectDeploymentHistoryQuery)
+
+	if err != nil {
+		return fmt.Errorf("failed to prepare history: %w", err)
+	}
+
+	if err := stmt.Select(&previous, record); err != nil {
+		return fmt.Errorf("failed to find history: %w", err)
+	}
+
+	for _, total := range []*DurationTotal{
+		deploymentLeadTime(record, commits),
+		deploymentRestore(record, previous),
+	} {
+		if total == nil {
+			continue
+		}
+
+		if err := incrementDurationTotal(handle, total); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deploymentRecord maps the deployment payload to a record.
+func deploymentRecord(repo *github.Repository, deployment *github.Deployment) *Deployment {
+	return &Deployment{
+		Owner:       repo.GetOwner().GetLogin(),
+		Repo:        repo.GetName(),
+		Identifier:  deployment.GetID(),
+		Environment: deployment.GetEnvironment(),
+		Ref:         deployment.GetRef(),
+		SHA:         deployment.GetSHA(),
+		Task:        deployment.GetTask(),
+		Creator:     deployment.GetCreator().GetLogin(),
+		CreatedAt:   unixOrZero(deployment.GetCreatedAt()),
+		UpdatedAt:   unixOrZero(deployment.GetUpdatedAt()),
+	}
+}
+
+// deploymentOutdated checks if the record is older than the existing one.
+func deploymentOutdated(existing, record *Deployment) bool {
+	return existing != nil && existing.UpdatedAt > record.UpdatedAt
+}
+
+// deploymentStatus returns the state and the creation of the status payload,
+// ok is false if the status shouldn't be stored. Deployments get marked as
+// inactive as soon as a newer deployment to the same environment succeeded,
+// we don't want to lose the final state.
+func deploymentStatus(event *github.DeploymentStatusEvent) (string, int64, bool) {
+	state := event.GetDeploymentStatus().GetState()
+
+	if state == "inactive" {
+		return "", 0, false
+	}
+
+	return state, unixOrZero(event.GetDeploymentStatus().GetCreatedAt()), true
+}
+
+// deploymentStatusOutdated checks if the existing status is newer than the
+// status created at the timestamp.
+func deploymentStatusOutdated(existing *Deployment, statusAt int64) bool {
+	return existing.StatusAt > statusAt
+}
+
+// deploymentSucceeded checks if the record finishes the deployment successfully
+// for the first time.
+func deploymentSucceeded(existing, record *Deployment) bool {
+	return record.Status == "success" && (existing == nil || existing.Status != "success")
+}
+
+// deploymentLeadTime returns the persistent histogram bucket of the time from
+// the earliest commit timestamp of the workflow runs for the deployed commit to
+// the successful deployment, it returns nil if there is no such run.
+func deploymentLeadTime(record *Deployment, commits []int64) *DurationTotal {
+	var committed int64
+
+	for _, commitAt := range commits {
+		if commitAt > 0 && (committed == 0 || commitAt < committed) {
+			committed = commitAt
+		}
+	}
+
+	if committed == 0 || committed > record.StatusAt {
+		return nil
+	}
+
+	return durationTotal(
+		"deployment_lead_time",
+		record.StatusAt-committed,
+		record.Owner,
+		record.Repo,
+		record.Environment,
+	)
+}
+
+// deploymentRestore returns the persistent histogram bucket of the time from
+// the first failed deployment since the last successful one to the successful
+// deployment, it returns nil if the environment wasn't broken before.
+func deploymentRestore(record *Deployment, previous []*Deployment) *DurationTotal {
+	history := slices.Clone(previous)
+
+	slices.SortFunc(history, func(a, b *Deployment) int {
+		return cmp.Compare(b.StatusAt, a.StatusAt)
+	})
+
+	var brokenSince int64
+
+loop:
+	for _, deployment := range history {
+		if deployment.Identifier == record.Identifier || deployment.StatusAt >= record.StatusAt {
+			continue
+		}
+
+		switch deployment.Status {
+		case "success":
+			break loop
+		case "failure", "error":
+			brokenSince = deployment.StatusAt
+		}
+	}
+
+	if brokenSince == 0 {
+		return nil
+	}
+
+	return durationTotal(
+		"deployment_restore",
+		record.StatusAt-brokenSince,
+		record.Owner,
+		record.Repo,
+		record.Environment,
+	)
+}
+
+// createOrUpdateDeployment creates or updates the record and returns the
+// previously stored record.
+func createOrUpdateDeployment(handle *sqlx.Tx, record *Deployment) (*Deployment, error) {
+	existing := &Deployment{}
+	stmt, err := handle.PrepareNamed(forUpdate(handle, findDeploymentQuery))
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to prepare find: %w", err)
+	}
+
+	if err := stmt.Get(existing, record); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to find record: %w", err)
+	}
+
+	if existing.Identifier == 0 {
+		if _, err := handle.NamedExec(
+			createDeploymentQuery,
+			record,
+		); err != nil {
+			return nil, fmt.Errorf("failed to create record: %w", err)
+		}
+	} else {
+		if deploymentOutdated(existing, record) {
+			return existing, nil
+		}
+
+		if _, err := handle.NamedExec(
+			updateDeploymentQuery,
+			record,
+		); err != nil {
+			return nil, fmt.Errorf("failed to update record: %w", err)
+		}
+	}
+
+	return existing, nil
+}
+
+// getDeployments retrieves the deployments from the database.
+func getDeployments(handle *sqlx.DB, window time.Duration) ([]*Deployment, error) {
+	records := make([]*Deployment, 0)
+
+	rows, err := handle.NamedQuery(
+		selectDeploymentsQuery,
+		map[string]interface{}{
+			"window": time.Now().Add(-window).Unix(),
+		},
+	)
+
+	if err != nil {
+		return records, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &Deployment{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return records, err
+		}
+
+		records = append(
+			records,
+			record,
+		)
+	}
+
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+// pruneDeployments prunes older deployment records.
+func pruneDeployments(handle *sqlx.DB, timeframe time.Duration) error {
+	if _, err := handle.NamedExec(
+		purgeDeploymentsQuery,
+		map[string]interface{}{
+			"timeframe": time.Now().Add(-timeframe).Unix(),
+		},
+	); err != nil {
+		return fmt.Errorf("failed to prune deployments: %w", err)
+	}
+
+	return nil
+}
+
+var selectDeploymentsQuery = `
+SELECT
+	owner,
+	repo,
+	identifier,
+	environment,
+	ref,
+	sha,
+	task,
+	creator,
+	status,
+	created_at,
+	updated_at,
+	status_at
+FROM
+	deployments
+WHERE
+	created_at > :window
+ORDER BY
+	created_at ASC;`
+
+var findDeploymentQuery = `
+SELECT
+	identifier,
+	status,
+	updated_at,
+	status_at
+FROM
+	deployments
+WHERE
+	owner=:owner AND repo=:repo AND identifier=:identifier;`
+
+var selectDeploymentCommitsQuery = `
+SELECT
+	commit_at
+FROM
+	workflow_runs
+WHERE
+	owner=:owner AND repo=:repo AND sha=:sha;`
+
+var selectDeploymentHistoryQuery = `
+SELECT
+	identifier,
+	status,
+	status_at
+FROM
+	deployments
+WHERE
+	owner=:owner AND repo=:repo AND environment=:environment AND status_at < :status_at;`
+
+var createDeploymentQuery = `
+INSERT INTO deployments (
+	owner,
+	repo,
+	identifier,
+	environment,
+	ref,
+	sha,
+	task,
+	creator,
+	status,
+	created_at,
+	updated_at,
+	status_at
+) VALUES (
+	:owner,
+	:repo,
+	:identifier,
+	:environment,
+	:ref,
+	:sha,
+	:task,
+	:creator,
+	:status,
+	:created_at,
+	:updated_at,
+	:status_at
+);`
+
+var updateDeploymentQuery = `
+UPDATE
+	deployments
+SET
+	environment=:environment,
+	ref=:ref,
+	sha=:sha,
+	task=:task,
+	creator=:creator,
+	created_at=:created_at,
+	updated_at=:updated_at
+WHERE
+	owner=:owner AND repo=:repo AND identifier=:identifier;`
+
+var updateDeploymentStatusQuery = `
+UPDATE
+	deployments
+SET
+	status=:status,
+	status_at=:status_at
+WHERE
+	owner=:owner AND repo=:repo AND identifier=:identifier;`
+
+var purgeDeploymentsQuery = `
+DELETE FROM
+	deployments
+WHERE
+	created_at < :timeframe;`
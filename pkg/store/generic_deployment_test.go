@@ -0,0 +1,170 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestDeploymentLeadTime(t *testing.T) {
+	record := &Deployment{
+		Owner:       "owner",
+		Repo:        "repo",
+		Environment: "production",
+		StatusAt:    1000,
+	}
+
+	tests := []struct {
+		name    string
+		commits []int64
+		want    int64
+	}{
+		{
+			name:    "no workflow runs",
+			commits: nil,
+			want:    -1,
+		},
+		{
+			name:    "single workflow run",
+			commits: []int64{400},
+			want:    600,
+		},
+		{
+			name:    "earliest commit of reruns",
+			commits: []int64{700, 400, 500},
+			want:    600,
+		},
+		{
+			name:    "missing commit timestamps",
+			commits: []int64{0, 800},
+			want:    200,
+		},
+		{
+			name:    "commit after deployment",
+			commits: []int64{1200},
+			want:    -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := deploymentLeadTime(record, tt.commits)
+
+			if tt.want == -1 {
+				if got != nil {
+					t.Errorf("Expected no lead time, got %+v", got)
+				}
+
+				return
+			}
+
+			if got == nil {
+				t.Fatalf("Expected lead time to be counted")
+			}
+
+			if got.Duration != tt.want {
+				t.Errorf("Expected lead time %d, got %d", tt.want, got.Duration)
+			}
+
+			if got.Labels != `["owner","repo","production"]` {
+				t.Errorf("Expected labels of owner, repo and environment, got %s", got.Labels)
+			}
+		})
+	}
+}
+
+func TestDeploymentRestore(t *testing.T) {
+	record := &Deployment{
+		Identifier: 10,
+		Status:     "success",
+		StatusAt:   1000,
+	}
+
+	deployment := func(id int64, status string, statusAt int64) *Deployment {
+		return &Deployment{
+			Identifier: id,
+			Status:     status,
+			StatusAt:   statusAt,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		previous []*Deployment
+		want     int64
+	}{
+		{
+			name:     "first deployment",
+			previous: nil,
+			want:     -1,
+		},
+		{
+			name: "previous success",
+			previous: []*Deployment{
+				deployment(1, "failure", 100),
+				deployment(2, "success", 200),
+			},
+			want: -1,
+		},
+		{
+			name: "single failure",
+			previous: []*Deployment{
+				deployment(1, "success", 100),
+				deployment(2, "failure", 600),
+			},
+			want: 400,
+		},
+		{
+			name: "first of multiple failures",
+			previous: []*Deployment{
+				deployment(3, "error", 700),
+				deployment(1, "success", 100),
+				deployment(2, "failure", 300),
+			},
+			want: 700,
+		},
+		{
+			name: "pending deployments in between",
+			previous: []*Deployment{
+				deployment(1, "failure", 300),
+				deployment(2, "in_progress", 500),
+			},
+			want: 700,
+		},
+		{
+			name: "later deployments",
+			previous: []*Deployment{
+				deployment(1, "success", 100),
+				deployment(2, "failure", 1200),
+			},
+			want: -1,
+		},
+		{
+			name: "same deployment",
+			previous: []*Deployment{
+				deployment(10, "failure", 500),
+			},
+			want: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := deploymentRestore(record, tt.previous)
+
+			if tt.want == -1 {
+				if got != nil {
+					t.Errorf("Expected no time to restore, got %+v", got)
+				}
+
+				return
+			}
+
+			if got == nil {
+				t.Fatalf("Expected time to restore to be counted")
+			}
+
+			if got.Duration != tt.want {
+				t.Errorf("Expected time to restore %d, got %d", tt.want, got.Duration)
+			}
+		})
+	}
+}
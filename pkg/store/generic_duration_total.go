@@ -0,0 +1,283 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// DurationBuckets defines the upper bounds in seconds of the persisted
+	// duration histograms per kind, longer durations get stored with a bound of
+	// -1.
+	DurationBuckets = map[string][]int64{
+		"deployment_lead_time": {
+			600,
+			1800,
+			3600,
+			14400,
+			86400,
+			604800,
+			2592000,
+		},
+		"deployment_restore": {
+			600,
+			1800,
+			3600,
+			14400,
+			86400,
+			604800,
+			2592000,
+		},
+		"pull_request_first_review": {
+			3600,
+			14400,
+			28800,
+			86400,
+			172800,
+			259200,
+			604800,
+			1209600,
+			2592000,
+		},
+		"pull_request_merge": {
+			3600,
+			14400,
+			28800,
+			86400,
+			172800,
+			259200,
+			604800,
+			1209600,
+			2592000,
+		},
+		"workflow_job_queue": {
+			5,
+			15,
+			30,
+			60,
+			120,
+			300,
+			600,
+			1800,
+			3600,
+		},
+		"workflow_job_step": {
+			1,
+			5,
+			15,
+			30,
+			60,
+			120,
+			300,
+			600,
+			1800,
+			3600,
+		},
+	}
+)
+
+// durationTotal builds the persistent histogram bucket for an observation.
+func durationTotal(kind string, duration int64, labels ...string) *DurationTotal {
+	encoded, _ := json.Marshal(labels)
+
+	return &DurationTotal{
+		Kind:     kind,
+		Labels:   string(encoded),
+		Bucket:   durationBucket(DurationBuckets[kind], duration),
+		Count:    1,
+		Duration: duration,
+	}
+}
+
+// durationBucket returns the upper bound of the bucket for the duration.
+func durationBucket(bounds []int64, duration int64) int64 {
+	for _, bound := range bounds {
+		if duration <= bound {
+			return bound
+		}
+	}
+
+	return -1
+}
+
+// incrementDurationTotal adds an observation to the persistent histogram.
+func incrementDurationTotal(handle *sqlx.Tx, record *DurationTotal) error {
+	if query, ok := upsertDurationTotalQueries[handle.DriverName()]; ok {
+		if _, err := handle.NamedExec(
+			query,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to upsert record: %w", err)
+		}
+
+		return nil
+	}
+
+	// The fallback for chai works like the one of incrementWorkflowTotal.
+	existing := &DurationTotal{}
+	stmt, err := handle.PrepareNamed(findDurationTotalQuery)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to prepare find: %w", err)
+	}
+
+	if err := stmt.Get(existing, record); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to find record: %w", err)
+	}
+
+	if existing.Count == 0 {
+		if _, err := handle.NamedExec(
+			createDurationTotalQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to create record: %w", err)
+		}
+	} else {
+		if _, err := handle.NamedExec(
+			updateDurationTotalQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to update record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// getDurationTotals retrieves the persistent histogram buckets of a kind.
+func getDurationTotals(handle *sqlx.DB, kind string) ([]*DurationTotal, error) {
+	records := make([]*DurationTotal, 0)
+
+	rows, err := handle.NamedQuery(
+		selectDurationTotalsQuery,
+		map[string]interface{}{
+			"kind": kind,
+		},
+	)
+
+	if err != nil {
+		return records, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &DurationTotal{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return records, err
+		}
+
+		records = append(
+			records,
+			record,
+		)
+	}
+
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+var selectDurationTotalsQuery = `
+SELECT
+	kind,
+	labels,
+	le,
+	count,
+	duration
+FROM
+	duration_totals
+WHERE
+	kind=:kind;`
+
+var findDurationTotalQuery = `
+SELECT
+	count
+FROM
+	duration_totals
+WHERE
+	kind=:kind AND labels=:labels AND le=:le;`
+
+var createDurationTotalQuery = `
+INSERT INTO duration_totals (
+	kind,
+	labels,
+	le,
+	count,
+	duration
+) VALUES (
+	:kind,
+	:labels,
+	:le,
+	:count,
+	:duration
+);`
+
+var updateDurationTotalQuery = `
+UPDATE
+	duration_totals
+SET
+	count=count + 1,
+	duration=duration + :duration
+WHERE
+	kind=:kind AND labels=:labels AND le=:le;`
+
+var upsertDurationTotalQueries = map[string]string{
+	"sqlite": `
+INSERT INTO duration_totals (
+	kind,
+	labels,
+	le,
+	count,
+	duration
+) VALUES (
+	:kind,
+	:labels,
+	:le,
+	:count,
+	:duration
+) ON CONFLICT (kind, labels, le) DO UPDATE SET
+	count=duration_totals.count + 1,
+	duration=duration_totals.duration + excluded.duration;`,
+	"postgres": `
+INSERT INTO duration_totals (
+	kind,
+	labels,
+	le,
+	count,
+	duration
+) VALUES (
+	:kind,
+	:labels,
+	:le,
+	:count,
+	:duration
+) ON CONFLICT (kind, labels, le) DO UPDATE SET
+	count=duration_totals.count + 1,
+	duration=duration_totals.duration + excluded.duration;`,
+	"mysql": `
+INSERT INTO duration_totals (
+	kind,
+	labels,
+	le,
+	count,
+	duration
+) VALUES (
+	:kind,
+	:labels,
+	:le,
+	:count,
+	:duration
+) ON DUPLICATE KEY UPDATE
+	count=count + 1,
+	duration=duration + VALUES(duration);`,
+}
@@ -0,0 +1,346 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/jmoiron/sqlx"
+)
+
+// storePullRequestEvent handles pull_request events from GitHub.
+func storePullRequestEvent(handle *sqlx.DB, event *github.PullRequestEvent) error {
+	return withTransaction(handle, func(tx *sqlx.Tx) error {
+		_, err := createOrUpdatePullRequest(
+			tx,
+			pullRequestRecord(event.GetRepo(), event.GetPullRequest()),
+		)
+
+		return err
+	})
+}
+
+// storePullRequestReviewEvent handles pull_request_review events from GitHub.
+func storePullRequestReviewEvent(handle *sqlx.DB, event *github.PullRequestReviewEvent) error {
+	record := pullRequestRecord(event.GetRepo(), event.GetPullRequest())
+
+	return withTransaction(handle, func(tx *sqlx.Tx) error {
+		existing, err := createOrUpdatePullRequest(tx, record)
+
+		if err != nil {
+			return err
+		}
+
+		submittedAt := pullRequestReviewAt(event)
+
+		if !pullRequestFirstReview(existing, submittedAt) {
+			return nil
+		}
+
+		review := pullRequestReview(existing, record, submittedAt)
+		record.FirstReviewAt = submittedAt
+
+		if _, err := tx.NamedExec(
+			updatePullRequestReviewQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to update first review: %w", err)
+		}
+
+		if review != nil {
+			return incrementDurationTotal(tx, review)
+		}
+
+		return nil
+	})
+}
+
+// pullRequestRecord maps the pull request payload to a record.
+func pullRequestRecord(repo *github.Repository, pr *github.PullRequest) *PullRequest {
+	state := pr.GetState()
+
+	if pr.GetMerged() || pr.MergedAt != nil {
+		state = "merged"
+	}
+
+	return &PullRequest{
+		Owner:      repo.GetOwner().GetLogin(),
+		Repo:       repo.GetName(),
+		Number:     pr.GetNumber(),
+		Identifier: pr.GetID(),
+		Base:       pr.GetBase().GetRef(),
+		Head:       pr.GetHead().GetRef(),
+		Author:     pr.GetUser().GetLogin(),
+		State:      state,
+		Draft:      pr.GetDraft(),
+		CreatedAt:  unixOrZero(pr.GetCreatedAt()),
+		UpdatedAt:  unixOrZero(pr.GetUpdatedAt()),
+		ClosedAt:   unixOrZero(pr.GetClosedAt()),
+		MergedAt:   unixOrZero(pr.GetMergedAt()),
+	}
+}
+
+// pullRequestOutdated checks if the record is older than the existing one.
+func pullRequestOutdated(existing, record *PullRequest) bool {
+	return existing != nil && existing.UpdatedAt > record.UpdatedAt
+}
+
+// pullRequestReviewAt returns the submission of the review, or zero if it
+// doesn't count for the first review. Reviews by the author itself, e.g.
+// replies to review comments, should not count as a review.
+func pullRequestReviewAt(event *github.PullRequestReviewEvent) int64 {
+	if event.GetReview().GetUser().GetLogin() == event.GetPullRequest().GetUser().GetLogin() {
+		return 0
+	}
+
+	return unixOrZero(event.GetReview().GetSubmittedAt())
+}
+
+// pullRequestFirstReview checks if the review submitted at the timestamp is
+// earlier than the first review known so far.
+func pullRequestFirstReview(existing *PullRequest, submittedAt int64) bool {
+	if submittedAt == 0 {
+		return false
+	}
+
+	return existing == nil || existing.FirstReviewAt == 0 || existing.FirstReviewAt > submittedAt
+}
+
+// pullRequestReview returns the persistent histogram bucket of the time to the
+// first review if the pull request didn't get reviewed before, otherwise it
+// returns nil. An earlier review delivered later on still corrects the first
+// review of the record, but it doesn't change the histogram anymore.
+func pullRequestReview(existing, record *PullRequest, submittedAt int64) *DurationTotal {
+	if !pullRequestFirstReview(existing, submittedAt) || submittedAt < record.CreatedAt {
+		return nil
+	}
+
+	if existing != nil && existing.FirstReviewAt > 0 {
+		return nil
+	}
+
+	return durationTotal(
+		"pull_request_first_review",
+		submittedAt-record.CreatedAt,
+		record.Owner,
+		record.Repo,
+		record.Base,
+	)
+}
+
+// pullRequestMerge returns the persistent histogram bucket of the time to
+// merge if the record merges the pull request for the first time, otherwise it
+// returns nil.
+func pullRequestMerge(existing, record *PullRequest) *DurationTotal {
+	if record.State != "merged" || record.MergedAt == 0 || record.MergedAt < record.CreatedAt {
+		return nil
+	}
+
+	if existing != nil && existing.State == "merged" {
+		return nil
+	}
+
+	return durationTotal(
+		"pull_request_merge",
+		record.MergedAt-record.CreatedAt,
+		record.Owner,
+		record.Repo,
+		record.Base,
+	)
+}
+
+// createOrUpdatePullRequest creates or updates the record and returns the
+// previously stored record.
+func createOrUpdatePullRequest(handle *sqlx.Tx, record *PullRequest) (*PullRequest, error) {
+	existing := &PullRequest{}
+	stmt, err := handle.PrepareNamed(forUpdate(handle, findPullRequestQuery))
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to prepare find: %w", err)
+	}
+
+	if err := stmt.Get(existing, record); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to find record: %w", err)
+	}
+
+	if existing.Identifier == 0 {
+		if _, err := handle.NamedExec(
+			createPullRequestQuery,
+			record,
+		); err != nil {
+			return nil, fmt.Errorf("failed to create record: %w", err)
+		}
+	} else {
+		if pullRequestOutdated(existing, record) {
+			return existing, nil
+		}
+
+		if _, err := handle.NamedExec(
+			updatePullRequestQuery,
+			record,
+		); err != nil {
+			return nil, fmt.Errorf("failed to update record: %w", err)
+		}
+	}
+
+	if merge := pullRequestMerge(existing, record); merge != nil {
+		if err := incrementDurationTotal(handle, merge); err != nil {
+			return nil, err
+		}
+	}
+
+	return existing, nil
+}
+
+// getPullRequests retrieves the pull requests from the database.
+func getPullRequests(handle *sqlx.DB, window time.Duration) ([]*PullRequest, error) {
+	records := make([]*PullRequest, 0)
+
+	rows, err := handle.NamedQuery(
+		selectPullRequestsQuery,
+		map[string]interface{}{
+			"window": time.Now().Add(-window).Unix(),
+		},
+	)
+
+	if err != nil {
+		return records, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &PullRequest{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return records, err
+		}
+
+		records = append(
+			records,
+			record,
+		)
+	}
+
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+// prunePullRequests prunes older closed pull request records.
+func prunePullRequests(handle *sqlx.DB, timeframe time.Duration) error {
+	if _, err := handle.NamedExec(
+		purgePullRequestsQuery,
+		map[string]interface{}{
+			"timeframe": time.Now().Add(-timeframe).Unix(),
+		},
+	); err != nil {
+		return fmt.Errorf("failed to prune pull requests: %w", err)
+	}
+
+	return nil
+}
+
+var selectPullRequestsQuery = `
+SELECT
+	owner,
+	repo,
+	number,
+	identifier,
+	base,
+	head,
+	author,
+	state,
+	draft,
+	created_at,
+	updated_at,
+	closed_at,
+	merged_at,
+	first_review_at
+FROM
+	pull_requests
+WHERE
+	state = 'open' OR updated_at > :window
+ORDER BY
+	updated_at ASC;`
+
+var findPullRequestQuery = `
+SELECT
+	identifier,
+	state,
+	updated_at,
+	first_review_at
+FROM
+	pull_requests
+WHERE
+	owner=:owner AND repo=:repo AND number=:number;`
+
+var createPullRequestQuery = `
+INSERT INTO pull_requests (
+	owner,
+	repo,
+	number,
+	identifier,
+	base,
+	head,
+	author,
+	state,
+	draft,
+	created_at,
+	updated_at,
+	closed_at,
+	merged_at,
+	first_review_at
+) VALUES (
+	:owner,
+	:repo,
+	:number,
+	:identifier,
+	:base,
+	:head,
+	:author,
+	:state,
+	:draft,
+	:created_at,
+	:updated_at,
+	:closed_at,
+	:merged_at,
+	:first_review_at
+);`
+
+var updatePullRequestQuery = `
+UPDATE
+	pull_requests
+SET
+	identifier=:identifier,
+	base=:base,
+	head=:head,
+	author=:author,
+	state=:state,
+	draft=:draft,
+	created_at=:created_at,
+	updated_at=:updated_at,
+	closed_at=:closed_at,
+	merged_at=:merged_at
+WHERE
+	owner=:owner AND repo=:repo AND number=:number;`
+
+var updatePullRequestReviewQuery = `
+UPDATE
+	pull_requests
+SET
+	first_review_at=:first_review_at
+WHERE
+	owner=:owner AND repo=:repo AND number=:number;`
+
+var purgePullRequestsQuery = `
+DELETE FROM
+	pull_requests
+WHERE
+	state != 'open' AND updated_at < :timeframe;`
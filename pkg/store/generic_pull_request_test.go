@@ -0,0 +1,140 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestPullRequestReview(t *testing.T) {
+	record := &PullRequest{
+		Owner:     "owner",
+		Repo:      "repo",
+		Base:      "main",
+		CreatedAt: 100,
+	}
+
+	tests := []struct {
+		name        string
+		existing    *PullRequest
+		submittedAt int64
+		want        int64
+	}{
+		{
+			name:        "first review",
+			existing:    &PullRequest{},
+			submittedAt: 400,
+			want:        300,
+		},
+		{
+			name:        "missing existing record",
+			existing:    nil,
+			submittedAt: 400,
+			want:        300,
+		},
+		{
+			name:        "review by author",
+			existing:    &PullRequest{},
+			submittedAt: 0,
+			want:        -1,
+		},
+		{
+			name:        "already reviewed",
+			existing:    &PullRequest{FirstReviewAt: 500},
+			submittedAt: 400,
+			want:        -1,
+		},
+		{
+			name:        "review before creation",
+			existing:    &PullRequest{},
+			submittedAt: 50,
+			want:        -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pullRequestReview(tt.existing, record, tt.submittedAt)
+
+			if tt.want == -1 {
+				if got != nil {
+					t.Errorf("Expected no review to be counted, got %+v", got)
+				}
+
+				return
+			}
+
+			if got == nil {
+				t.Fatalf("Expected review to be counted")
+			}
+
+			if got.Duration != tt.want {
+				t.Errorf("Expected duration %d, got %d", tt.want, got.Duration)
+			}
+
+			if got.Labels != `["owner","repo","main"]` {
+				t.Errorf("Expected labels of owner, repo and base, got %s", got.Labels)
+			}
+		})
+	}
+}
+
+func TestPullRequestMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *PullRequest
+		record   *PullRequest
+		want     int64
+	}{
+		{
+			name:     "merged",
+			existing: &PullRequest{State: "open"},
+			record:   &PullRequest{State: "merged", CreatedAt: 100, MergedAt: 4000},
+			want:     3900,
+		},
+		{
+			name:     "merged without existing record",
+			existing: nil,
+			record:   &PullRequest{State: "merged", CreatedAt: 100, MergedAt: 200},
+			want:     100,
+		},
+		{
+			name:     "already merged",
+			existing: &PullRequest{State: "merged"},
+			record:   &PullRequest{State: "merged", CreatedAt: 100, MergedAt: 200},
+			want:     -1,
+		},
+		{
+			name:     "closed without merge",
+			existing: &PullRequest{State: "open"},
+			record:   &PullRequest{State: "closed", CreatedAt: 100},
+			want:     -1,
+		},
+		{
+			name:     "missing merge time",
+			existing: &PullRequest{State: "open"},
+			record:   &PullRequest{State: "merged", CreatedAt: 100},
+			want:     -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pullRequestMerge(tt.existing, tt.record)
+
+			if tt.want == -1 {
+				if got != nil {
+					t.Errorf("Expected no merge to be counted, got %+v", got)
+				}
+
+				return
+			}
+
+			if got == nil {
+				t.Fatalf("Expected merge to be counted")
+			}
+
+			if got.Duration != tt.want {
+				t.Errorf("Expected duration %d, got %d", tt.want, got.Duration)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// storeRepository creates or updates the default branch of a repository.
+func storeRepository(handle *sqlx.DB, record *Repository) error {
+	existing := &Repository{}
+	stmt, err := handle.PrepareNamed(findRepositoryQuery)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to prepare find: %w", err)
+	}
+
+	if err := stmt.Get(existing, record); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to find repository: %w", err)
+	}
+
+	if existing.Repo == "" {
+		if _, err := handle.NamedExec(
+			createRepositoryQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to create repository: %w", err)
+		}
+
+		return nil
+	}
+
+	if _, err := handle.NamedExec(
+		updateRepositoryQuery,
+		record,
+	); err != nil {
+		return fmt.Errorf("failed to update repository: %w", err)
+	}
+
+	return nil
+}
+
+// getRepositories retrieves all repositories from the database.
+func getRepositories(handle *sqlx.DB) ([]*Repository, error) {
+	records := make([]*Repository, 0)
+
+	rows, err := handle.Queryx(
+		selectRepositoriesQuery,
+	)
+
+	if err != nil {
+		return records, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &Repository{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return records, err
+		}
+
+		records = append(
+			records,
+			record,
+		)
+	}
+
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+var selectRepositoriesQuery = `
+SELECT
+	owner,
+	repo,
+	default_branch,
+	updated_at
+FROM
+	repositories;`
+
+var findRepositoryQuery = `
+SELECT
+	repo
+FROM
+	repositories
+WHERE
+	owner=:owner AND repo=:repo;`
+
+var createRepositoryQuery = `
+INSERT INTO repositories (
+	owner,
+	repo,
+	default_branch,
+	updated_at
+) VALUES (
+	:owner,
+	:repo,
+	:default_branch,
+	:updated_at
+);`
+
+var updateRepositoryQuery = `
+UPDATE
+	repositories
+SET
+	default_branch=:default_branch,
+	updated_at=:updated_at
+WHERE
+	owner=:owner AND repo=:repo;`
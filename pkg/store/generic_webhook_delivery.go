@@ -0,0 +1,107 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// hasWebhookDelivery checks if the delivery have already been reserved.
+func hasWebhookDelivery(handle *sqlx.DB, delivery string) (bool, error) {
+	existing := &WebhookDelivery{}
+	stmt, err := handle.PrepareNamed(findWebhookDeliveryQuery)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("failed to prepare find: %w", err)
+	}
+
+	if err := stmt.Get(existing, &WebhookDelivery{Delivery: delivery}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to find record: %w", err)
+	}
+
+	return existing.Delivery != "", nil
+}
+
+// reserveWebhookDelivery records the delivery on receipt, it returns false if
+// the delivery has already been reserved or processed. The primary key rejects
+// concurrent reservations of the same delivery.
+func reserveWebhookDelivery(handle *sqlx.DB, record *WebhookDelivery) (bool, error) {
+	if _, err := handle.NamedExec(
+		createWebhookDeliveryQuery,
+		record,
+	); err != nil {
+		exists, existsErr := hasWebhookDelivery(handle, record.Delivery)
+
+		if existsErr == nil && exists {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to create record: %w", err)
+	}
+
+	return true, nil
+}
+
+// releaseWebhookDelivery removes a reserved delivery to accept redeliveries.
+func releaseWebhookDelivery(handle *sqlx.DB, delivery string) error {
+	if _, err := handle.NamedExec(
+		deleteWebhookDeliveryQuery,
+		&WebhookDelivery{Delivery: delivery},
+	); err != nil {
+		return fmt.Errorf("failed to delete record: %w", err)
+	}
+
+	return nil
+}
+
+// pruneWebhookDeliveries prunes older webhook delivery records.
+func pruneWebhookDeliveries(handle *sqlx.DB, timeframe time.Duration) error {
+	if _, err := handle.NamedExec(
+		purgeWebhookDeliveriesQuery,
+		map[string]interface{}{
+			"timeframe": time.Now().Add(-timeframe).Unix(),
+		},
+	); err != nil {
+		return fmt.Errorf("failed to prune webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+var findWebhookDeliveryQuery = `
+SELECT
+	delivery
+FROM
+	webhook_deliveries
+WHERE
+	delivery=:delivery;`
+
+var createWebhookDeliveryQuery = `
+INSERT INTO webhook_deliveries (
+	delivery,
+	event,
+	created_at
+) VALUES (
+	:delivery,
+	:event,
+	:created_at
+);`
+
+var deleteWebhookDeliveryQuery = `
+DELETE FROM
+	webhook_deliveries
+WHERE
+	delivery=:delivery;`
+
+var purgeWebhookDeliveriesQuery = `
+DELETE FROM
+	webhook_deliveries
+WHERE
+	created_at < :timeframe;`
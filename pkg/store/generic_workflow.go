@@ -0,0 +1,129 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// storeWorkflow creates or updates a resolved workflow.
+func storeWorkflow(handle *sqlx.DB, record *Workflow) error {
+	existing := &Workflow{}
+	stmt, err := handle.PrepareNamed(findWorkflowQuery)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to prepare find: %w", err)
+	}
+
+	if err := stmt.Get(existing, record); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to find workflow: %w", err)
+	}
+
+	if existing.Identifier == 0 {
+		if _, err := handle.NamedExec(
+			createWorkflowQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to create workflow: %w", err)
+		}
+
+		return nil
+	}
+
+	if _, err := handle.NamedExec(
+		updateWorkflowQuery,
+		record,
+	); err != nil {
+		return fmt.Errorf("failed to update workflow: %w", err)
+	}
+
+	return nil
+}
+
+// getWorkflows retrieves all resolved workflows from the database.
+func getWorkflows(handle *sqlx.DB) ([]*Workflow, error) {
+	records := make([]*Workflow, 0)
+
+	rows, err := handle.Queryx(
+		selectWorkflowsQuery,
+	)
+
+	if err != nil {
+		return records, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &Workflow{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return records, err
+		}
+
+		records = append(
+			records,
+			record,
+		)
+	}
+
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+var selectWorkflowsQuery = `
+SELECT
+	owner,
+	repo,
+	identifier,
+	name,
+	path,
+	state,
+	updated_at
+FROM
+	workflows;`
+
+var findWorkflowQuery = `
+SELECT
+	identifier
+FROM
+	workflows
+WHERE
+	owner=:owner AND repo=:repo AND identifier=:identifier;`
+
+var createWorkflowQuery = `
+INSERT INTO workflows (
+	owner,
+	repo,
+	identifier,
+	name,
+	path,
+	state,
+	updated_at
+) VALUES (
+	:owner,
+	:repo,
+	:identifier,
+	:name,
+	:path,
+	:state,
+	:updated_at
+);`
+
+var updateWorkflowQuery = `
+UPDATE
+	workflows
+SET
+	name=:name,
+	path=:path,
+	state=:state,
+	updated_at=:updated_at
+WHERE
+	owner=:owner AND repo=:repo AND identifier=:identifier;`
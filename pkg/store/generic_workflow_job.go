@@ -13,9 +13,28 @@ import (
 
 // storeWorkflowJobEvent handles workflow_run events from GitHub.
 func storeWorkflowJobEvent(handle *sqlx.DB, event *github.WorkflowJobEvent) error {
+	record := workflowJobRecord(event)
+
+	return withTransaction(handle, func(tx *sqlx.Tx) error {
+		if err := createOrUpdateWorkflowJob(tx, record); err != nil {
+			return err
+		}
+
+		for _, step := range workflowJobStepRecords(record, event.GetWorkflowJob()) {
+			if err := createOrUpdateWorkflowJobStep(tx, step); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// workflowJobRecord maps the workflow job payload to a record.
+func workflowJobRecord(event *github.WorkflowJobEvent) *WorkflowJob {
 	job := event.WorkflowJob
 
-	record := &WorkflowJob{
+	return &WorkflowJob{
 		Owner:           event.GetRepo().GetOwner().GetLogin(),
 		Repo:            event.GetRepo().GetName(),
 		Name:            job.GetName(),
@@ -35,15 +54,117 @@ func storeWorkflowJobEvent(handle *sqlx.DB, event *github.WorkflowJobEvent) erro
 		RunnerGroupID:   job.GetRunnerGroupID(),
 		RunnerGroupName: job.GetRunnerGroupName(),
 		WorkflowName:    job.GetWorkflowName(),
+		HTMLURL:         job.GetHTMLURL(),
+	}
+}
+
+// workflowJobStepRecords maps the steps of the workflow job payload to records.
+func workflowJobStepRecords(record *WorkflowJob, job *github.WorkflowJob) []*WorkflowJobStep {
+	steps := make([]*WorkflowJobStep, 0, len(job.Steps))
+
+	for _, step := range job.Steps {
+		steps = append(steps, &WorkflowJobStep{
+			Owner:        record.Owner,
+			Repo:         record.Repo,
+			WorkflowName: record.WorkflowName,
+			JobName:      record.Name,
+			JobID:        record.Identifier,
+			Number:       step.GetNumber(),
+			Name:         step.GetName(),
+			Status:       step.GetStatus(),
+			Conclusion:   step.GetConclusion(),
+			CreatedAt:    record.CreatedAt,
+			StartedAt:    step.GetStartedAt().Time.Unix(),
+			CompletedAt:  step.GetCompletedAt().Time.Unix(),
+		})
+	}
+
+	return steps
+}
+
+// workflowJobOutdated checks if the record is older than the existing one. Jobs
+// don't provide an updated timestamp, so we compare the creation, and a
+// completed job never gets overwritten by a record of the same attempt.
+func workflowJobOutdated(existing, record *WorkflowJob) bool {
+	if existing == nil {
+		return false
+	}
+
+	if existing.CreatedAt > record.CreatedAt {
+		return true
+	}
+
+	return existing.CreatedAt == record.CreatedAt && existing.Status == "completed"
+}
+
+// workflowJobUpdate returns the record to store on top of the existing one.
+// The labels, run and workflow name of a job never change, so they are kept
+// like the update query of the SQL drivers does.
+func workflowJobUpdate(existing, record *WorkflowJob) *WorkflowJob {
+	if existing == nil {
+		return record
+	}
+
+	updated := *record
+	updated.Labels = existing.Labels
+	updated.RunID = existing.RunID
+	updated.WorkflowName = existing.WorkflowName
+
+	return &updated
+}
+
+// workflowJobTotal returns the persistent counter to increment if the record
+// completes the job for the first time, otherwise it returns nil.
+func workflowJobTotal(existing, record *WorkflowJob) *WorkflowTotal {
+	if record.Status != "completed" {
+		return nil
+	}
+
+	if existing != nil && existing.Status == "completed" {
+		return nil
+	}
+
+	return &WorkflowTotal{
+		Kind:       "workflow_job",
+		Owner:      record.Owner,
+		Repo:       record.Repo,
+		Workflow:   record.WorkflowName,
+		Conclusion: record.Conclusion,
+		Duration:   max(record.CompletedAt-record.StartedAt, 0),
+	}
+}
+
+// workflowJobQueue returns the persistent histogram bucket of the time the job
+// waited for a runner if the record starts the job for the first time,
+// otherwise it returns nil.
+func workflowJobQueue(existing, record *WorkflowJob) *DurationTotal {
+	if !workflowJobStarted(record) || record.StartedAt < record.CreatedAt {
+		return nil
 	}
 
-	return createOrUpdateWorkflowJob(handle, record)
+	if existing != nil && workflowJobStarted(existing) {
+		return nil
+	}
+
+	return durationTotal(
+		"workflow_job_queue",
+		record.StartedAt-record.CreatedAt,
+		record.Owner,
+		record.Repo,
+		record.Labels,
+		record.RunnerGroupName,
+	)
+}
+
+// workflowJobStarted checks if the job already left the queue.
+func workflowJobStarted(record *WorkflowJob) bool {
+	return record.StartedAt > 0 && (record.Status == "in_progress" || record.Status == "completed")
 }
 
 // createOrUpdateWorkflowJob creates or updates the record.
-func createOrUpdateWorkflowJob(handle *sqlx.DB, record *WorkflowJob) error {
+func createOrUpdateWorkflowJob(handle *sqlx.Tx, record *WorkflowJob) error {
 	existing := &WorkflowJob{}
-	stmt, err := handle.PrepareNamed(findWorkflowJobQuery)
+	stmt, err := handle.PrepareNamed(forUpdate(handle, findWorkflowJobQuery))
 
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return fmt.Errorf("failed to prepare find: %w", err)
@@ -61,12 +182,7 @@ func createOrUpdateWorkflowJob(handle *sqlx.DB, record *WorkflowJob) error {
 			return fmt.Errorf("failed to create record: %w", err)
 		}
 	} else {
-		if existing.CreatedAt > record.CreatedAt {
-			return nil
-		} else if existing.CreatedAt == record.CreatedAt && existing.Status == "completed" {
-			// The updatedAt timestamp is in seconds, so if the existing record has
-			// the same timestamp as the new record, and the status is "completed",
-			// we can safely ignore the update.
+		if workflowJobOutdated(existing, record) {
 			return nil
 		}
 
@@ -78,6 +194,16 @@ func createOrUpdateWorkflowJob(handle *sqlx.DB, record *WorkflowJob) error {
 		}
 	}
 
+	if queue := workflowJobQueue(existing, record); queue != nil {
+		if err := incrementDurationTotal(handle, queue); err != nil {
+			return err
+		}
+	}
+
+	if total := workflowJobTotal(existing, record); total != nil {
+		return incrementWorkflowTotal(handle, total)
+	}
+
 	return nil
 }
 
@@ -120,6 +246,68 @@ func getWorkflowJobs(handle *sqlx.DB, window time.Duration) ([]*WorkflowJob, err
 	return records, nil
 }
 
+// getPendingWorkflowJobs retrieves unfinished workflow jobs created before the threshold.
+func getPendingWorkflowJobs(handle *sqlx.DB, threshold time.Duration) ([]*WorkflowJob, error) {
+	records := make([]*WorkflowJob, 0)
+
+	rows, err := handle.NamedQuery(
+		selectPendingWorkflowJobsQuery,
+		map[string]interface{}{
+			"threshold": time.Now().Add(-threshold).Unix(),
+		},
+	)
+
+	if err != nil {
+		return records, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &WorkflowJob{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return records, err
+		}
+
+		records = append(
+			records,
+			record,
+		)
+	}
+
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+// deleteWorkflowJob deletes a workflow job together with its steps.
+func deleteWorkflowJob(handle *sqlx.DB, record *WorkflowJob) error {
+	if _, err := handle.NamedExec(
+		deleteWorkflowJobQuery,
+		record,
+	); err != nil {
+		return fmt.Errorf("failed to delete workflow job: %w", err)
+	}
+
+	if _, err := handle.NamedExec(
+		deleteWorkflowJobStepsQuery,
+		map[string]interface{}{
+			"owner":  record.Owner,
+			"repo":   record.Repo,
+			"job_id": record.Identifier,
+		},
+	); err != nil {
+		return fmt.Errorf("failed to delete workflow job steps: %w", err)
+	}
+
+	return nil
+}
+
 // pruneWorkflowJobs prunes older workflow job records.
 func pruneWorkflowJobs(handle *sqlx.DB, timeframe time.Duration) error {
 	if _, err := handle.NamedExec(
@@ -131,6 +319,15 @@ func pruneWorkflowJobs(handle *sqlx.DB, timeframe time.Duration) error {
 		return fmt.Errorf("failed to prune workflow jobs: %w", err)
 	}
 
+	if _, err := handle.NamedExec(
+		purgeWorkflowJobStepsQuery,
+		map[string]interface{}{
+			"timeframe": time.Now().Add(-timeframe).Unix(),
+		},
+	); err != nil {
+		return fmt.Errorf("failed to prune workflow job steps: %w", err)
+	}
+
 	return nil
 }
 
@@ -154,7 +351,8 @@ SELECT
 	runner_name,
 	runner_group_id,
 	runner_group_name,
-	workflow_name
+	workflow_name,
+	html_url
 FROM
 	workflow_jobs
 WHERE
@@ -162,9 +360,41 @@ WHERE
 ORDER BY
 	created_at ASC;`
 
+var selectPendingWorkflowJobsQuery = `
+SELECT
+	owner,
+	repo,
+	name,
+	status,
+	conclusion,
+	branch,
+	sha,
+	identifier,
+	run_id,
+	run_attempt,
+	created_at,
+	started_at,
+	completed_at,
+	labels,
+	runner_id,
+	runner_name,
+	runner_group_id,
+	runner_group_name,
+	workflow_name,
+	html_url
+FROM
+	workflow_jobs
+WHERE
+	status != 'completed' AND created_at < :threshold
+ORDER BY
+	created_at ASC;`
+
 var findWorkflowJobQuery = `
 SELECT
-	identifier
+	identifier,
+	status,
+	created_at,
+	started_at
 FROM
 	workflow_jobs
 WHERE
@@ -190,7 +420,8 @@ INSERT INTO workflow_jobs (
 	runner_name,
 	runner_group_id,
 	runner_group_name,
-	workflow_name
+	workflow_name,
+	html_url
 ) VALUES (
 	:owner,
 	:repo,
@@ -210,7 +441,8 @@ INSERT INTO workflow_jobs (
 	:runner_name,
 	:runner_group_id,
 	:runner_group_name,
-	:workflow_name
+	:workflow_name,
+	:html_url
 );`
 
 var updateWorkflowJobQuery = `
@@ -230,7 +462,14 @@ SET
 	runner_id=:runner_id,
 	runner_name=:runner_name,
 	runner_group_id=:runner_group_id,
-	runner_group_name=:runner_group_name
+	runner_group_name=:runner_group_name,
+	html_url=:html_url
+WHERE
+	owner=:owner AND repo=:repo AND identifier=:identifier;`
+
+var deleteWorkflowJobQuery = `
+DELETE FROM
+	workflow_jobs
 WHERE
 	owner=:owner AND repo=:repo AND identifier=:identifier;`
 
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// getWorkflowJobFlakes detects jobs which failed on one attempt and succeeded
+// on a later attempt of the same run within the window.
+func getWorkflowJobFlakes(handle *sqlx.DB, window time.Duration) ([]*WorkflowJobFlake, error) {
+	rows, err := handle.NamedQuery(
+		selectWorkflowJobAttemptsQuery,
+		map[string]interface{}{
+			"window": time.Now().Add(-window).Unix(),
+		},
+	)
+
+	if err != nil {
+		return make([]*WorkflowJobFlake, 0), err
+	}
+
+	defer rows.Close()
+	jobs := make([]*WorkflowJob, 0)
+
+	for rows.Next() {
+		record := &WorkflowJob{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return make([]*WorkflowJobFlake, 0), err
+		}
+
+		jobs = append(jobs, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return make([]*WorkflowJobFlake, 0), err
+	}
+
+	return detectWorkflowJobFlakes(jobs), nil
+}
+
+func detectWorkflowJobFlakes(jobs []*WorkflowJob) []*WorkflowJobFlake {
+	type attempts struct {
+		key     string
+		failed  int
+		success int
+	}
+
+	records := make([]*WorkflowJobFlake, 0)
+	keys := make([]string, 0)
+	flakes := make(map[string]*WorkflowJobFlake)
+	runKeys := make([]string, 0)
+	runs := make(map[string]*attempts)
+
+	for _, job := range jobs {
+		key := job.Owner + "/" + job.Repo + ":" + job.WorkflowName + ":" + job.Name
+
+		if _, ok := flakes[key]; !ok {
+			keys = append(keys, key)
+			flakes[key] = &WorkflowJobFlake{
+				Owner:        job.Owner,
+				Repo:         job.Repo,
+				WorkflowName: job.WorkflowName,
+				Name:         job.Name,
+			}
+		}
+
+		run := key + ":" + strconv.FormatInt(job.RunID, 10)
+
+		if _, ok := runs[run]; !ok {
+			runKeys = append(runKeys, run)
+			runs[run] = &attempts{
+				key: key,
+			}
+		}
+
+		switch job.Conclusion {
+		case "failure", "timed_out":
+			if runs[run].failed == 0 || job.RunAttempt < runs[run].failed {
+				runs[run].failed = job.RunAttempt
+			}
+		case "success":
+			if job.RunAttempt > runs[run].success {
+				runs[run].success = job.RunAttempt
+			}
+		}
+	}
+
+	for _, run := range runKeys {
+		record := flakes[runs[run].key]
+		record.Runs++
+
+		if runs[run].failed > 0 && runs[run].success > runs[run].failed {
+			record.Flaky++
+		}
+	}
+
+	for _, key := range keys {
+		records = append(records, flakes[key])
+	}
+
+	return records
+}
+
+var selectWorkflowJobAttemptsQuery = `
+SELECT
+	owner,
+	repo,
+	workflow_name,
+	name,
+	conclusion,
+	run_id,
+	run_attempt
+FROM
+	workflow_jobs
+WHERE
+	status = 'completed' AND completed_at > :window;`
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestDetectWorkflowJobFlakes(t *testing.T) {
+	job := func(runID int64, attempt int, conclusion string) *WorkflowJob {
+		return &WorkflowJob{
+			Owner:        "owner",
+			Repo:         "repo",
+			WorkflowName: "CI",
+			Name:         "build",
+			RunID:        runID,
+			RunAttempt:   attempt,
+			Conclusion:   conclusion,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		jobs  []*WorkflowJob
+		runs  int64
+		flaky int64
+	}{
+		{
+			name: "single success",
+			jobs: []*WorkflowJob{
+				job(1, 1, "success"),
+			},
+			runs:  1,
+			flaky: 0,
+		},
+		{
+			name: "failed then succeeded",
+			jobs: []*WorkflowJob{
+				job(1, 1, "failure"),
+				job(1, 2, "success"),
+			},
+			runs:  1,
+			flaky: 1,
+		},
+		{
+			name: "timed out then succeeded",
+			jobs: []*WorkflowJob{
+				job(1, 1, "timed_out"),
+				job(1, 3, "success"),
+			},
+			runs:  1,
+			flaky: 1,
+		},
+		{
+			name: "succeeded then failed",
+			jobs: []*WorkflowJob{
+				job(1, 1, "success"),
+				job(1, 2, "failure"),
+			},
+			runs:  1,
+			flaky: 0,
+		},
+		{
+			name: "failed on every attempt",
+			jobs: []*WorkflowJob{
+				job(1, 1, "failure"),
+				job(1, 2, "failure"),
+			},
+			runs:  1,
+			flaky: 0,
+		},
+		{
+			name: "cancelled then succeeded",
+			jobs: []*WorkflowJob{
+				job(1, 1, "cancelled"),
+				job(1, 2, "success"),
+			},
+			runs:  1,
+			flaky: 0,
+		},
+		{
+			name: "attempts out of order",
+			jobs: []*WorkflowJob{
+				job(1, 3, "success"),
+				job(1, 2, "failure"),
+				job(1, 1, "success"),
+			},
+			runs:  1,
+			flaky: 1,
+		},
+		{
+			name: "multiple runs",
+			jobs: []*WorkflowJob{
+				job(1, 1, "failure"),
+				job(1, 2, "success"),
+				job(2, 1, "success"),
+				job(3, 1, "failure"),
+			},
+			runs:  3,
+			flaky: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := detectWorkflowJobFlakes(tt.jobs)
+
+			if len(records) != 1 {
+				t.Fatalf("Expected 1 record, got %d", len(records))
+			}
+
+			if records[0].Runs != tt.runs {
+				t.Errorf("Expected %d runs, got %d", tt.runs, records[0].Runs)
+			}
+
+			if records[0].Flaky != tt.flaky {
+				t.Errorf("Expected %d flaky runs, got %d", tt.flaky, records[0].Flaky)
+			}
+		})
+	}
+}
+
+func TestDetectWorkflowJobFlakesPerJob(t *testing.T) {
+	records := detectWorkflowJobFlakes([]*WorkflowJob{
+		{Owner: "owner", Repo: "repo", WorkflowName: "CI", Name: "build", RunID: 1, RunAttempt: 1, Conclusion: "failure"},
+		{Owner: "owner", Repo: "repo", WorkflowName: "CI", Name: "test", RunID: 1, RunAttempt: 1, Conclusion: "success"},
+		{Owner: "owner", Repo: "repo", WorkflowName: "CI", Name: "build", RunID: 1, RunAttempt: 2, Conclusion: "success"},
+	})
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+
+	if records[0].Name != "build" || records[0].Flaky != 1 {
+		t.Errorf("Expected build to be flaky, got %+v", records[0])
+	}
+
+	if records[1].Name != "test" || records[1].Flaky != 0 {
+		t.Errorf("Expected test not to be flaky, got %+v", records[1])
+	}
+}
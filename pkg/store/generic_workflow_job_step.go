@@ -0,0 +1,204 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// workflowJobStepOutdated checks if the existing step must be kept. Webhooks
+// can be delivered out of order, so a finished step never gets overwritten.
+func workflowJobStepOutdated(existing *WorkflowJobStep) bool {
+	return existing != nil && existing.Status == "completed"
+}
+
+// workflowJobStepDuration returns the persistent histogram bucket of the step
+// duration if the record finishes the step for the first time, otherwise it
+// returns nil.
+func workflowJobStepDuration(existing, record *WorkflowJobStep) *DurationTotal {
+	if record.Status != "completed" || record.StartedAt == 0 || record.CompletedAt < record.StartedAt {
+		return nil
+	}
+
+	if workflowJobStepOutdated(existing) {
+		return nil
+	}
+
+	return durationTotal(
+		"workflow_job_step",
+		record.CompletedAt-record.StartedAt,
+		record.Owner,
+		record.Repo,
+		record.WorkflowName,
+		record.JobName,
+		record.Name,
+	)
+}
+
+// createOrUpdateWorkflowJobStep creates or updates the record.
+func createOrUpdateWorkflowJobStep(handle *sqlx.Tx, record *WorkflowJobStep) error {
+	existing := &WorkflowJobStep{}
+	stmt, err := handle.PrepareNamed(forUpdate(handle, findWorkflowJobStepQuery))
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to prepare find: %w", err)
+	}
+
+	if err := stmt.Get(existing, record); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to find step: %w", err)
+	}
+
+	if existing.JobID == 0 {
+		if _, err := handle.NamedExec(
+			createWorkflowJobStepQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to create step: %w", err)
+		}
+	} else {
+		if workflowJobStepOutdated(existing) {
+			return nil
+		}
+
+		if _, err := handle.NamedExec(
+			updateWorkflowJobStepQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to update step: %w", err)
+		}
+	}
+
+	if duration := workflowJobStepDuration(existing, record); duration != nil {
+		return incrementDurationTotal(handle, duration)
+	}
+
+	return nil
+}
+
+// getWorkflowJobSteps retrieves the workflow job steps from the database.
+func getWorkflowJobSteps(handle *sqlx.DB, window time.Duration) ([]*WorkflowJobStep, error) {
+	records := make([]*WorkflowJobStep, 0)
+
+	rows, err := handle.NamedQuery(
+		selectWorkflowJobStepsQuery,
+		map[string]interface{}{
+			"window": time.Now().Add(-window).Unix(),
+		},
+	)
+
+	if err != nil {
+		return records, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &WorkflowJobStep{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return records, err
+		}
+
+		records = append(
+			records,
+			record,
+		)
+	}
+
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+var selectWorkflowJobStepsQuery = `
+SELECT
+	owner,
+	repo,
+	workflow_name,
+	job_name,
+	job_id,
+	number,
+	name,
+	status,
+	conclusion,
+	created_at,
+	started_at,
+	completed_at
+FROM
+	workflow_job_steps
+WHERE
+	created_at > :window
+ORDER BY
+	created_at ASC;`
+
+var findWorkflowJobStepQuery = `
+SELECT
+	job_id,
+	status
+FROM
+	workflow_job_steps
+WHERE
+	owner=:owner AND repo=:repo AND job_id=:job_id AND number=:number;`
+
+var createWorkflowJobStepQuery = `
+INSERT INTO workflow_job_steps (
+	owner,
+	repo,
+	workflow_name,
+	job_name,
+	job_id,
+	number,
+	name,
+	status,
+	conclusion,
+	created_at,
+	started_at,
+	completed_at
+) VALUES (
+	:owner,
+	:repo,
+	:workflow_name,
+	:job_name,
+	:job_id,
+	:number,
+	:name,
+	:status,
+	:conclusion,
+	:created_at,
+	:started_at,
+	:completed_at
+);`
+
+var updateWorkflowJobStepQuery = `
+UPDATE
+	workflow_job_steps
+SET
+	workflow_name=:workflow_name,
+	job_name=:job_name,
+	name=:name,
+	status=:status,
+	conclusion=:conclusion,
+	created_at=:created_at,
+	started_at=:started_at,
+	completed_at=:completed_at
+WHERE
+	owner=:owner AND repo=:repo AND job_id=:job_id AND number=:number;`
+
+var deleteWorkflowJobStepsQuery = `
+DELETE FROM
+	workflow_job_steps
+WHERE
+	owner=:owner AND repo=:repo AND job_id=:job_id;`
+
+var purgeWorkflowJobStepsQuery = `
+DELETE FROM
+	workflow_job_steps
+WHERE
+	created_at < :timeframe;`
@@ -12,11 +12,28 @@ import (
 
 // storeWorkflowRunEvent handles workflow_run events from GitHub.
 func storeWorkflowRunEvent(handle *sqlx.DB, event *github.WorkflowRunEvent) error {
+	return withTransaction(handle, func(tx *sqlx.Tx) error {
+		return createOrUpdateWorkflowRun(
+			tx,
+			workflowRunRecord(event),
+		)
+	})
+}
+
+// workflowRunRecord maps the workflow run payload to a record.
+func workflowRunRecord(event *github.WorkflowRunEvent) *WorkflowRun {
 	createdAt := event.GetWorkflowRun().GetCreatedAt().Time.Unix()
 	updatedAt := event.GetWorkflowRun().GetUpdatedAt().Time.Unix()
 	startedAt := event.GetWorkflowRun().GetRunStartedAt().Time.Unix()
+	commitAt := unixOrZero(event.GetWorkflowRun().GetHeadCommit().GetTimestamp())
 
-	record := &WorkflowRun{
+	// Runs without a head commit, e.g. triggered manually, use the creation of
+	// the run as a fallback for the lead time of deployments.
+	if commitAt == 0 {
+		commitAt = createdAt
+	}
+
+	return &WorkflowRun{
 		Owner:      event.GetRepo().GetOwner().GetLogin(),
 		Repo:       event.GetRepo().GetName(),
 		WorkflowID: event.GetWorkflowRun().GetWorkflowID(),
@@ -25,7 +42,8 @@ func storeWorkflowRunEvent(handle *sqlx.DB, event *github.WorkflowRunEvent) erro
 		Event:      event.GetWorkflowRun().GetEvent(),
 		Name:       event.GetWorkflowRun().GetName(),
 		Title:      event.GetWorkflowRun().GetDisplayTitle(),
-		Status:     event.GetWorkflowRun().GetConclusion(),
+		Status:     event.GetWorkflowRun().GetStatus(),
+		Conclusion: event.GetWorkflowRun().GetConclusion(),
 		Branch:     event.GetWorkflowRun().GetHeadBranch(),
 		SHA:        event.GetWorkflowRun().GetHeadSHA(),
 		Identifier: event.GetWorkflowRun().GetID(),
@@ -33,19 +51,51 @@ func storeWorkflowRunEvent(handle *sqlx.DB, event *github.WorkflowRunEvent) erro
 		CreatedAt:  createdAt,
 		UpdatedAt:  updatedAt,
 		StartedAt:  startedAt,
+		CommitAt:   commitAt,
+		HTMLURL:    event.GetWorkflowRun().GetHTMLURL(),
+	}
+}
+
+// workflowRunOutdated checks if the record is older than the existing one. The
+// updated timestamp is in seconds, so a completed run doesn't get overwritten
+// by a record with the same timestamp either.
+func workflowRunOutdated(existing, record *WorkflowRun) bool {
+	if existing == nil {
+		return false
+	}
+
+	if existing.UpdatedAt > record.UpdatedAt {
+		return true
+	}
+
+	return existing.UpdatedAt == record.UpdatedAt && existing.Status == "completed"
+}
+
+// workflowRunTotal returns the persistent counter to increment if the record
+// completes the run for the first time, otherwise it returns nil.
+func workflowRunTotal(existing, record *WorkflowRun) *WorkflowTotal {
+	if record.Status != "completed" {
+		return nil
 	}
 
-	if record.Status == "" {
-		record.Status = event.GetWorkflowRun().GetStatus()
+	if existing != nil && existing.Status == "completed" {
+		return nil
 	}
 
-	return createOrUpdateWorkflowRun(handle, record)
+	return &WorkflowTotal{
+		Kind:       "workflow_run",
+		Owner:      record.Owner,
+		Repo:       record.Repo,
+		Workflow:   record.Name,
+		Conclusion: record.Conclusion,
+		Duration:   max(record.UpdatedAt-record.StartedAt, 0),
+	}
 }
 
 // createOrUpdateWorkflowRun creates or updates the record.
-func createOrUpdateWorkflowRun(handle *sqlx.DB, record *WorkflowRun) error {
+func createOrUpdateWorkflowRun(handle *sqlx.Tx, record *WorkflowRun) error {
 	existing := &WorkflowRun{}
-	stmt, err := handle.PrepareNamed(findWorkflowRunQuery)
+	stmt, err := handle.PrepareNamed(forUpdate(handle, findWorkflowRunQuery))
 
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return fmt.Errorf("failed to prepare find: %w", err)
@@ -63,12 +113,7 @@ func createOrUpdateWorkflowRun(handle *sqlx.DB, record *WorkflowRun) error {
 			return fmt.Errorf("failed to create record: %w", err)
 		}
 	} else {
-		if existing.UpdatedAt > record.UpdatedAt {
-			return nil
-		} else if existing.UpdatedAt == record.UpdatedAt && existing.Status == "completed" {
-			// The updatedAt timestamp is in seconds, so if the existing record has
-			// the same timestamp as the new record, and the status is "completed",
-			// we can safely ignore the update.
+		if workflowRunOutdated(existing, record) {
 			return nil
 		}
 
@@ -80,6 +125,10 @@ func createOrUpdateWorkflowRun(handle *sqlx.DB, record *WorkflowRun) error {
 		}
 	}
 
+	if total := workflowRunTotal(existing, record); total != nil {
+		return incrementWorkflowTotal(handle, total)
+	}
+
 	return nil
 }
 
@@ -122,6 +171,57 @@ func getWorkflowRuns(handle *sqlx.DB, window time.Duration) ([]*WorkflowRun, err
 	return records, nil
 }
 
+// getPendingWorkflowRuns retrieves unfinished workflow runs not updated since the threshold.
+func getPendingWorkflowRuns(handle *sqlx.DB, threshold time.Duration) ([]*WorkflowRun, error) {
+	records := make([]*WorkflowRun, 0)
+
+	rows, err := handle.NamedQuery(
+		selectPendingWorkflowRunsQuery,
+		map[string]interface{}{
+			"threshold": time.Now().Add(-threshold).Unix(),
+		},
+	)
+
+	if err != nil {
+		return records, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &WorkflowRun{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return records, err
+		}
+
+		records = append(
+			records,
+			record,
+		)
+	}
+
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+// deleteWorkflowRun deletes a single attempt of a workflow run.
+func deleteWorkflowRun(handle *sqlx.DB, record *WorkflowRun) error {
+	if _, err := handle.NamedExec(
+		deleteWorkflowRunQuery,
+		record,
+	); err != nil {
+		return fmt.Errorf("failed to delete workflow run: %w", err)
+	}
+
+	return nil
+}
+
 // pruneWorkflowRuns prunes older workflow run records.
 func pruneWorkflowRuns(handle *sqlx.DB, timeframe time.Duration) error {
 	if _, err := handle.NamedExec(
@@ -147,13 +247,16 @@ SELECT
 	name,
 	title,
 	status,
+	conclusion,
 	branch,
 	sha,
 	identifier,
 	actor,
 	created_at,
 	updated_at,
-	started_at
+	started_at,
+	commit_at,
+	html_url
 FROM
 	workflow_runs
 WHERE
@@ -161,14 +264,43 @@ WHERE
 ORDER BY
 	updated_at ASC;`
 
+var selectPendingWorkflowRunsQuery = `
+SELECT
+	owner,
+	repo,
+	workflow_id,
+	number,
+	attempt,
+	event,
+	name,
+	title,
+	status,
+	conclusion,
+	branch,
+	sha,
+	identifier,
+	actor,
+	created_at,
+	updated_at,
+	started_at,
+	commit_at,
+	html_url
+FROM
+	workflow_runs
+WHERE
+	status IN ('requested', 'queued', 'waiting', 'pending', 'in_progress') AND updated_at < :threshold
+ORDER BY
+	updated_at ASC;`
+
 var findWorkflowRunQuery = `
 SELECT
 	identifier,
+	status,
 	updated_at
 FROM
 	workflow_runs
 WHERE
-	owner=:owner AND repo=:repo AND workflow_id=:workflow_id AND number=:number;`
+	owner=:owner AND repo=:repo AND workflow_id=:workflow_id AND number=:number AND attempt=:attempt;`
 
 var createWorkflowRunQuery = `
 INSERT INTO workflow_runs (
@@ -181,13 +313,16 @@ INSERT INTO workflow_runs (
 	name,
 	title,
 	status,
+	conclusion,
 	branch,
 	sha,
 	identifier,
 	actor,
 	created_at,
 	updated_at,
-	started_at
+	started_at,
+	commit_at,
+	html_url
 ) VALUES (
 	:owner,
 	:repo,
@@ -198,33 +333,44 @@ INSERT INTO workflow_runs (
 	:name,
 	:title,
 	:status,
+	:conclusion,
 	:branch,
 	:sha,
 	:identifier,
 	:actor,
 	:created_at,
 	:updated_at,
-	:started_at
+	:started_at,
+	:commit_at,
+	:html_url
 );`
 
 var updateWorkflowRunQuery = `
 UPDATE
 	workflow_runs
 SET
-	attempt=:attempt,
 	event=:event,
 	name=:name,
 	title=:title,
 	status=:status,
+	conclusion=:conclusion,
 	branch=:branch,
 	sha=:sha,
 	identifier=:identifier,
 	actor=:actor,
 	created_at=:created_at,
 	updated_at=:updated_at,
-	started_at=:started_at
+	started_at=:started_at,
+	commit_at=:commit_at,
+	html_url=:html_url
+WHERE
+	owner=:owner AND repo=:repo AND workflow_id=:workflow_id AND number=:number AND attempt=:attempt;`
+
+var deleteWorkflowRunQuery = `
+DELETE FROM
+	workflow_runs
 WHERE
-	owner=:owner AND repo=:repo AND workflow_id=:workflow_id AND number=:number;`
+	owner=:owner AND repo=:repo AND workflow_id=:workflow_id AND number=:number AND attempt=:attempt;`
 
 var purgeWorkflowRunsQuery = `
 DELETE FROM
@@ -0,0 +1,279 @@
+package store
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// workflowDuration defines a single finished run or job for summaries.
+type workflowDuration struct {
+	Owner      string
+	Repo       string
+	Workflow   string
+	Name       string
+	Conclusion string
+	Duration   int64
+}
+
+// getWorkflowRunSummaries calculates the summaries of workflow runs in Go.
+func getWorkflowRunSummaries(handle *sqlx.DB, window time.Duration) ([]*WorkflowSummary, error) {
+	rows, err := handle.NamedQuery(
+		selectWorkflowRunDurationsQuery,
+		map[string]interface{}{
+			"window": time.Now().Add(-window).Unix(),
+		},
+	)
+
+	if err != nil {
+		return make([]*WorkflowSummary, 0), err
+	}
+
+	defer rows.Close()
+	durations := make([]workflowDuration, 0)
+
+	for rows.Next() {
+		record := &WorkflowRun{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return make([]*WorkflowSummary, 0), err
+		}
+
+		durations = append(durations, workflowDuration{
+			Owner:      record.Owner,
+			Repo:       record.Repo,
+			Workflow:   record.Name,
+			Conclusion: record.Conclusion,
+			Duration:   record.UpdatedAt - record.StartedAt,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return make([]*WorkflowSummary, 0), err
+	}
+
+	return summarizeWorkflowDurations(durations), nil
+}
+
+// getWorkflowJobSummaries calculates the summaries of workflow jobs in Go.
+func getWorkflowJobSummaries(handle *sqlx.DB, window time.Duration) ([]*WorkflowSummary, error) {
+	rows, err := handle.NamedQuery(
+		selectWorkflowJobDurationsQuery,
+		map[string]interface{}{
+			"window": time.Now().Add(-window).Unix(),
+		},
+	)
+
+	if err != nil {
+		return make([]*WorkflowSummary, 0), err
+	}
+
+	defer rows.Close()
+	durations := make([]workflowDuration, 0)
+
+	for rows.Next() {
+		record := &WorkflowJob{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return make([]*WorkflowSummary, 0), err
+		}
+
+		durations = append(durations, workflowDuration{
+			Owner:      record.Owner,
+			Repo:       record.Repo,
+			Workflow:   record.WorkflowName,
+			Name:       record.Name,
+			Conclusion: record.Conclusion,
+			Duration:   record.CompletedAt - record.StartedAt,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return make([]*WorkflowSummary, 0), err
+	}
+
+	return summarizeWorkflowDurations(durations), nil
+}
+
+// getNativeWorkflowRunSummaries calculates the summaries of workflow runs in SQL.
+func getNativeWorkflowRunSummaries(handle *sqlx.DB, window time.Duration) ([]*WorkflowSummary, error) {
+	return queryWorkflowSummaries(handle, selectNativeWorkflowRunSummariesQuery, window)
+}
+
+// getNativeWorkflowJobSummaries calculates the summaries of workflow jobs in SQL.
+func getNativeWorkflowJobSummaries(handle *sqlx.DB, window time.Duration) ([]*WorkflowSummary, error) {
+	return queryWorkflowSummaries(handle, selectNativeWorkflowJobSummariesQuery, window)
+}
+
+func queryWorkflowSummaries(handle *sqlx.DB, query string, window time.Duration) ([]*WorkflowSummary, error) {
+	records := make([]*WorkflowSummary, 0)
+
+	rows, err := handle.NamedQuery(
+		query,
+		map[string]interface{}{
+			"window": time.Now().Add(-window).Unix(),
+		},
+	)
+
+	if err != nil {
+		return records, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &WorkflowSummary{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return records, err
+		}
+
+		records = append(
+			records,
+			record,
+		)
+	}
+
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+func summarizeWorkflowDurations(rows []workflowDuration) []*WorkflowSummary {
+	records := make([]*WorkflowSummary, 0)
+	keys := make([]string, 0)
+	summaries := make(map[string]*WorkflowSummary)
+	durations := make(map[string][]float64)
+
+	for _, row := range rows {
+		key := row.Owner + "/" + row.Repo + ":" + row.Workflow + ":" + row.Name
+
+		if _, ok := summaries[key]; !ok {
+			keys = append(keys, key)
+			summaries[key] = &WorkflowSummary{
+				Owner:    row.Owner,
+				Repo:     row.Repo,
+				Workflow: row.Workflow,
+				Name:     row.Name,
+			}
+		}
+
+		summaries[key].Count++
+		summaries[key].Sum += float64(row.Duration)
+
+		if row.Conclusion == "success" {
+			summaries[key].Successful++
+		}
+
+		durations[key] = append(durations[key], float64(row.Duration))
+	}
+
+	for _, key := range keys {
+		record := summaries[key]
+		sorted := durations[key]
+		sort.Float64s(sorted)
+
+		record.P50 = percentile(sorted, 0.5)
+		record.P90 = percentile(sorted, 0.9)
+		record.P95 = percentile(sorted, 0.95)
+		record.P99 = percentile(sorted, 0.99)
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// percentile interpolates like percentile_cont, the values have to be sorted.
+func percentile(sorted []float64, quantile float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := quantile * float64(len(sorted)-1)
+	lower := math.Floor(rank)
+	upper := math.Ceil(rank)
+
+	if lower == upper {
+		return sorted[int(rank)]
+	}
+
+	return sorted[int(lower)] + (rank-lower)*(sorted[int(upper)]-sorted[int(lower)])
+}
+
+var selectWorkflowRunDurationsQuery = `
+SELECT
+	owner,
+	repo,
+	name,
+	conclusion,
+	started_at,
+	updated_at
+FROM
+	workflow_runs
+WHERE
+	status = 'completed' AND updated_at > :window;`
+
+var selectWorkflowJobDurationsQuery = `
+SELECT
+	owner,
+	repo,
+	workflow_name,
+	name,
+	conclusion,
+	started_at,
+	completed_at
+FROM
+	workflow_jobs
+WHERE
+	status = 'completed' AND started_at > 0 AND completed_at > :window;`
+
+var selectNativeWorkflowRunSummariesQuery = `
+SELECT
+	owner,
+	repo,
+	name AS workflow,
+	'' AS name,
+	COUNT(*) AS count,
+	SUM(CASE WHEN conclusion = 'success' THEN 1 ELSE 0 END) AS successful,
+	SUM(updated_at - started_at) AS sum,
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY updated_at - started_at) AS p50,
+	percentile_cont(0.9) WITHIN GROUP (ORDER BY updated_at - started_at) AS p90,
+	percentile_cont(0.95) WITHIN GROUP (ORDER BY updated_at - started_at) AS p95,
+	percentile_cont(0.99) WITHIN GROUP (ORDER BY updated_at - started_at) AS p99
+FROM
+	workflow_runs
+WHERE
+	status = 'completed' AND updated_at > :window
+GROUP BY
+	owner, repo, name;`
+
+var selectNativeWorkflowJobSummariesQuery = `
+SELECT
+	owner,
+	repo,
+	workflow_name AS workflow,
+	name,
+	COUNT(*) AS count,
+	SUM(CASE WHEN conclusion = 'success' THEN 1 ELSE 0 END) AS successful,
+	SUM(completed_at - started_at) AS sum,
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY completed_at - started_at) AS p50,
+	percentile_cont(0.9) WITHIN GROUP (ORDER BY completed_at - started_at) AS p90,
+	percentile_cont(0.95) WITHIN GROUP (ORDER BY completed_at - started_at) AS p95,
+	percentile_cont(0.99) WITHIN GROUP (ORDER BY completed_at - started_at) AS p99
+FROM
+	workflow_jobs
+WHERE
+	status = 'completed' AND started_at > 0 AND completed_at > :window
+GROUP BY
+	owner, repo, workflow_name, name;`
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name     string
+		sorted   []float64
+		quantile float64
+		want     float64
+	}{
+		{
+			name:     "no values",
+			sorted:   []float64{},
+			quantile: 0.5,
+			want:     0,
+		},
+		{
+			name:     "single value",
+			sorted:   []float64{42},
+			quantile: 0.99,
+			want:     42,
+		},
+		{
+			name:     "minimum",
+			sorted:   []float64{10, 20, 30},
+			quantile: 0,
+			want:     10,
+		},
+		{
+			name:     "maximum",
+			sorted:   []float64{10, 20, 30},
+			quantile: 1,
+			want:     30,
+		},
+		{
+			name:     "exact rank",
+			sorted:   []float64{10, 20, 30, 40, 50},
+			quantile: 0.5,
+			want:     30,
+		},
+		{
+			name:     "interpolated median",
+			sorted:   []float64{10, 20},
+			quantile: 0.5,
+			want:     15,
+		},
+		{
+			name:     "interpolated high quantile",
+			sorted:   []float64{10, 20},
+			quantile: 0.9,
+			want:     19,
+		},
+		{
+			name:     "equal values",
+			sorted:   []float64{5, 5, 5, 5},
+			quantile: 0.95,
+			want:     5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.quantile); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSummarizeWorkflowDurations(t *testing.T) {
+	t.Run("no durations", func(t *testing.T) {
+		records := summarizeWorkflowDurations(nil)
+
+		if records == nil || len(records) != 0 {
+			t.Errorf("Expected empty summaries, got %v", records)
+		}
+	})
+
+	t.Run("grouped durations", func(t *testing.T) {
+		records := summarizeWorkflowDurations([]workflowDuration{
+			{Owner: "owner", Repo: "repo", Workflow: "CI", Name: "test", Conclusion: "success", Duration: 30},
+			{Owner: "owner", Repo: "repo", Workflow: "CI", Name: "build", Conclusion: "failure", Duration: 60},
+			{Owner: "owner", Repo: "repo", Workflow: "CI", Name: "test", Conclusion: "failure", Duration: 10},
+			{Owner: "owner", Repo: "repo", Workflow: "CI", Name: "test", Conclusion: "success", Duration: 20},
+			{Owner: "owner", Repo: "other", Workflow: "CI", Name: "test", Conclusion: "success", Duration: 0},
+		})
+
+		if len(records) != 3 {
+			t.Fatalf("Expected 3 summaries, got %d", len(records))
+		}
+
+		want := []WorkflowSummary{
+			{Owner: "owner", Repo: "repo", Workflow: "CI", Name: "test", Count: 3, Successful: 2, Sum: 60, P50: 20, P90: 28, P95: 29, P99: 29.8},
+			{Owner: "owner", Repo: "repo", Workflow: "CI", Name: "build", Count: 1, Successful: 0, Sum: 60, P50: 60, P90: 60, P95: 60, P99: 60},
+			{Owner: "owner", Repo: "other", Workflow: "CI", Name: "test", Count: 1, Successful: 1, Sum: 0, P50: 0, P90: 0, P95: 0, P99: 0},
+		}
+
+		for i, record := range records {
+			if !equalSummary(*record, want[i]) {
+				t.Errorf("Expected summary %+v, got %+v", want[i], *record)
+			}
+		}
+	})
+}
+
+func equalSummary(got, want WorkflowSummary) bool {
+	within := func(a, b float64) bool {
+		return a-b < 1e-9 && b-a < 1e-9
+	}
+
+	return got.Owner == want.Owner &&
+		got.Repo == want.Repo &&
+		got.Workflow == want.Workflow &&
+		got.Name == want.Name &&
+		got.Count == want.Count &&
+		got.Successful == want.Successful &&
+		within(got.Sum, want.Sum) &&
+		within(got.P50, want.P50) &&
+		within(got.P90, want.P90) &&
+		within(got.P95, want.P95) &&
+		within(got.P99, want.P99)
+}
@@ -0,0 +1,238 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// TotalBuckets defines the upper bounds in seconds of the persisted
+	// duration histograms, longer durations get stored with a bound of -1.
+	TotalBuckets = []int64{
+		10,
+		30,
+		60,
+		120,
+		300,
+		600,
+		1200,
+		1800,
+		3600,
+		7200,
+		21600,
+	}
+)
+
+// incrementWorkflowTotal increments the persistent counter for a finished run or job.
+func incrementWorkflowTotal(handle *sqlx.Tx, record *WorkflowTotal) error {
+	record.Bucket = totalBucket(record.Duration)
+	record.Count = 1
+
+	// Concurrent workers could finish the first run for the same counter at
+	// the same time, so we are using an upsert if the dialect supports it.
+	if query, ok := upsertWorkflowTotalQueries[handle.DriverName()]; ok {
+		if _, err := handle.NamedExec(
+			query,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to upsert record: %w", err)
+		}
+
+		return nil
+	}
+
+	// Chai doesn't support updates on conflict, but it only allows a single
+	// writing transaction at a time, so the lookup can't race.
+	existing := &WorkflowTotal{}
+	stmt, err := handle.PrepareNamed(findWorkflowTotalQuery)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to prepare find: %w", err)
+	}
+
+	if err := stmt.Get(existing, record); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to find record: %w", err)
+	}
+
+	if existing.Count == 0 {
+		if _, err := handle.NamedExec(
+			createWorkflowTotalQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to create record: %w", err)
+		}
+	} else {
+		if _, err := handle.NamedExec(
+			updateWorkflowTotalQuery,
+			record,
+		); err != nil {
+			return fmt.Errorf("failed to update record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// totalBucket returns the upper bound of the bucket for the duration.
+func totalBucket(duration int64) int64 {
+	return durationBucket(TotalBuckets, duration)
+}
+
+// getWorkflowTotals retrieves all persistent counters from the database.
+func getWorkflowTotals(handle *sqlx.DB) ([]*WorkflowTotal, error) {
+	records := make([]*WorkflowTotal, 0)
+
+	rows, err := handle.Queryx(
+		selectWorkflowTotalsQuery,
+	)
+
+	if err != nil {
+		return records, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &WorkflowTotal{}
+
+		if err := rows.StructScan(
+			record,
+		); err != nil {
+			return records, err
+		}
+
+		records = append(
+			records,
+			record,
+		)
+	}
+
+	if err := rows.Err(); err != nil {
+		return records, err
+	}
+
+	return records, nil
+}
+
+var selectWorkflowTotalsQuery = `
+SELECT
+	kind,
+	owner,
+	repo,
+	workflow,
+	conclusion,
+	le,
+	count,
+	duration
+FROM
+	workflow_totals;`
+
+var findWorkflowTotalQuery = `
+SELECT
+	count
+FROM
+	workflow_totals
+WHERE
+	kind=:kind AND owner=:owner AND repo=:repo AND workflow=:workflow AND conclusion=:conclusion AND le=:le;`
+
+var createWorkflowTotalQuery = `
+INSERT INTO workflow_totals (
+	kind,
+	owner,
+	repo,
+	workflow,
+	conclusion,
+	le,
+	count,
+	duration
+) VALUES (
+	:kind,
+	:owner,
+	:repo,
+	:workflow,
+	:conclusion,
+	:le,
+	:count,
+	:duration
+);`
+
+var updateWorkflowTotalQuery = `
+UPDATE
+	workflow_totals
+SET
+	count=count + 1,
+	duration=duration + :duration
+WHERE
+	kind=:kind AND owner=:owner AND repo=:repo AND workflow=:workflow AND conclusion=:conclusion AND le=:le;`
+
+var upsertWorkflowTotalQueries = map[string]string{
+	"sqlite": `
+INSERT INTO workflow_totals (
+	kind,
+	owner,
+	repo,
+	workflow,
+	conclusion,
+	le,
+	count,
+	duration
+) VALUES (
+	:kind,
+	:owner,
+	:repo,
+	:workflow,
+	:conclusion,
+	:le,
+	:count,
+	:duration
+) ON CONFLICT (kind, owner, repo, workflow, conclusion, le) DO UPDATE SET
+	count=workflow_totals.count + 1,
+	duration=workflow_totals.duration + excluded.duration;`,
+	"postgres": `
+INSERT INTO workflow_totals (
+	kind,
+	owner,
+	repo,
+	workflow,
+	conclusion,
+	le,
+	count,
+	duration
+) VALUES (
+	:kind,
+	:owner,
+	:repo,
+	:workflow,
+	:conclusion,
+	:le,
+	:count,
+	:duration
+) ON CONFLICT (kind, owner, repo, workflow, conclusion, le) DO UPDATE SET
+	count=workflow_totals.count + 1,
+	duration=workflow_totals.duration + excluded.duration;`,
+	"mysql": `
+INSERT INTO workflow_totals (
+	kind,
+	owner,
+	repo,
+	workflow,
+	conclusion,
+	le,
+	count,
+	duration
+) VALUES (
+	:kind,
+	:owner,
+	:repo,
+	:workflow,
+	:conclusion,
+	:le,
+	:count,
+	:duration
+) ON DUPLICATE KEY UPDATE
+	count=count + 1,
+	duration=duration + VALUES(duration);`,
+}
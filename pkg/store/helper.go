@@ -0,0 +1,50 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v72/github"
+	"github.com/jmoiron/sqlx"
+)
+
+// unixOrZero returns the unix timestamp or zero for an undefined timestamp.
+func unixOrZero(val github.Timestamp) int64 {
+	if val.IsZero() {
+		return 0
+	}
+
+	return val.Time.Unix()
+}
+
+// withTransaction executes the function within a transaction, which gets
+// rolled back if the function returns an error.
+func withTransaction(handle *sqlx.DB, fn func(*sqlx.Tx) error) error {
+	tx, err := handle.Beginx()
+
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// forUpdate locks the selected rows until the end of the transaction for the
+// drivers supporting it, sqlite and chai serialize writing transactions anyway.
+func forUpdate(tx *sqlx.Tx, query string) string {
+	switch tx.DriverName() {
+	case "postgres", "mysql":
+		return strings.TrimSuffix(query, ";") + " FOR UPDATE;"
+	}
+
+	return query
+}
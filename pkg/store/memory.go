@@ -0,0 +1,805 @@
+package store
+
+import (
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+)
+
+type memoryKey struct {
+	owner   string
+	repo    string
+	id      int64
+	number  int64
+	attempt int
+}
+
+type memoryTotalKey struct {
+	kind       string
+	owner      string
+	repo       string
+	workflow   string
+	conclusion string
+	bucket     int64
+}
+
+type memoryDurationKey struct {
+	kind   string
+	labels string
+	bucket int64
+}
+
+type memoryStore struct {
+	logger *slog.Logger
+	mutex  sync.RWMutex
+
+	workflowRuns      map[memoryKey]*WorkflowRun
+	workflows         map[memoryKey]*Workflow
+	repositories      map[memoryKey]*Repository
+	workflowJobs      map[memoryKey]*WorkflowJob
+	workflowJobSteps  map[memoryKey]*WorkflowJobStep
+	workflowTotals    map[memoryTotalKey]*WorkflowTotal
+	durationTotals    map[memoryDurationKey]*DurationTotal
+	checkSuites       map[memoryKey]*CheckSuite
+	checkRuns         map[memoryKey]*CheckRun
+	pullRequests      map[memoryKey]*PullRequest
+	deployments       map[memoryKey]*Deployment
+	webhookDeliveries map[string]*WebhookDelivery
+}
+
+func init() {
+	register("memory", NewMemoryStore)
+}
+
+// Open doesn't have to do anything for the in-memory store.
+func (s *memoryStore) Open() (bool, error) {
+	return true, nil
+}
+
+// Close doesn't have to do anything for the in-memory store.
+func (s *memoryStore) Close() error {
+	return nil
+}
+
+// Ping always succeeds for the in-memory store.
+func (s *memoryStore) Ping() (bool, error) {
+	return true, nil
+}
+
+// Migrate doesn't have to do anything for the in-memory store.
+func (s *memoryStore) Migrate() error {
+	return nil
+}
+
+// StoreWorkflowRunEvent implements the Store interface.
+func (s *memoryStore) StoreWorkflowRunEvent(event *github.WorkflowRunEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record := workflowRunRecord(event)
+	key := memoryKey{owner: record.Owner, repo: record.Repo, id: record.WorkflowID, number: int64(record.Number), attempt: record.Attempt}
+	existing := s.workflowRuns[key]
+
+	if workflowRunOutdated(existing, record) {
+		return nil
+	}
+
+	s.workflowRuns[key] = record
+
+	if total := workflowRunTotal(existing, record); total != nil {
+		s.incrementWorkflowTotal(total)
+	}
+
+	return nil
+}
+
+// GetWorkflowRuns implements the Store interface.
+func (s *memoryStore) GetWorkflowRuns(window time.Duration) ([]*WorkflowRun, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	threshold := time.Now().Add(-window).Unix()
+
+	return memorySelect(s.workflowRuns, func(r *WorkflowRun) bool {
+		return r.UpdatedAt > threshold
+	}, func(r *WorkflowRun) int64 {
+		return r.UpdatedAt
+	}), nil
+}
+
+// GetPendingWorkflowRuns implements the Store interface.
+func (s *memoryStore) GetPendingWorkflowRuns(threshold time.Duration) ([]*WorkflowRun, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	before := time.Now().Add(-threshold).Unix()
+	pending := []string{"requested", "queued", "waiting", "pending", "in_progress"}
+
+	return memorySelect(s.workflowRuns, func(r *WorkflowRun) bool {
+		return slices.Contains(pending, r.Status) && r.UpdatedAt < before
+	}, func(r *WorkflowRun) int64 {
+		return r.UpdatedAt
+	}), nil
+}
+
+// DeleteWorkflowRun implements the Store interface.
+func (s *memoryStore) DeleteWorkflowRun(record *WorkflowRun) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.workflowRuns, memoryKey{owner: record.Owner, repo: record.Repo, id: record.WorkflowID, number: int64(record.Number), attempt: record.Attempt})
+	return nil
+}
+
+// PruneWorkflowRuns implements the Store interface.
+func (s *memoryStore) PruneWorkflowRuns(timeframe time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	before := time.Now().Add(-timeframe).Unix()
+
+	memoryPrune(s.workflowRuns, func(r *WorkflowRun) bool {
+		return r.UpdatedAt < before
+	})
+
+	return nil
+}
+
+// StoreWorkflow implements the Store interface.
+func (s *memoryStore) StoreWorkflow(record *Workflow) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	clone := *record
+	s.workflows[memoryKey{owner: record.Owner, repo: record.Repo, id: record.Identifier}] = &clone
+
+	return nil
+}
+
+// GetWorkflows implements the Store interface.
+func (s *memoryStore) GetWorkflows() ([]*Workflow, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return memorySelect(s.workflows, nil, func(r *Workflow) int64 {
+		return r.Identifier
+	}), nil
+}
+
+// StoreRepository implements the Store interface.
+func (s *memoryStore) StoreRepository(record *Repository) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	clone := *record
+	s.repositories[memoryKey{owner: record.Owner, repo: record.Repo}] = &clone
+
+	return nil
+}
+
+// GetRepositories implements the Store interface.
+func (s *memoryStore) GetRepositories() ([]*Repository, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return memorySelect(s.repositories, nil, func(r *Repository) int64 {
+		return r.UpdatedAt
+	}), nil
+}
+
+// StoreWorkflowJobEvent implements the Store interface.
+func (s *memoryStore) StoreWorkflowJobEvent(event *github.WorkflowJobEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record := workflowJobRecord(event)
+	s.createOrUpdateWorkflowJob(record)
+
+	for _, step := range workflowJobStepRecords(record, event.GetWorkflowJob()) {
+		key := memoryKey{owner: step.Owner, repo: step.Repo, id: step.JobID, number: step.Number}
+
+		existing := s.workflowJobSteps[key]
+
+		if workflowJobStepOutdated(existing) {
+			continue
+		}
+
+		s.workflowJobSteps[key] = step
+
+		if duration := workflowJobStepDuration(existing, step); duration != nil {
+			s.incrementDurationTotal(duration)
+		}
+	}
+
+	return nil
+}
+
+func (s *memoryStore) createOrUpdateWorkflowJob(record *WorkflowJob) {
+	key := memoryKey{owner: record.Owner, repo: record.Repo, id: record.Identifier}
+	existing := s.workflowJobs[key]
+
+	if workflowJobOutdated(existing, record) {
+		return
+	}
+
+	s.workflowJobs[key] = workflowJobUpdate(existing, record)
+
+	if queue := workflowJobQueue(existing, record); queue != nil {
+		s.incrementDurationTotal(queue)
+	}
+
+	if total := workflowJobTotal(existing, record); total != nil {
+		s.incrementWorkflowTotal(total)
+	}
+}
+
+// GetWorkflowJobs implements the Store interface.
+func (s *memoryStore) GetWorkflowJobs(window time.Duration) ([]*WorkflowJob, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	threshold := time.Now().Add(-window).Unix()
+
+	return memorySelect(s.workflowJobs, func(r *WorkflowJob) bool {
+		return r.CreatedAt > threshold
+	}, func(r *WorkflowJob) int64 {
+		return r.CreatedAt
+	}), nil
+}
+
+// GetPendingWorkflowJobs implements the Store interface.
+func (s *memoryStore) GetPendingWorkflowJobs(threshold time.Duration) ([]*WorkflowJob, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	before := time.Now().Add(-threshold).Unix()
+
+	return memorySelect(s.workflowJobs, func(r *WorkflowJob) bool {
+		return r.Status != "completed" && r.CreatedAt < before
+	}, func(r *WorkflowJob) int64 {
+		return r.CreatedAt
+	}), nil
+}
+
+// DeleteWorkflowJob implements the Store interface.
+func (s *memoryStore) DeleteWorkflowJob(record *WorkflowJob) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.workflowJobs, memoryKey{owner: record.Owner, repo: record.Repo, id: record.Identifier})
+
+	memoryPrune(s.workflowJobSteps, func(r *WorkflowJobStep) bool {
+		return r.Owner == record.Owner && r.Repo == record.Repo && r.JobID == record.Identifier
+	})
+
+	return nil
+}
+
+// PruneWorkflowJobs implements the Store interface.
+func (s *memoryStore) PruneWorkflowJobs(timeframe time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	before := time.Now().Add(-timeframe).Unix()
+
+	memoryPrune(s.workflowJobs, func(r *WorkflowJob) bool {
+		return r.CreatedAt < before
+	})
+
+	memoryPrune(s.workflowJobSteps, func(r *WorkflowJobStep) bool {
+		return r.CreatedAt < before
+	})
+
+	return nil
+}
+
+// GetWorkflowJobSteps implements the Store interface.
+func (s *memoryStore) GetWorkflowJobSteps(window time.Duration) ([]*WorkflowJobStep, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	threshold := time.Now().Add(-window).Unix()
+
+	return memorySelect(s.workflowJobSteps, func(r *WorkflowJobStep) bool {
+		return r.CreatedAt > threshold
+	}, func(r *WorkflowJobStep) int64 {
+		return r.CreatedAt
+	}), nil
+}
+
+// GetWorkflowJobFlakes implements the Store interface.
+func (s *memoryStore) GetWorkflowJobFlakes(window time.Duration) ([]*WorkflowJobFlake, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	threshold := time.Now().Add(-window).Unix()
+
+	return detectWorkflowJobFlakes(
+		memorySelect(s.workflowJobs, func(r *WorkflowJob) bool {
+			return r.Status == "completed" && r.CompletedAt > threshold
+		}, nil),
+	), nil
+}
+
+// GetWorkflowTotals implements the Store interface.
+func (s *memoryStore) GetWorkflowTotals() ([]*WorkflowTotal, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return memorySelect(s.workflowTotals, nil, nil), nil
+}
+
+func (s *memoryStore) incrementWorkflowTotal(record *WorkflowTotal) {
+	record.Bucket = totalBucket(record.Duration)
+
+	key := memoryTotalKey{
+		kind:       record.Kind,
+		owner:      record.Owner,
+		repo:       record.Repo,
+		workflow:   record.Workflow,
+		conclusion: record.Conclusion,
+		bucket:     record.Bucket,
+	}
+
+	if existing, ok := s.workflowTotals[key]; ok {
+		existing.Count++
+		existing.Duration += record.Duration
+
+		return
+	}
+
+	record.Count = 1
+	s.workflowTotals[key] = record
+}
+
+// GetDurationTotals implements the Store interface.
+func (s *memoryStore) GetDurationTotals(kind string) ([]*DurationTotal, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return memorySelect(s.durationTotals, func(r *DurationTotal) bool {
+		return r.Kind == kind
+	}, nil), nil
+}
+
+func (s *memoryStore) incrementDurationTotal(record *DurationTotal) {
+	key := memoryDurationKey{
+		kind:   record.Kind,
+		labels: record.Labels,
+		bucket: record.Bucket,
+	}
+
+	if existing, ok := s.durationTotals[key]; ok {
+		existing.Count++
+		existing.Duration += record.Duration
+
+		return
+	}
+
+	s.durationTotals[key] = record
+}
+
+// GetWorkflowRunSummaries implements the Store interface.
+func (s *memoryStore) GetWorkflowRunSummaries(window time.Duration) ([]*WorkflowSummary, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	threshold := time.Now().Add(-window).Unix()
+	durations := make([]workflowDuration, 0)
+
+	for _, record := range memorySelect(s.workflowRuns, func(r *WorkflowRun) bool {
+		return r.Status == "completed" && r.UpdatedAt > threshold
+	}, nil) {
+		durations = append(durations, workflowDuration{
+			Owner:      record.Owner,
+			Repo:       record.Repo,
+			Workflow:   record.Name,
+			Conclusion: record.Conclusion,
+			Duration:   record.UpdatedAt - record.StartedAt,
+		})
+	}
+
+	return summarizeWorkflowDurations(durations), nil
+}
+
+// GetWorkflowJobSummaries implements the Store interface.
+func (s *memoryStore) GetWorkflowJobSummaries(window time.Duration) ([]*WorkflowSummary, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	threshold := time.Now().Add(-window).Unix()
+	durations := make([]workflowDuration, 0)
+
+	for _, record := range memorySelect(s.workflowJobs, func(r *WorkflowJob) bool {
+		return r.Status == "completed" && r.StartedAt > 0 && r.CompletedAt > threshold
+	}, nil) {
+		durations = append(durations, workflowDuration{
+			Owner:      record.Owner,
+			Repo:       record.Repo,
+			Workflow:   record.WorkflowName,
+			Name:       record.Name,
+			Conclusion: record.Conclusion,
+			Duration:   record.CompletedAt - record.StartedAt,
+		})
+	}
+
+	return summarizeWorkflowDurations(durations), nil
+}
+
+// StoreCheckSuiteEvent implements the Store interface.
+func (s *memoryStore) StoreCheckSuiteEvent(event *github.CheckSuiteEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record := checkSuiteRecord(event)
+	key := memoryKey{owner: record.Owner, repo: record.Repo, id: record.Identifier}
+
+	if checkSuiteOutdated(s.checkSuites[key], record) {
+		return nil
+	}
+
+	s.checkSuites[key] = record
+
+	return nil
+}
+
+// GetCheckSuites implements the Store interface.
+func (s *memoryStore) GetCheckSuites(window time.Duration) ([]*CheckSuite, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	threshold := time.Now().Add(-window).Unix()
+
+	return memorySelect(s.checkSuites, func(r *CheckSuite) bool {
+		return r.UpdatedAt > threshold
+	}, func(r *CheckSuite) int64 {
+		return r.UpdatedAt
+	}), nil
+}
+
+// PruneCheckSuites implements the Store interface.
+func (s *memoryStore) PruneCheckSuites(timeframe time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	before := time.Now().Add(-timeframe).Unix()
+
+	memoryPrune(s.checkSuites, func(r *CheckSuite) bool {
+		return r.UpdatedAt < before
+	})
+
+	return nil
+}
+
+// StoreCheckRunEvent implements the Store interface.
+func (s *memoryStore) StoreCheckRunEvent(event *github.CheckRunEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record := checkRunRecord(event)
+	key := memoryKey{owner: record.Owner, repo: record.Repo, id: record.Identifier}
+
+	if checkRunOutdated(s.checkRuns[key], record) {
+		return nil
+	}
+
+	s.checkRuns[key] = record
+
+	return nil
+}
+
+// GetCheckRuns implements the Store interface.
+func (s *memoryStore) GetCheckRuns(window time.Duration) ([]*CheckRun, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	threshold := time.Now().Add(-window).Unix()
+
+	return memorySelect(s.checkRuns, func(r *CheckRun) bool {
+		return r.StartedAt > threshold
+	}, func(r *CheckRun) int64 {
+		return r.StartedAt
+	}), nil
+}
+
+// PruneCheckRuns implements the Store interface.
+func (s *memoryStore) PruneCheckRuns(timeframe time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	before := time.Now().Add(-timeframe).Unix()
+
+	memoryPrune(s.checkRuns, func(r *CheckRun) bool {
+		return r.StartedAt < before
+	})
+
+	return nil
+}
+
+// StorePullRequestEvent implements the Store interface.
+func (s *memoryStore) StorePullRequestEvent(event *github.PullRequestEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.createOrUpdatePullRequest(
+		pullRequestRecord(event.GetRepo(), event.GetPullRequest()),
+	)
+
+	return nil
+}
+
+// StorePullRequestReviewEvent implements the Store interface.
+func (s *memoryStore) StorePullRequestReviewEvent(event *github.PullRequestReviewEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record := pullRequestRecord(event.GetRepo(), event.GetPullRequest())
+	s.createOrUpdatePullRequest(record)
+
+	existing := s.pullRequests[memoryKey{owner: record.Owner, repo: record.Repo, number: int64(record.Number)}]
+	submittedAt := pullRequestReviewAt(event)
+
+	if pullRequestFirstReview(existing, submittedAt) {
+		if review := pullRequestReview(existing, record, submittedAt); review != nil {
+			s.incrementDurationTotal(review)
+		}
+
+		existing.FirstReviewAt = submittedAt
+	}
+
+	return nil
+}
+
+func (s *memoryStore) createOrUpdatePullRequest(record *PullRequest) {
+	key := memoryKey{owner: record.Owner, repo: record.Repo, number: int64(record.Number)}
+
+	existing := s.pullRequests[key]
+
+	if pullRequestOutdated(existing, record) {
+		return
+	}
+
+	if existing != nil {
+		record.FirstReviewAt = existing.FirstReviewAt
+	}
+
+	s.pullRequests[key] = record
+
+	if merge := pullRequestMerge(existing, record); merge != nil {
+		s.incrementDurationTotal(merge)
+	}
+}
+
+// GetPullRequests implements the Store interface.
+func (s *memoryStore) GetPullRequests(window time.Duration) ([]*PullRequest, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	threshold := time.Now().Add(-window).Unix()
+
+	return memorySelect(s.pullRequests, func(r *PullRequest) bool {
+		return r.State == "open" || r.UpdatedAt > threshold
+	}, func(r *PullRequest) int64 {
+		return r.UpdatedAt
+	}), nil
+}
+
+// PrunePullRequests implements the Store interface.
+func (s *memoryStore) PrunePullRequests(timeframe time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	before := time.Now().Add(-timeframe).Unix()
+
+	memoryPrune(s.pullRequests, func(r *PullRequest) bool {
+		return r.State != "open" && r.UpdatedAt < before
+	})
+
+	return nil
+}
+
+// StoreDeploymentEvent implements the Store interface.
+func (s *memoryStore) StoreDeploymentEvent(event *github.DeploymentEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.createOrUpdateDeployment(
+		deploymentRecord(event.GetRepo(), event.GetDeployment()),
+	)
+
+	return nil
+}
+
+// StoreDeploymentStatusEvent implements the Store interface.
+func (s *memoryStore) StoreDeploymentStatusEvent(event *github.DeploymentStatusEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record := deploymentRecord(event.GetRepo(), event.GetDeployment())
+	s.createOrUpdateDeployment(record)
+
+	existing := s.deployments[memoryKey{owner: record.Owner, repo: record.Repo, id: record.Identifier}]
+	status, statusAt, ok := deploymentStatus(event)
+
+	if !ok || deploymentStatusOutdated(existing, statusAt) {
+		return nil
+	}
+
+	updated := *existing
+	updated.Status = status
+	updated.StatusAt = statusAt
+
+	if deploymentSucceeded(existing, &updated) {
+		s.incrementDeploymentTotals(&updated)
+	}
+
+	*existing = updated
+	return nil
+}
+
+func (s *memoryStore) incrementDeploymentTotals(record *Deployment) {
+	commits := make([]int64, 0)
+
+	for _, run := range s.workflowRuns {
+		if run.Owner == record.Owner && run.Repo == record.Repo && run.SHA == record.SHA {
+			commits = append(commits, run.CommitAt)
+		}
+	}
+
+	previous := make([]*Deployment, 0)
+
+	for _, deployment := range s.deployments {
+		if deployment.Owner == record.Owner && deployment.Repo == record.Repo && deployment.Environment == record.Environment {
+			previous = append(previous, deployment)
+		}
+	}
+
+	for _, total := range []*DurationTotal{
+		deploymentLeadTime(record, commits),
+		deploymentRestore(record, previous),
+	} {
+		if total != nil {
+			s.incrementDurationTotal(total)
+		}
+	}
+}
+
+func (s *memoryStore) createOrUpdateDeployment(record *Deployment) {
+	key := memoryKey{owner: record.Owner, repo: record.Repo, id: record.Identifier}
+
+	existing := s.deployments[key]
+
+	if deploymentOutdated(existing, record) {
+		return
+	}
+
+	if existing != nil {
+		record.Status = existing.Status
+		record.StatusAt = existing.StatusAt
+	}
+
+	s.deployments[key] = record
+}
+
+// GetDeployments implements the Store interface.
+func (s *memoryStore) GetDeployments(window time.Duration) ([]*Deployment, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	threshold := time.Now().Add(-window).Unix()
+
+	return memorySelect(s.deployments, func(r *Deployment) bool {
+		return r.CreatedAt > threshold
+	}, func(r *Deployment) int64 {
+		return r.CreatedAt
+	}), nil
+}
+
+// PruneDeployments implements the Store interface.
+func (s *memoryStore) PruneDeployments(timeframe time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	before := time.Now().Add(-timeframe).Unix()
+
+	memoryPrune(s.deployments, func(r *Deployment) bool {
+		return r.CreatedAt < before
+	})
+
+	return nil
+}
+
+// ReserveWebhookDelivery implements the Store interface.
+func (s *memoryStore) ReserveWebhookDelivery(record *WebhookDelivery) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.webhookDeliveries[record.Delivery]; ok {
+		return false, nil
+	}
+
+	clone := *record
+	s.webhookDeliveries[record.Delivery] = &clone
+
+	return true, nil
+}
+
+// ReleaseWebhookDelivery implements the Store interface.
+func (s *memoryStore) ReleaseWebhookDelivery(delivery string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.webhookDeliveries, delivery)
+	return nil
+}
+
+// PruneWebhookDeliveries implements the Store interface.
+func (s *memoryStore) PruneWebhookDeliveries(timeframe time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	before := time.Now().Add(-timeframe).Unix()
+
+	memoryPrune(s.webhookDeliveries, func(r *WebhookDelivery) bool {
+		return r.CreatedAt < before
+	})
+
+	return nil
+}
+
+// memorySelect returns copies of the matching records ordered ascending by the
+// order function, that way callers can't modify the stored records.
+func memorySelect[K comparable, T any](records map[K]*T, match func(*T) bool, order func(*T) int64) []*T {
+	result := make([]*T, 0, len(records))
+
+	for _, record := range records {
+		if match != nil && !match(record) {
+			continue
+		}
+
+		clone := *record
+		result = append(result, &clone)
+	}
+
+	if order != nil {
+		sort.SliceStable(result, func(i, j int) bool {
+			return order(result[i]) < order(result[j])
+		})
+	}
+
+	return result
+}
+
+// memoryPrune deletes all records matching the function.
+func memoryPrune[K comparable, T any](records map[K]*T, match func(*T) bool) {
+	for key, record := range records {
+		if match(record) {
+			delete(records, key)
+		}
+	}
+}
+
+// NewMemoryStore initializes a new in-memory store.
+func NewMemoryStore(_ string, logger *slog.Logger) (Store, error) {
+	client := &memoryStore{
+		logger:            logger,
+		workflowRuns:      make(map[memoryKey]*WorkflowRun),
+		workflows:         make(map[memoryKey]*Workflow),
+		repositories:      make(map[memoryKey]*Repository),
+		workflowJobs:      make(map[memoryKey]*WorkflowJob),
+		workflowJobSteps:  make(map[memoryKey]*WorkflowJobStep),
+		workflowTotals:    make(map[memoryTotalKey]*WorkflowTotal),
+		durationTotals:    make(map[memoryDurationKey]*DurationTotal),
+		checkSuites:       make(map[memoryKey]*CheckSuite),
+		checkRuns:         make(map[memoryKey]*CheckRun),
+		pullRequests:      make(map[memoryKey]*PullRequest),
+		deployments:       make(map[memoryKey]*Deployment),
+		webhookDeliveries: make(map[string]*WebhookDelivery),
+	}
+
+	return client, nil
+}
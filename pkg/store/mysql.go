@@ -73,6 +73,203 @@ var (
 				PRIMARY KEY(owner, repo, identifier)
 			);`,
 		},
+		{
+			Version:     4,
+			Description: "Creating table check_suites",
+			Script: `CREATE TABLE check_suites (
+				owner VARCHAR(255) NOT NULL,
+				repo VARCHAR(255) NOT NULL,
+				identifier BIGINT NOT NULL,
+				app VARCHAR(255),
+				status VARCHAR(255),
+				conclusion VARCHAR(255),
+				branch VARCHAR(255),
+				sha VARCHAR(255),
+				created_at BIGINT,
+				updated_at BIGINT,
+				PRIMARY KEY(owner, repo, identifier)
+			) ENGINE=InnoDB CHARACTER SET=utf8;`,
+		},
+		{
+			Version:     5,
+			Description: "Creating table check_runs",
+			Script: `CREATE TABLE check_runs (
+				owner VARCHAR(255) NOT NULL,
+				repo VARCHAR(255) NOT NULL,
+				identifier BIGINT NOT NULL,
+				suite_id BIGINT,
+				name VARCHAR(255),
+				app VARCHAR(255),
+				status VARCHAR(255),
+				conclusion VARCHAR(255),
+				branch VARCHAR(255),
+				sha VARCHAR(255),
+				started_at BIGINT,
+				completed_at BIGINT,
+				PRIMARY KEY(owner, repo, identifier)
+			) ENGINE=InnoDB CHARACTER SET=utf8;`,
+		},
+		{
+			Version:     6,
+			Description: "Creating table pull_requests",
+			Script: `CREATE TABLE pull_requests (
+				owner VARCHAR(255) NOT NULL,
+				repo VARCHAR(255) NOT NULL,
+				number INTEGER NOT NULL,
+				identifier BIGINT,
+				base VARCHAR(255),
+				head VARCHAR(255),
+				author VARCHAR(255),
+				state VARCHAR(255),
+				draft BOOLEAN,
+				created_at BIGINT,
+				updated_at BIGINT,
+				closed_at BIGINT,
+				merged_at BIGINT,
+				first_review_at BIGINT,
+				PRIMARY KEY(owner, repo, number)
+			) ENGINE=InnoDB CHARACTER SET=utf8;`,
+		},
+		{
+			Version:     7,
+			Description: "Creating table deployments",
+			Script: `CREATE TABLE deployments (
+				owner VARCHAR(255) NOT NULL,
+				repo VARCHAR(255) NOT NULL,
+				identifier BIGINT NOT NULL,
+				environment VARCHAR(255),
+				ref VARCHAR(255),
+				sha VARCHAR(255),
+				task VARCHAR(255),
+				creator VARCHAR(255),
+				status VARCHAR(255),
+				created_at BIGINT,
+				updated_at BIGINT,
+				status_at BIGINT,
+				PRIMARY KEY(owner, repo, identifier)
+			) ENGINE=InnoDB CHARACTER SET=utf8;`,
+		},
+		{
+			Version:     8,
+			Description: "Creating table webhook_deliveries",
+			Script: `CREATE TABLE webhook_deliveries (
+				delivery VARCHAR(255) NOT NULL,
+				event VARCHAR(255),
+				created_at BIGINT,
+				PRIMARY KEY(delivery)
+			) ENGINE=InnoDB CHARACTER SET=utf8;`,
+		},
+		{
+			Version:     9,
+			Description: "Defaulting attempt column of workflow_runs table",
+			Script:      `UPDATE workflow_runs SET attempt = 1 WHERE attempt IS NULL;`,
+		},
+		{
+			Version:     10,
+			Description: "Altering table workflow_runs to require attempt column",
+			Script:      `ALTER TABLE workflow_runs MODIFY attempt INTEGER NOT NULL;`,
+		},
+		{
+			Version:     11,
+			Description: "Altering table workflow_runs to add attempt to primary key",
+			Script:      `ALTER TABLE workflow_runs DROP PRIMARY KEY, ADD PRIMARY KEY(owner, repo, workflow_id, number, attempt);`,
+		},
+		{
+			Version:     12,
+			Description: "Altering table workflow_runs to add conclusion column",
+			Script:      `ALTER TABLE workflow_runs ADD COLUMN conclusion VARCHAR(255);`,
+		},
+		{
+			Version:     13,
+			Description: "Splitting conclusion from status column of workflow_runs table",
+			Script:      `UPDATE workflow_runs SET conclusion = status, status = 'completed' WHERE status NOT IN ('requested', 'queued', 'waiting', 'pending', 'in_progress', 'completed');`,
+		},
+		{
+			Version:     14,
+			Description: "Creating table workflow_totals",
+			Script: `CREATE TABLE workflow_totals (
+				kind VARCHAR(32) NOT NULL,
+				owner VARCHAR(255) NOT NULL,
+				repo VARCHAR(255) NOT NULL,
+				workflow VARCHAR(255) NOT NULL,
+				conclusion VARCHAR(32) NOT NULL,
+				le BIGINT NOT NULL,
+				count BIGINT,
+				duration BIGINT,
+				PRIMARY KEY(kind, owner, repo, workflow, conclusion, le)
+			) ENGINE=InnoDB CHARACTER SET=utf8;`,
+		},
+		{
+			Version:     15,
+			Description: "Creating table workflow_job_steps",
+			Script: `CREATE TABLE workflow_job_steps (
+				owner VARCHAR(255) NOT NULL,
+				repo VARCHAR(255) NOT NULL,
+				workflow_name VARCHAR(255),
+				job_name VARCHAR(255),
+				job_id BIGINT NOT NULL,
+				number BIGINT NOT NULL,
+				name VARCHAR(255),
+				status VARCHAR(255),
+				conclusion VARCHAR(255),
+				created_at BIGINT,
+				started_at BIGINT,
+				completed_at BIGINT,
+				PRIMARY KEY(owner, repo, job_id, number)
+			) ENGINE=InnoDB CHARACTER SET=utf8;`,
+		},
+		{
+			Version:     16,
+			Description: "Altering table workflow_runs to add html_url column",
+			Script:      `ALTER TABLE workflow_runs ADD COLUMN html_url VARCHAR(255) DEFAULT '';`,
+		},
+		{
+			Version:     17,
+			Description: "Altering table workflow_jobs to add html_url column",
+			Script:      `ALTER TABLE workflow_jobs ADD COLUMN html_url VARCHAR(255) DEFAULT '';`,
+		},
+		{
+			Version:     18,
+			Description: "Creating table workflows",
+			Script: `CREATE TABLE workflows (
+				owner VARCHAR(255) NOT NULL,
+				repo VARCHAR(255) NOT NULL,
+				identifier BIGINT NOT NULL,
+				name VARCHAR(255),
+				path VARCHAR(255),
+				state VARCHAR(255),
+				updated_at BIGINT,
+				PRIMARY KEY(owner, repo, identifier)
+			) ENGINE=InnoDB CHARACTER SET=utf8;`,
+		},
+		{
+			Version:     19,
+			Description: "Creating table repositories",
+			Script: `CREATE TABLE repositories (
+				owner VARCHAR(255) NOT NULL,
+				repo VARCHAR(255) NOT NULL,
+				default_branch VARCHAR(255),
+				updated_at BIGINT,
+				PRIMARY KEY(owner, repo)
+			) ENGINE=InnoDB CHARACTER SET=utf8;`,
+		},
+		{
+			Version:     20,
+			Description: "Creating table duration_totals",
+			Script: `CREATE TABLE duration_totals (
+				kind VARCHAR(32) NOT NULL,
+				labels VARCHAR(900) NOT NULL,
+				le BIGINT NOT NULL,
+				count BIGINT,
+				duration BIGINT,
+				PRIMARY KEY(kind, labels, le)
+			) ENGINE=InnoDB CHARACTER SET=utf8;`,
+		},
+		{
+			Version:     21,
+			Description: "Altering table workflow_runs to add commit_at column",
+			Script:      `ALTER TABLE workflow_runs ADD COLUMN commit_at BIGINT DEFAULT 0;`,
+		},
 	}
 )
 
@@ -157,11 +354,41 @@ func (s *mysqlStore) GetWorkflowRuns(window time.Duration) ([]*WorkflowRun, erro
 	return getWorkflowRuns(s.handle, window)
 }
 
+// GetPendingWorkflowRuns implements the Store interface.
+func (s *mysqlStore) GetPendingWorkflowRuns(threshold time.Duration) ([]*WorkflowRun, error) {
+	return getPendingWorkflowRuns(s.handle, threshold)
+}
+
+// DeleteWorkflowRun implements the Store interface.
+func (s *mysqlStore) DeleteWorkflowRun(record *WorkflowRun) error {
+	return deleteWorkflowRun(s.handle, record)
+}
+
 // PruneWorkflowRuns implements the Store interface.
 func (s *mysqlStore) PruneWorkflowRuns(timeframe time.Duration) error {
 	return pruneWorkflowRuns(s.handle, timeframe)
 }
 
+// StoreWorkflow implements the Store interface.
+func (s *mysqlStore) StoreWorkflow(record *Workflow) error {
+	return storeWorkflow(s.handle, record)
+}
+
+// GetWorkflows implements the Store interface.
+func (s *mysqlStore) GetWorkflows() ([]*Workflow, error) {
+	return getWorkflows(s.handle)
+}
+
+// StoreRepository implements the Store interface.
+func (s *mysqlStore) StoreRepository(record *Repository) error {
+	return storeRepository(s.handle, record)
+}
+
+// GetRepositories implements the Store interface.
+func (s *mysqlStore) GetRepositories() ([]*Repository, error) {
+	return getRepositories(s.handle)
+}
+
 // StoreWorkflowJobEvent implements the Store interface.
 func (s *mysqlStore) StoreWorkflowJobEvent(event *github.WorkflowJobEvent) error {
 	return storeWorkflowJobEvent(s.handle, event)
@@ -172,11 +399,136 @@ func (s *mysqlStore) GetWorkflowJobs(window time.Duration) ([]*WorkflowJob, erro
 	return getWorkflowJobs(s.handle, window)
 }
 
+// GetPendingWorkflowJobs implements the Store interface.
+func (s *mysqlStore) GetPendingWorkflowJobs(threshold time.Duration) ([]*WorkflowJob, error) {
+	return getPendingWorkflowJobs(s.handle, threshold)
+}
+
+// DeleteWorkflowJob implements the Store interface.
+func (s *mysqlStore) DeleteWorkflowJob(record *WorkflowJob) error {
+	return deleteWorkflowJob(s.handle, record)
+}
+
 // PruneWorkflowJobs implements the Store interface.
 func (s *mysqlStore) PruneWorkflowJobs(timeframe time.Duration) error {
 	return pruneWorkflowJobs(s.handle, timeframe)
 }
 
+// GetWorkflowJobSteps implements the Store interface.
+func (s *mysqlStore) GetWorkflowJobSteps(window time.Duration) ([]*WorkflowJobStep, error) {
+	return getWorkflowJobSteps(s.handle, window)
+}
+
+// GetWorkflowJobFlakes implements the Store interface.
+func (s *mysqlStore) GetWorkflowJobFlakes(window time.Duration) ([]*WorkflowJobFlake, error) {
+	return getWorkflowJobFlakes(s.handle, window)
+}
+
+// GetWorkflowTotals implements the Store interface.
+func (s *mysqlStore) GetWorkflowTotals() ([]*WorkflowTotal, error) {
+	return getWorkflowTotals(s.handle)
+}
+
+// GetDurationTotals implements the Store interface.
+func (s *mysqlStore) GetDurationTotals(kind string) ([]*DurationTotal, error) {
+	return getDurationTotals(s.handle, kind)
+}
+
+// GetWorkflowRunSummaries implements the Store interface.
+func (s *mysqlStore) GetWorkflowRunSummaries(window time.Duration) ([]*WorkflowSummary, error) {
+	return getWorkflowRunSummaries(s.handle, window)
+}
+
+// GetWorkflowJobSummaries implements the Store interface.
+func (s *mysqlStore) GetWorkflowJobSummaries(window time.Duration) ([]*WorkflowSummary, error) {
+	return getWorkflowJobSummaries(s.handle, window)
+}
+
+// StoreCheckSuiteEvent implements the Store interface.
+func (s *mysqlStore) StoreCheckSuiteEvent(event *github.CheckSuiteEvent) error {
+	return storeCheckSuiteEvent(s.handle, event)
+}
+
+// GetCheckSuites implements the Store interface.
+func (s *mysqlStore) GetCheckSuites(window time.Duration) ([]*CheckSuite, error) {
+	return getCheckSuites(s.handle, window)
+}
+
+// PruneCheckSuites implements the Store interface.
+func (s *mysqlStore) PruneCheckSuites(timeframe time.Duration) error {
+	return pruneCheckSuites(s.handle, timeframe)
+}
+
+// StoreCheckRunEvent implements the Store interface.
+func (s *mysqlStore) StoreCheckRunEvent(event *github.CheckRunEvent) error {
+	return storeCheckRunEvent(s.handle, event)
+}
+
+// GetCheckRuns implements the Store interface.
+func (s *mysqlStore) GetCheckRuns(window time.Duration) ([]*CheckRun, error) {
+	return getCheckRuns(s.handle, window)
+}
+
+// PruneCheckRuns implements the Store interface.
+func (s *mysqlStore) PruneCheckRuns(timeframe time.Duration) error {
+	return pruneCheckRuns(s.handle, timeframe)
+}
+
+// StorePullRequestEvent implements the Store interface.
+func (s *mysqlStore) StorePullRequestEvent(event *github.PullRequestEvent) error {
+	return storePullRequestEvent(s.handle, event)
+}
+
+// StorePullRequestReviewEvent implements the Store interface.
+func (s *mysqlStore) StorePullRequestReviewEvent(event *github.PullRequestReviewEvent) error {
+	return storePullRequestReviewEvent(s.handle, event)
+}
+
+// GetPullRequests implements the Store interface.
+func (s *mysqlStore) GetPullRequests(window time.Duration) ([]*PullRequest, error) {
+	return getPullRequests(s.handle, window)
+}
+
+// PrunePullRequests implements the Store interface.
+func (s *mysqlStore) PrunePullRequests(timeframe time.Duration) error {
+	return prunePullRequests(s.handle, timeframe)
+}
+
+// StoreDeploymentEvent implements the Store interface.
+func (s *mysqlStore) StoreDeploymentEvent(event *github.DeploymentEvent) error {
+	return storeDeploymentEvent(s.handle, event)
+}
+
+// StoreDeploymentStatusEvent implements the Store interface.
+func (s *mysqlStore) StoreDeploymentStatusEvent(event *github.DeploymentStatusEvent) error {
+	return storeDeploymentStatusEvent(s.handle, event)
+}
+
+// GetDeployments implements the Store interface.
+func (s *mysqlStore) GetDeployments(window time.Duration) ([]*Deployment, error) {
+	return getDeployments(s.handle, window)
+}
+
+// PruneDeployments implements the Store interface.
+func (s *mysqlStore) PruneDeployments(timeframe time.Duration) error {
+	return pruneDeployments(s.handle, timeframe)
+}
+
+// ReserveWebhookDelivery implements the Store interface.
+func (s *mysqlStore) ReserveWebhookDelivery(record *WebhookDelivery) (bool, error) {
+	return reserveWebhookDelivery(s.handle, record)
+}
+
+// ReleaseWebhookDelivery implements the Store interface.
+func (s *mysqlStore) ReleaseWebhookDelivery(delivery string) error {
+	return releaseWebhookDelivery(s.handle, delivery)
+}
+
+// PruneWebhookDeliveries implements the Store interface.
+func (s *mysqlStore) PruneWebhookDeliveries(timeframe time.Duration) error {
+	return pruneWebhookDeliveries(s.handle, timeframe)
+}
+
 func (s *mysqlStore) dsn() string {
 	if s.password != "" {
 		return fmt.Sprintf(
@@ -83,6 +83,203 @@ var (
 			Description: "Fix run_id be BIGINT",
 			Script:      `ALTER TABLE workflow_jobs ALTER COLUMN run_id TYPE BIGINT USING run_id::BIGINT;`,
 		},
+		{
+			Version:     6,
+			Description: "Creating table check_suites",
+			Script: `CREATE TABLE check_suites (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				identifier BIGINT NOT NULL,
+				app TEXT,
+				status TEXT,
+				conclusion TEXT,
+				branch TEXT,
+				sha TEXT,
+				created_at BIGINT,
+				updated_at BIGINT,
+				PRIMARY KEY(owner, repo, identifier)
+			);`,
+		},
+		{
+			Version:     7,
+			Description: "Creating table check_runs",
+			Script: `CREATE TABLE check_runs (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				identifier BIGINT NOT NULL,
+				suite_id BIGINT,
+				name TEXT,
+				app TEXT,
+				status TEXT,
+				conclusion TEXT,
+				branch TEXT,
+				sha TEXT,
+				started_at BIGINT,
+				completed_at BIGINT,
+				PRIMARY KEY(owner, repo, identifier)
+			);`,
+		},
+		{
+			Version:     8,
+			Description: "Creating table pull_requests",
+			Script: `CREATE TABLE pull_requests (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				number INTEGER NOT NULL,
+				identifier BIGINT,
+				base TEXT,
+				head TEXT,
+				author TEXT,
+				state TEXT,
+				draft BOOLEAN,
+				created_at BIGINT,
+				updated_at BIGINT,
+				closed_at BIGINT,
+				merged_at BIGINT,
+				first_review_at BIGINT,
+				PRIMARY KEY(owner, repo, number)
+			);`,
+		},
+		{
+			Version:     9,
+			Description: "Creating table deployments",
+			Script: `CREATE TABLE deployments (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				identifier BIGINT NOT NULL,
+				environment TEXT,
+				ref TEXT,
+				sha TEXT,
+				task TEXT,
+				creator TEXT,
+				status TEXT,
+				created_at BIGINT,
+				updated_at BIGINT,
+				status_at BIGINT,
+				PRIMARY KEY(owner, repo, identifier)
+			);`,
+		},
+		{
+			Version:     10,
+			Description: "Creating table webhook_deliveries",
+			Script: `CREATE TABLE webhook_deliveries (
+				delivery TEXT NOT NULL,
+				event TEXT,
+				created_at BIGINT,
+				PRIMARY KEY(delivery)
+			);`,
+		},
+		{
+			Version:     11,
+			Description: "Defaulting attempt column of workflow_runs table",
+			Script:      `UPDATE workflow_runs SET attempt = 1 WHERE attempt IS NULL;`,
+		},
+		{
+			Version:     12,
+			Description: "Dropping primary key of workflow_runs table",
+			Script:      `ALTER TABLE workflow_runs DROP CONSTRAINT workflow_runs_pkey;`,
+		},
+		{
+			Version:     13,
+			Description: "Adding attempt to primary key of workflow_runs table",
+			Script:      `ALTER TABLE workflow_runs ADD PRIMARY KEY (owner, repo, workflow_id, number, attempt);`,
+		},
+		{
+			Version:     14,
+			Description: "Adding conclusion column to workflow_runs table",
+			Script:      `ALTER TABLE workflow_runs ADD COLUMN conclusion TEXT;`,
+		},
+		{
+			Version:     15,
+			Description: "Splitting conclusion from status column of workflow_runs table",
+			Script:      `UPDATE workflow_runs SET conclusion = status, status = 'completed' WHERE status NOT IN ('requested', 'queued', 'waiting', 'pending', 'in_progress', 'completed');`,
+		},
+		{
+			Version:     16,
+			Description: "Creating table workflow_totals",
+			Script: `CREATE TABLE workflow_totals (
+				kind TEXT NOT NULL,
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				workflow TEXT NOT NULL,
+				conclusion TEXT NOT NULL,
+				le BIGINT NOT NULL,
+				count BIGINT,
+				duration BIGINT,
+				PRIMARY KEY(kind, owner, repo, workflow, conclusion, le)
+			);`,
+		},
+		{
+			Version:     17,
+			Description: "Creating table workflow_job_steps",
+			Script: `CREATE TABLE workflow_job_steps (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				workflow_name TEXT,
+				job_name TEXT,
+				job_id BIGINT NOT NULL,
+				number BIGINT NOT NULL,
+				name TEXT,
+				status TEXT,
+				conclusion TEXT,
+				created_at BIGINT,
+				started_at BIGINT,
+				completed_at BIGINT,
+				PRIMARY KEY(owner, repo, job_id, number)
+			);`,
+		},
+		{
+			Version:     18,
+			Description: "Adding html_url column to workflow_runs table",
+			Script:      `ALTER TABLE workflow_runs ADD COLUMN html_url TEXT DEFAULT '';`,
+		},
+		{
+			Version:     19,
+			Description: "Adding html_url column to workflow_jobs table",
+			Script:      `ALTER TABLE workflow_jobs ADD COLUMN html_url TEXT DEFAULT '';`,
+		},
+		{
+			Version:     20,
+			Description: "Creating table workflows",
+			Script: `CREATE TABLE workflows (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				identifier BIGINT NOT NULL,
+				name TEXT,
+				path TEXT,
+				state TEXT,
+				updated_at BIGINT,
+				PRIMARY KEY(owner, repo, identifier)
+			);`,
+		},
+		{
+			Version:     21,
+			Description: "Creating table repositories",
+			Script: `CREATE TABLE repositories (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				default_branch TEXT,
+				updated_at BIGINT,
+				PRIMARY KEY(owner, repo)
+			);`,
+		},
+		{
+			Version:     22,
+			Description: "Creating table duration_totals",
+			Script: `CREATE TABLE duration_totals (
+				kind TEXT NOT NULL,
+				labels TEXT NOT NULL,
+				le BIGINT NOT NULL,
+				count BIGINT,
+				duration BIGINT,
+				PRIMARY KEY(kind, labels, le)
+			);`,
+		},
+		{
+			Version:     23,
+			Description: "Adding commit_at column to workflow_runs table",
+			Script:      `ALTER TABLE workflow_runs ADD COLUMN commit_at BIGINT DEFAULT 0;`,
+		},
 	}
 )
 
@@ -167,11 +364,41 @@ func (s *postgresStore) GetWorkflowRuns(window time.Duration) ([]*WorkflowRun, e
 	return getWorkflowRuns(s.handle, window)
 }
 
+// GetPendingWorkflowRuns implements the Store interface.
+func (s *postgresStore) GetPendingWorkflowRuns(threshold time.Duration) ([]*WorkflowRun, error) {
+	return getPendingWorkflowRuns(s.handle, threshold)
+}
+
+// DeleteWorkflowRun implements the Store interface.
+func (s *postgresStore) DeleteWorkflowRun(record *WorkflowRun) error {
+	return deleteWorkflowRun(s.handle, record)
+}
+
 // PruneWorkflowRuns implements the Store interface.
 func (s *postgresStore) PruneWorkflowRuns(timeframe time.Duration) error {
 	return pruneWorkflowRuns(s.handle, timeframe)
 }
 
+// StoreWorkflow implements the Store interface.
+func (s *postgresStore) StoreWorkflow(record *Workflow) error {
+	return storeWorkflow(s.handle, record)
+}
+
+// GetWorkflows implements the Store interface.
+func (s *postgresStore) GetWorkflows() ([]*Workflow, error) {
+	return getWorkflows(s.handle)
+}
+
+// StoreRepository implements the Store interface.
+func (s *postgresStore) StoreRepository(record *Repository) error {
+	return storeRepository(s.handle, record)
+}
+
+// GetRepositories implements the Store interface.
+func (s *postgresStore) GetRepositories() ([]*Repository, error) {
+	return getRepositories(s.handle)
+}
+
 // StoreWorkflowJobEvent implements the Store interface.
 func (s *postgresStore) StoreWorkflowJobEvent(event *github.WorkflowJobEvent) error {
 	return storeWorkflowJobEvent(s.handle, event)
@@ -182,11 +409,136 @@ func (s *postgresStore) GetWorkflowJobs(window time.Duration) ([]*WorkflowJob, e
 	return getWorkflowJobs(s.handle, window)
 }
 
+// GetPendingWorkflowJobs implements the Store interface.
+func (s *postgresStore) GetPendingWorkflowJobs(threshold time.Duration) ([]*WorkflowJob, error) {
+	return getPendingWorkflowJobs(s.handle, threshold)
+}
+
+// DeleteWorkflowJob implements the Store interface.
+func (s *postgresStore) DeleteWorkflowJob(record *WorkflowJob) error {
+	return deleteWorkflowJob(s.handle, record)
+}
+
 // PruneWorkflowJobs implements the Store interface.
 func (s *postgresStore) PruneWorkflowJobs(timeframe time.Duration) error {
 	return pruneWorkflowJobs(s.handle, timeframe)
 }
 
+// GetWorkflowJobSteps implements the Store interface.
+func (s *postgresStore) GetWorkflowJobSteps(window time.Duration) ([]*WorkflowJobStep, error) {
+	return getWorkflowJobSteps(s.handle, window)
+}
+
+// GetWorkflowJobFlakes implements the Store interface.
+func (s *postgresStore) GetWorkflowJobFlakes(window time.Duration) ([]*WorkflowJobFlake, error) {
+	return getWorkflowJobFlakes(s.handle, window)
+}
+
+// GetWorkflowTotals implements the Store interface.
+func (s *postgresStore) GetWorkflowTotals() ([]*WorkflowTotal, error) {
+	return getWorkflowTotals(s.handle)
+}
+
+// GetDurationTotals implements the Store interface.
+func (s *postgresStore) GetDurationTotals(kind string) ([]*DurationTotal, error) {
+	return getDurationTotals(s.handle, kind)
+}
+
+// GetWorkflowRunSummaries implements the Store interface.
+func (s *postgresStore) GetWorkflowRunSummaries(window time.Duration) ([]*WorkflowSummary, error) {
+	return getNativeWorkflowRunSummaries(s.handle, window)
+}
+
+// GetWorkflowJobSummaries implements the Store interface.
+func (s *postgresStore) GetWorkflowJobSummaries(window time.Duration) ([]*WorkflowSummary, error) {
+	return getNativeWorkflowJobSummaries(s.handle, window)
+}
+
+// StoreCheckSuiteEvent implements the Store interface.
+func (s *postgresStore) StoreCheckSuiteEvent(event *github.CheckSuiteEvent) error {
+	return storeCheckSuiteEvent(s.handle, event)
+}
+
+// GetCheckSuites implements the Store interface.
+func (s *postgresStore) GetCheckSuites(window time.Duration) ([]*CheckSuite, error) {
+	return getCheckSuites(s.handle, window)
+}
+
+// PruneCheckSuites implements the Store interface.
+func (s *postgresStore) PruneCheckSuites(timeframe time.Duration) error {
+	return pruneCheckSuites(s.handle, timeframe)
+}
+
+// StoreCheckRunEvent implements the Store interface.
+func (s *postgresStore) StoreCheckRunEvent(event *github.CheckRunEvent) error {
+	return storeCheckRunEvent(s.handle, event)
+}
+
+// GetCheckRuns implements the Store interface.
+func (s *postgresStore) GetCheckRuns(window time.Duration) ([]*CheckRun, error) {
+	return getCheckRuns(s.handle, window)
+}
+
+// PruneCheckRuns implements the Store interface.
+func (s *postgresStore) PruneCheckRuns(timeframe time.Duration) error {
+	return pruneCheckRuns(s.handle, timeframe)
+}
+
+// StorePullRequestEvent implements the Store interface.
+func (s *postgresStore) StorePullRequestEvent(event *github.PullRequestEvent) error {
+	return storePullRequestEvent(s.handle, event)
+}
+
+// StorePullRequestReviewEvent implements the Store interface.
+func (s *postgresStore) StorePullRequestReviewEvent(event *github.PullRequestReviewEvent) error {
+	return storePullRequestReviewEvent(s.handle, event)
+}
+
+// GetPullRequests implements the Store interface.
+func (s *postgresStore) GetPullRequests(window time.Duration) ([]*PullRequest, error) {
+	return getPullRequests(s.handle, window)
+}
+
+// PrunePullRequests implements the Store interface.
+func (s *postgresStore) PrunePullRequests(timeframe time.Duration) error {
+	return prunePullRequests(s.handle, timeframe)
+}
+
+// StoreDeploymentEvent implements the Store interface.
+func (s *postgresStore) StoreDeploymentEvent(event *github.DeploymentEvent) error {
+	return storeDeploymentEvent(s.handle, event)
+}
+
+// StoreDeploymentStatusEvent implements the Store interface.
+func (s *postgresStore) StoreDeploymentStatusEvent(event *github.DeploymentStatusEvent) error {
+	return storeDeploymentStatusEvent(s.handle, event)
+}
+
+// GetDeployments implements the Store interface.
+func (s *postgresStore) GetDeployments(window time.Duration) ([]*Deployment, error) {
+	return getDeployments(s.handle, window)
+}
+
+// PruneDeployments implements the Store interface.
+func (s *postgresStore) PruneDeployments(timeframe time.Duration) error {
+	return pruneDeployments(s.handle, timeframe)
+}
+
+// ReserveWebhookDelivery implements the Store interface.
+func (s *postgresStore) ReserveWebhookDelivery(record *WebhookDelivery) (bool, error) {
+	return reserveWebhookDelivery(s.handle, record)
+}
+
+// ReleaseWebhookDelivery implements the Store interface.
+func (s *postgresStore) ReleaseWebhookDelivery(delivery string) error {
+	return releaseWebhookDelivery(s.handle, delivery)
+}
+
+// PruneWebhookDeliveries implements the Store interface.
+func (s *postgresStore) PruneWebhookDeliveries(timeframe time.Duration) error {
+	return pruneWebhookDeliveries(s.handle, timeframe)
+}
+
 func (s *postgresStore) dsn() string {
 	dsn := fmt.Sprintf(
 		"host=%s port=%s dbname=%s user=%s",
@@ -74,6 +74,235 @@ var (
 				PRIMARY KEY(owner, repo, identifier)
 			);`,
 		},
+		{
+			Version:     4,
+			Description: "Creating table check_suites",
+			Script: `CREATE TABLE check_suites (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				identifier BIGINT NOT NULL,
+				app TEXT,
+				status TEXT,
+				conclusion TEXT,
+				branch TEXT,
+				sha TEXT,
+				created_at BIGINT,
+				updated_at BIGINT,
+				PRIMARY KEY(owner, repo, identifier)
+			);`,
+		},
+		{
+			Version:     5,
+			Description: "Creating table check_runs",
+			Script: `CREATE TABLE check_runs (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				identifier BIGINT NOT NULL,
+				suite_id BIGINT,
+				name TEXT,
+				app TEXT,
+				status TEXT,
+				conclusion TEXT,
+				branch TEXT,
+				sha TEXT,
+				started_at BIGINT,
+				completed_at BIGINT,
+				PRIMARY KEY(owner, repo, identifier)
+			);`,
+		},
+		{
+			Version:     6,
+			Description: "Creating table pull_requests",
+			Script: `CREATE TABLE pull_requests (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				number INTEGER NOT NULL,
+				identifier BIGINT,
+				base TEXT,
+				head TEXT,
+				author TEXT,
+				state TEXT,
+				draft BOOLEAN,
+				created_at BIGINT,
+				updated_at BIGINT,
+				closed_at BIGINT,
+				merged_at BIGINT,
+				first_review_at BIGINT,
+				PRIMARY KEY(owner, repo, number)
+			);`,
+		},
+		{
+			Version:     7,
+			Description: "Creating table deployments",
+			Script: `CREATE TABLE deployments (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				identifier BIGINT NOT NULL,
+				environment TEXT,
+				ref TEXT,
+				sha TEXT,
+				task TEXT,
+				creator TEXT,
+				status TEXT,
+				created_at BIGINT,
+				updated_at BIGINT,
+				status_at BIGINT,
+				PRIMARY KEY(owner, repo, identifier)
+			);`,
+		},
+		{
+			Version:     8,
+			Description: "Creating table webhook_deliveries",
+			Script: `CREATE TABLE webhook_deliveries (
+				delivery TEXT NOT NULL,
+				event TEXT,
+				created_at BIGINT,
+				PRIMARY KEY(delivery)
+			);`,
+		},
+		{
+			Version:     9,
+			Description: "Creating table workflow_run_attempts",
+			Script: `CREATE TABLE workflow_run_attempts (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				workflow_id INTEGER NOT NULL,
+				number INTEGER NOT NULL,
+				attempt INTEGER NOT NULL,
+				event TEXT,
+				name TEXT,
+				title TEXT,
+				status TEXT,
+				branch TEXT,
+				sha TEXT,
+				identifier BIGINT,
+				actor TEXT,
+				created_at BIGINT,
+				updated_at BIGINT,
+				started_at BIGINT,
+				PRIMARY KEY(owner, repo, workflow_id, number, attempt)
+			);`,
+		},
+		{
+			Version:     10,
+			Description: "Defaulting attempt column of workflow_runs table",
+			Script:      `UPDATE workflow_runs SET attempt = 1 WHERE attempt IS NULL;`,
+		},
+		{
+			Version:     11,
+			Description: "Copying workflow_runs into workflow_run_attempts table",
+			Script: `INSERT INTO workflow_run_attempts (
+				owner, repo, workflow_id, number, attempt, event, name, title, status, branch, sha, identifier, actor, created_at, updated_at, started_at
+			) SELECT
+				owner, repo, workflow_id, number, attempt, event, name, title, status, branch, sha, identifier, actor, created_at, updated_at, started_at
+			FROM workflow_runs;`,
+		},
+		{
+			Version:     12,
+			Description: "Dropping table workflow_runs",
+			Script:      `DROP TABLE workflow_runs;`,
+		},
+		{
+			Version:     13,
+			Description: "Renaming table workflow_run_attempts to workflow_runs",
+			Script:      `ALTER TABLE workflow_run_attempts RENAME TO workflow_runs;`,
+		},
+		{
+			Version:     14,
+			Description: "Adding conclusion column to workflow_runs table",
+			Script:      `ALTER TABLE workflow_runs ADD COLUMN conclusion TEXT;`,
+		},
+		{
+			Version:     15,
+			Description: "Splitting conclusion from status column of workflow_runs table",
+			Script:      `UPDATE workflow_runs SET conclusion = status, status = 'completed' WHERE status NOT IN ('requested', 'queued', 'waiting', 'pending', 'in_progress', 'completed');`,
+		},
+		{
+			Version:     16,
+			Description: "Creating table workflow_totals",
+			Script: `CREATE TABLE workflow_totals (
+				kind TEXT NOT NULL,
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				workflow TEXT NOT NULL,
+				conclusion TEXT NOT NULL,
+				le BIGINT NOT NULL,
+				count BIGINT,
+				duration BIGINT,
+				PRIMARY KEY(kind, owner, repo, workflow, conclusion, le)
+			);`,
+		},
+		{
+			Version:     17,
+			Description: "Creating table workflow_job_steps",
+			Script: `CREATE TABLE workflow_job_steps (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				workflow_name TEXT,
+				job_name TEXT,
+				job_id BIGINT NOT NULL,
+				number BIGINT NOT NULL,
+				name TEXT,
+				status TEXT,
+				conclusion TEXT,
+				created_at BIGINT,
+				started_at BIGINT,
+				completed_at BIGINT,
+				PRIMARY KEY(owner, repo, job_id, number)
+			);`,
+		},
+		{
+			Version:     18,
+			Description: "Adding html_url column to workflow_runs table",
+			Script:      `ALTER TABLE workflow_runs ADD COLUMN html_url TEXT DEFAULT '';`,
+		},
+		{
+			Version:     19,
+			Description: "Adding html_url column to workflow_jobs table",
+			Script:      `ALTER TABLE workflow_jobs ADD COLUMN html_url TEXT DEFAULT '';`,
+		},
+		{
+			Version:     20,
+			Description: "Creating table workflows",
+			Script: `CREATE TABLE workflows (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				identifier BIGINT NOT NULL,
+				name TEXT,
+				path TEXT,
+				state TEXT,
+				updated_at BIGINT,
+				PRIMARY KEY(owner, repo, identifier)
+			);`,
+		},
+		{
+			Version:     21,
+			Description: "Creating table repositories",
+			Script: `CREATE TABLE repositories (
+				owner TEXT NOT NULL,
+				repo TEXT NOT NULL,
+				default_branch TEXT,
+				updated_at BIGINT,
+				PRIMARY KEY(owner, repo)
+			);`,
+		},
+		{
+			Version:     22,
+			Description: "Creating table duration_totals",
+			Script: `CREATE TABLE duration_totals (
+				kind TEXT NOT NULL,
+				labels TEXT NOT NULL,
+				le BIGINT NOT NULL,
+				count BIGINT,
+				duration BIGINT,
+				PRIMARY KEY(kind, labels, le)
+			);`,
+		},
+		{
+			Version:     23,
+			Description: "Adding commit_at column to workflow_runs table",
+			Script:      `ALTER TABLE workflow_runs ADD COLUMN commit_at BIGINT DEFAULT 0;`,
+		},
 	}
 )
 
@@ -154,11 +383,41 @@ func (s *sqliteStore) GetWorkflowRuns(window time.Duration) ([]*WorkflowRun, err
 	return getWorkflowRuns(s.handle, window)
 }
 
+// GetPendingWorkflowRuns implements the Store interface.
+func (s *sqliteStore) GetPendingWorkflowRuns(threshold time.Duration) ([]*WorkflowRun, error) {
+	return getPendingWorkflowRuns(s.handle, threshold)
+}
+
+// DeleteWorkflowRun implements the Store interface.
+func (s *sqliteStore) DeleteWorkflowRun(record *WorkflowRun) error {
+	return deleteWorkflowRun(s.handle, record)
+}
+
 // PruneWorkflowRuns implements the Store interface.
 func (s *sqliteStore) PruneWorkflowRuns(timeframe time.Duration) error {
 	return pruneWorkflowRuns(s.handle, timeframe)
 }
 
+// StoreWorkflow implements the Store interface.
+func (s *sqliteStore) StoreWorkflow(record *Workflow) error {
+	return storeWorkflow(s.handle, record)
+}
+
+// GetWorkflows implements the Store interface.
+func (s *sqliteStore) GetWorkflows() ([]*Workflow, error) {
+	return getWorkflows(s.handle)
+}
+
+// StoreRepository implements the Store interface.
+func (s *sqliteStore) StoreRepository(record *Repository) error {
+	return storeRepository(s.handle, record)
+}
+
+// GetRepositories implements the Store interface.
+func (s *sqliteStore) GetRepositories() ([]*Repository, error) {
+	return getRepositories(s.handle)
+}
+
 // StoreWorkflowJobEvent implements the Store interface.
 func (s *sqliteStore) StoreWorkflowJobEvent(event *github.WorkflowJobEvent) error {
 	return storeWorkflowJobEvent(s.handle, event)
@@ -169,11 +428,136 @@ func (s *sqliteStore) GetWorkflowJobs(window time.Duration) ([]*WorkflowJob, err
 	return getWorkflowJobs(s.handle, window)
 }
 
+// GetPendingWorkflowJobs implements the Store interface.
+func (s *sqliteStore) GetPendingWorkflowJobs(threshold time.Duration) ([]*WorkflowJob, error) {
+	return getPendingWorkflowJobs(s.handle, threshold)
+}
+
+// DeleteWorkflowJob implements the Store interface.
+func (s *sqliteStore) DeleteWorkflowJob(record *WorkflowJob) error {
+	return deleteWorkflowJob(s.handle, record)
+}
+
 // PruneWorkflowJobs implements the Store interface.
 func (s *sqliteStore) PruneWorkflowJobs(timeframe time.Duration) error {
 	return pruneWorkflowJobs(s.handle, timeframe)
 }
 
+// GetWorkflowJobSteps implements the Store interface.
+func (s *sqliteStore) GetWorkflowJobSteps(window time.Duration) ([]*WorkflowJobStep, error) {
+	return getWorkflowJobSteps(s.handle, window)
+}
+
+// GetWorkflowJobFlakes implements the Store interface.
+func (s *sqliteStore) GetWorkflowJobFlakes(window time.Duration) ([]*WorkflowJobFlake, error) {
+	return getWorkflowJobFlakes(s.handle, window)
+}
+
+// GetWorkflowTotals implements the Store interface.
+func (s *sqliteStore) GetWorkflowTotals() ([]*WorkflowTotal, error) {
+	return getWorkflowTotals(s.handle)
+}
+
+// GetDurationTotals implements the Store interface.
+func (s *sqliteStore) GetDurationTotals(kind string) ([]*DurationTotal, error) {
+	return getDurationTotals(s.handle, kind)
+}
+
+// GetWorkflowRunSummaries implements the Store interface.
+func (s *sqliteStore) GetWorkflowRunSummaries(window time.Duration) ([]*WorkflowSummary, error) {
+	return getWorkflowRunSummaries(s.handle, window)
+}
+
+// GetWorkflowJobSummaries implements the Store interface.
+func (s *sqliteStore) GetWorkflowJobSummaries(window time.Duration) ([]*WorkflowSummary, error) {
+	return getWorkflowJobSummaries(s.handle, window)
+}
+
+// StoreCheckSuiteEvent implements the Store interface.
+func (s *sqliteStore) StoreCheckSuiteEvent(event *github.CheckSuiteEvent) error {
+	return storeCheckSuiteEvent(s.handle, event)
+}
+
+// GetCheckSuites implements the Store interface.
+func (s *sqliteStore) GetCheckSuites(window time.Duration) ([]*CheckSuite, error) {
+	return getCheckSuites(s.handle, window)
+}
+
+// PruneCheckSuites implements the Store interface.
+func (s *sqliteStore) PruneCheckSuites(timeframe time.Duration) error {
+	return pruneCheckSuites(s.handle, timeframe)
+}
+
+// StoreCheckRunEvent implements the Store interface.
+func (s *sqliteStore) StoreCheckRunEvent(event *github.CheckRunEvent) error {
+	return storeCheckRunEvent(s.handle, event)
+}
+
+// GetCheckRuns implements the Store interface.
+func (s *sqliteStore) GetCheckRuns(window time.Duration) ([]*CheckRun, error) {
+	return getCheckRuns(s.handle, window)
+}
+
+// PruneCheckRuns implements the Store interface.
+func (s *sqliteStore) PruneCheckRuns(timeframe time.Duration) error {
+	return pruneCheckRuns(s.handle, timeframe)
+}
+
+// StorePullRequestEvent implements the Store interface.
+func (s *sqliteStore) StorePullRequestEvent(event *github.PullRequestEvent) error {
+	return storePullRequestEvent(s.handle, event)
+}
+
+// StorePullRequestReviewEvent implements the Store interface.
+func (s *sqliteStore) StorePullRequestReviewEvent(event *github.PullRequestReviewEvent) error {
+	return storePullRequestReviewEvent(s.handle, event)
+}
+
+// GetPullRequests implements the Store interface.
+func (s *sqliteStore) GetPullRequests(window time.Duration) ([]*PullRequest, error) {
+	return getPullRequests(s.handle, window)
+}
+
+// PrunePullRequests implements the Store interface.
+func (s *sqliteStore) PrunePullRequests(timeframe time.Duration) error {
+	return prunePullRequests(s.handle, timeframe)
+}
+
+// StoreDeploymentEvent implements the Store interface.
+func (s *sqliteStore) StoreDeploymentEvent(event *github.DeploymentEvent) error {
+	return storeDeploymentEvent(s.handle, event)
+}
+
+// StoreDeploymentStatusEvent implements the Store interface.
+func (s *sqliteStore) StoreDeploymentStatusEvent(event *github.DeploymentStatusEvent) error {
+	return storeDeploymentStatusEvent(s.handle, event)
+}
+
+// GetDeployments implements the Store interface.
+func (s *sqliteStore) GetDeployments(window time.Duration) ([]*Deployment, error) {
+	return getDeployments(s.handle, window)
+}
+
+// PruneDeployments implements the Store interface.
+func (s *sqliteStore) PruneDeployments(timeframe time.Duration) error {
+	return pruneDeployments(s.handle, timeframe)
+}
+
+// ReserveWebhookDelivery implements the Store interface.
+func (s *sqliteStore) ReserveWebhookDelivery(record *WebhookDelivery) (bool, error) {
+	return reserveWebhookDelivery(s.handle, record)
+}
+
+// ReleaseWebhookDelivery implements the Store interface.
+func (s *sqliteStore) ReleaseWebhookDelivery(delivery string) error {
+	return releaseWebhookDelivery(s.handle, delivery)
+}
+
+// PruneWebhookDeliveries implements the Store interface.
+func (s *sqliteStore) PruneWebhookDeliveries(timeframe time.Duration) error {
+	return pruneWebhookDeliveries(s.handle, timeframe)
+}
+
 func (s *sqliteStore) dsn() string {
 	if len(s.meta) > 0 {
 		return fmt.Sprintf(
@@ -204,6 +588,7 @@ func NewSqliteStore(dsn string, logger *slog.Logger) (Store, error) {
 	client.meta.Add("_pragma", "journal_mode(WAL)")
 	client.meta.Add("_pragma", "busy_timeout(5000)")
 	client.meta.Add("_pragma", "foreign_keys(1)")
+	client.meta.Add("_txlock", "immediate")
 
 	return client, nil
 }
@@ -0,0 +1,30 @@
+//go:build sqlite
+
+package store
+
+import (
+	"log/slog"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestSqliteStore(t *testing.T) {
+	s, err := New("sqlite://"+path.Join(t.TempDir(), "exporter.db"), slog.New(slog.NewTextHandler(os.Stdout, nil)))
+
+	if err != nil {
+		t.Fatalf("Failed to setup store: %v", err)
+	}
+
+	if _, err := s.Open(); err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	defer s.Close()
+
+	if err := s.Migrate(); err != nil {
+		t.Fatalf("Failed to migrate store: %v", err)
+	}
+
+	testStore(t, s)
+}
@@ -24,13 +24,68 @@ type Store interface {
 	// WorkflowRunEvent
 	StoreWorkflowRunEvent(*github.WorkflowRunEvent) error
 	GetWorkflowRuns(time.Duration) ([]*WorkflowRun, error)
+	GetPendingWorkflowRuns(time.Duration) ([]*WorkflowRun, error)
+	DeleteWorkflowRun(*WorkflowRun) error
 	PruneWorkflowRuns(time.Duration) error
 
+	// Workflow
+	StoreWorkflow(*Workflow) error
+	GetWorkflows() ([]*Workflow, error)
+
+	// Repository
+	StoreRepository(*Repository) error
+	GetRepositories() ([]*Repository, error)
+
 	// WorkflowJobEvent
 	StoreWorkflowJobEvent(*github.WorkflowJobEvent) error
 	GetWorkflowJobs(time.Duration) ([]*WorkflowJob, error)
+	GetPendingWorkflowJobs(time.Duration) ([]*WorkflowJob, error)
+	DeleteWorkflowJob(*WorkflowJob) error
 	PruneWorkflowJobs(time.Duration) error
 
+	// WorkflowJobStep
+	GetWorkflowJobSteps(time.Duration) ([]*WorkflowJobStep, error)
+
+	// WorkflowJobFlake
+	GetWorkflowJobFlakes(time.Duration) ([]*WorkflowJobFlake, error)
+
+	// WorkflowTotal
+	GetWorkflowTotals() ([]*WorkflowTotal, error)
+
+	// DurationTotal
+	GetDurationTotals(string) ([]*DurationTotal, error)
+
+	// WorkflowSummary
+	GetWorkflowRunSummaries(time.Duration) ([]*WorkflowSummary, error)
+	GetWorkflowJobSummaries(time.Duration) ([]*WorkflowSummary, error)
+
+	// CheckSuiteEvent
+	StoreCheckSuiteEvent(*github.CheckSuiteEvent) error
+	GetCheckSuites(time.Duration) ([]*CheckSuite, error)
+	PruneCheckSuites(time.Duration) error
+
+	// CheckRunEvent
+	StoreCheckRunEvent(*github.CheckRunEvent) error
+	GetCheckRuns(time.Duration) ([]*CheckRun, error)
+	PruneCheckRuns(time.Duration) error
+
+	// PullRequestEvent
+	StorePullRequestEvent(*github.PullRequestEvent) error
+	StorePullRequestReviewEvent(*github.PullRequestReviewEvent) error
+	GetPullRequests(time.Duration) ([]*PullRequest, error)
+	PrunePullRequests(time.Duration) error
+
+	// DeploymentEvent
+	StoreDeploymentEvent(*github.DeploymentEvent) error
+	StoreDeploymentStatusEvent(*github.DeploymentStatusEvent) error
+	GetDeployments(time.Duration) ([]*Deployment, error)
+	PruneDeployments(time.Duration) error
+
+	// WebhookDelivery
+	ReserveWebhookDelivery(*WebhookDelivery) (bool, error)
+	ReleaseWebhookDelivery(string) error
+	PruneWebhookDeliveries(time.Duration) error
+
 	Open() (bool, error)
 	Close() error
 	Ping() (bool, error)
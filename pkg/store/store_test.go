@@ -0,0 +1,629 @@
+package store
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v72/github"
+)
+
+var (
+	testRepo = &github.Repository{
+		Name: github.Ptr("repo"),
+		Owner: &github.User{
+			Login: github.Ptr("owner"),
+		},
+	}
+)
+
+func TestMemoryStore(t *testing.T) {
+	s, err := New("memory://", slog.New(slog.NewTextHandler(os.Stdout, nil)))
+
+	if err != nil {
+		t.Fatalf("Failed to setup store: %v", err)
+	}
+
+	testStore(t, s)
+}
+
+// testStore runs the same out of order deliveries against every driver, the
+// results have to be identical for all of them.
+func testStore(t *testing.T, s Store) {
+	now := time.Now().Truncate(time.Second)
+
+	t.Run("workflow run", func(t *testing.T) {
+		run := func(status string, updatedAt time.Time) *github.WorkflowRunEvent {
+			return &github.WorkflowRunEvent{
+				Repo: testRepo,
+				WorkflowRun: &github.WorkflowRun{
+					ID:           github.Ptr(int64(1)),
+					WorkflowID:   github.Ptr(int64(2)),
+					RunNumber:    github.Ptr(3),
+					RunAttempt:   github.Ptr(1),
+					Name:         github.Ptr("CI"),
+					Status:       github.Ptr(status),
+					Conclusion:   github.Ptr("success"),
+					CreatedAt:    &github.Timestamp{Time: now.Add(-time.Hour)},
+					RunStartedAt: &github.Timestamp{Time: now.Add(-time.Hour)},
+					UpdatedAt:    &github.Timestamp{Time: updatedAt},
+				},
+			}
+		}
+
+		for _, event := range []*github.WorkflowRunEvent{
+			run("completed", now),
+			run("in_progress", now.Add(-time.Minute)),
+			run("in_progress", now),
+			run("completed", now),
+		} {
+			if err := s.StoreWorkflowRunEvent(event); err != nil {
+				t.Fatalf("Failed to store workflow run: %v", err)
+			}
+		}
+
+		records, err := s.GetWorkflowRuns(2 * time.Hour)
+
+		if err != nil {
+			t.Fatalf("Failed to get workflow runs: %v", err)
+		}
+
+		if len(records) != 1 || records[0].Status != "completed" {
+			t.Errorf("Expected a single completed workflow run, got %+v", records)
+		}
+
+		if count := testTotalCount(t, s, "workflow_run"); count != 1 {
+			t.Errorf("Expected workflow run to be counted once, got %d", count)
+		}
+	})
+
+	t.Run("workflow run attempts", func(t *testing.T) {
+		run := func(attempt int, status, conclusion string, updatedAt time.Time) *github.WorkflowRunEvent {
+			return &github.WorkflowRunEvent{
+				Repo: testRepo,
+				WorkflowRun: &github.WorkflowRun{
+					ID:           github.Ptr(int64(7)),
+					WorkflowID:   github.Ptr(int64(3)),
+					RunNumber:    github.Ptr(7),
+					RunAttempt:   github.Ptr(attempt),
+					Name:         github.Ptr("Release"),
+					Status:       github.Ptr(status),
+					Conclusion:   github.Ptr(conclusion),
+					CreatedAt:    &github.Timestamp{Time: now.Add(-time.Hour)},
+					RunStartedAt: &github.Timestamp{Time: now.Add(-time.Hour)},
+					UpdatedAt:    &github.Timestamp{Time: updatedAt},
+				},
+			}
+		}
+
+		for _, event := range []*github.WorkflowRunEvent{
+			run(1, "in_progress", "", now.Add(-50*time.Minute)),
+			run(2, "queued", "", now.Add(-20*time.Minute)),
+			run(1, "completed", "failure", now.Add(-30*time.Minute)),
+			run(2, "completed", "success", now.Add(-10*time.Minute)),
+			run(1, "completed", "failure", now.Add(-5*time.Minute)),
+		} {
+			if err := s.StoreWorkflowRunEvent(event); err != nil {
+				t.Fatalf("Failed to store workflow run: %v", err)
+			}
+		}
+
+		records, err := s.GetWorkflowRuns(2 * time.Hour)
+
+		if err != nil {
+			t.Fatalf("Failed to get workflow runs: %v", err)
+		}
+
+		attempts := make(map[int]*WorkflowRun)
+
+		for _, record := range records {
+			if record.Identifier == 7 {
+				attempts[record.Attempt] = record
+			}
+		}
+
+		if len(attempts) != 2 {
+			t.Fatalf("Expected 2 attempts of the workflow run, got %d", len(attempts))
+		}
+
+		if record := attempts[1]; record == nil || record.Status != "completed" || record.Conclusion != "failure" {
+			t.Errorf("Expected first attempt to be failed, got %+v", record)
+		}
+
+		if record := attempts[2]; record == nil || record.Status != "completed" || record.Conclusion != "success" {
+			t.Errorf("Expected second attempt to be successful, got %+v", record)
+		}
+	})
+
+	t.Run("workflow job", func(t *testing.T) {
+		job := func(status string) *github.WorkflowJobEvent {
+			return &github.WorkflowJobEvent{
+				Repo: testRepo,
+				WorkflowJob: &github.WorkflowJob{
+					ID:           github.Ptr(int64(4)),
+					RunID:        github.Ptr(int64(1)),
+					RunAttempt:   github.Ptr(int64(1)),
+					Name:         github.Ptr("build"),
+					WorkflowName: github.Ptr("CI"),
+					Status:       github.Ptr(status),
+					Conclusion:   github.Ptr("success"),
+					CreatedAt:    &github.Timestamp{Time: now.Add(-time.Hour)},
+					StartedAt:    &github.Timestamp{Time: now.Add(-time.Hour)},
+					CompletedAt:  &github.Timestamp{Time: now},
+					Steps: []*github.TaskStep{
+						{
+							Number:      github.Ptr(int64(1)),
+							Name:        github.Ptr("checkout"),
+							Status:      github.Ptr(status),
+							StartedAt:   &github.Timestamp{Time: now.Add(-time.Hour)},
+							CompletedAt: &github.Timestamp{Time: now},
+						},
+					},
+				},
+			}
+		}
+
+		for _, event := range []*github.WorkflowJobEvent{
+			job("queued"),
+			job("completed"),
+			job("in_progress"),
+			job("completed"),
+		} {
+			if err := s.StoreWorkflowJobEvent(event); err != nil {
+				t.Fatalf("Failed to store workflow job: %v", err)
+			}
+		}
+
+		records, err := s.GetWorkflowJobs(2 * time.Hour)
+
+		if err != nil {
+			t.Fatalf("Failed to get workflow jobs: %v", err)
+		}
+
+		if len(records) != 1 || records[0].Status != "completed" {
+			t.Errorf("Expected a single completed workflow job, got %+v", records)
+		}
+
+		steps, err := s.GetWorkflowJobSteps(2 * time.Hour)
+
+		if err != nil {
+			t.Fatalf("Failed to get workflow job steps: %v", err)
+		}
+
+		if len(steps) != 1 || steps[0].Status != "completed" {
+			t.Errorf("Expected a single completed workflow job step, got %+v", steps)
+		}
+
+		if count := testTotalCount(t, s, "workflow_job"); count != 1 {
+			t.Errorf("Expected workflow job to be counted once, got %d", count)
+		}
+
+		queues, err := s.GetDurationTotals("workflow_job_queue")
+
+		if err != nil {
+			t.Fatalf("Failed to get duration totals: %v", err)
+		}
+
+		if len(queues) != 1 || queues[0].Count != 1 || queues[0].Bucket != 5 {
+			t.Errorf("Expected queue time to be counted once, got %+v", queues)
+		}
+
+		durations, err := s.GetDurationTotals("workflow_job_step")
+
+		if err != nil {
+			t.Fatalf("Failed to get duration totals: %v", err)
+		}
+
+		if len(durations) != 1 || durations[0].Count != 1 || durations[0].Bucket != 3600 {
+			t.Errorf("Expected step duration to be counted once, got %+v", durations)
+		}
+	})
+
+	t.Run("check run", func(t *testing.T) {
+		for _, status := range []string{"completed", "in_progress"} {
+			if err := s.StoreCheckRunEvent(&github.CheckRunEvent{
+				Repo: testRepo,
+				CheckRun: &github.CheckRun{
+					ID:        github.Ptr(int64(5)),
+					Name:      github.Ptr("lint"),
+					Status:    github.Ptr(status),
+					StartedAt: &github.Timestamp{Time: now},
+				},
+			}); err != nil {
+				t.Fatalf("Failed to store check run: %v", err)
+			}
+		}
+
+		records, err := s.GetCheckRuns(time.Hour)
+
+		if err != nil {
+			t.Fatalf("Failed to get check runs: %v", err)
+		}
+
+		if len(records) != 1 || records[0].Status != "completed" {
+			t.Errorf("Expected a single completed check run, got %+v", records)
+		}
+	})
+
+	t.Run("pull request review", func(t *testing.T) {
+		review := func(user string, submittedAt time.Time) *github.PullRequestReviewEvent {
+			return &github.PullRequestReviewEvent{
+				Repo: testRepo,
+				PullRequest: &github.PullRequest{
+					ID:        github.Ptr(int64(6)),
+					Number:    github.Ptr(7),
+					State:     github.Ptr("open"),
+					User:      &github.User{Login: github.Ptr("author")},
+					CreatedAt: &github.Timestamp{Time: now.Add(-time.Hour)},
+					UpdatedAt: &github.Timestamp{Time: now},
+				},
+				Review: &github.PullRequestReview{
+					User:        &github.User{Login: github.Ptr(user)},
+					SubmittedAt: &github.Timestamp{Time: submittedAt},
+				},
+			}
+		}
+
+		for _, event := range []*github.PullRequestReviewEvent{
+			review("author", now.Add(-50*time.Minute)),
+			review("reviewer", now.Add(-20*time.Minute)),
+			review("reviewer", now.Add(-30*time.Minute)),
+			review("reviewer", now.Add(-10*time.Minute)),
+		} {
+			if err := s.StorePullRequestReviewEvent(event); err != nil {
+				t.Fatalf("Failed to store pull request review: %v", err)
+			}
+		}
+
+		records, err := s.GetPullRequests(time.Hour)
+
+		if err != nil {
+			t.Fatalf("Failed to get pull requests: %v", err)
+		}
+
+		if len(records) != 1 || records[0].FirstReviewAt != now.Add(-30*time.Minute).Unix() {
+			t.Errorf("Expected the earliest review by another user, got %+v", records)
+		}
+
+		reviews, err := s.GetDurationTotals("pull_request_first_review")
+
+		if err != nil {
+			t.Fatalf("Failed to get duration totals: %v", err)
+		}
+
+		if len(reviews) != 1 || reviews[0].Count != 1 || reviews[0].Duration != 2400 {
+			t.Errorf("Expected first review to be counted once, got %+v", reviews)
+		}
+	})
+
+	t.Run("pull request merge", func(t *testing.T) {
+		pr := func(state string, updatedAt time.Time) *github.PullRequestEvent {
+			event := &github.PullRequestEvent{
+				Repo: testRepo,
+				PullRequest: &github.PullRequest{
+					ID:        github.Ptr(int64(9)),
+					Number:    github.Ptr(10),
+					State:     github.Ptr(state),
+					User:      &github.User{Login: github.Ptr("author")},
+					CreatedAt: &github.Timestamp{Time: now.Add(-2 * time.Hour)},
+					UpdatedAt: &github.Timestamp{Time: updatedAt},
+				},
+			}
+
+			if state == "closed" {
+				event.PullRequest.Merged = github.Ptr(true)
+				event.PullRequest.MergedAt = &github.Timestamp{Time: now}
+			}
+
+			return event
+		}
+
+		for _, event := range []*github.PullRequestEvent{
+			pr("open", now.Add(-time.Hour)),
+			pr("closed", now),
+			pr("open", now.Add(-30*time.Minute)),
+			pr("closed", now),
+		} {
+			if err := s.StorePullRequestEvent(event); err != nil {
+				t.Fatalf("Failed to store pull request: %v", err)
+			}
+		}
+
+		merges, err := s.GetDurationTotals("pull_request_merge")
+
+		if err != nil {
+			t.Fatalf("Failed to get duration totals: %v", err)
+		}
+
+		if len(merges) != 1 || merges[0].Count != 1 || merges[0].Duration != 7200 {
+			t.Errorf("Expected merge to be counted once, got %+v", merges)
+		}
+	})
+
+	t.Run("deployment status", func(t *testing.T) {
+		status := func(state string, createdAt time.Time) *github.DeploymentStatusEvent {
+			return &github.DeploymentStatusEvent{
+				Repo: testRepo,
+				Deployment: &github.Deployment{
+					ID:          github.Ptr(int64(8)),
+					Environment: github.Ptr("production"),
+					CreatedAt:   &github.Timestamp{Time: now.Add(-time.Hour)},
+					UpdatedAt:   &github.Timestamp{Time: now},
+				},
+				DeploymentStatus: &github.DeploymentStatus{
+					State:     github.Ptr(state),
+					CreatedAt: &github.Timestamp{Time: createdAt},
+				},
+			}
+		}
+
+		for _, event := range []*github.DeploymentStatusEvent{
+			status("success", now.Add(-10*time.Minute)),
+			status("in_progress", now.Add(-20*time.Minute)),
+			status("inactive", now),
+		} {
+			if err := s.StoreDeploymentStatusEvent(event); err != nil {
+				t.Fatalf("Failed to store deployment status: %v", err)
+			}
+		}
+
+		records, err := s.GetDeployments(2 * time.Hour)
+
+		if err != nil {
+			t.Fatalf("Failed to get deployments: %v", err)
+		}
+
+		if len(records) != 1 || records[0].Status != "success" {
+			t.Errorf("Expected a single successful deployment, got %+v", records)
+		}
+	})
+
+	t.Run("deployment durations", func(t *testing.T) {
+		if err := s.StoreWorkflowRunEvent(&github.WorkflowRunEvent{
+			Repo: testRepo,
+			WorkflowRun: &github.WorkflowRun{
+				ID:         github.Ptr(int64(11)),
+				WorkflowID: github.Ptr(int64(2)),
+				RunNumber:  github.Ptr(12),
+				RunAttempt: github.Ptr(1),
+				HeadSHA:    github.Ptr("abc"),
+				Status:     github.Ptr("completed"),
+				HeadCommit: &github.HeadCommit{
+					Timestamp: &github.Timestamp{Time: now.Add(-2 * time.Hour)},
+				},
+				CreatedAt: &github.Timestamp{Time: now.Add(-time.Hour)},
+				UpdatedAt: &github.Timestamp{Time: now},
+			},
+		}); err != nil {
+			t.Fatalf("Failed to store workflow run: %v", err)
+		}
+
+		status := func(id int64, sha, state string, createdAt time.Time) *github.DeploymentStatusEvent {
+			return &github.DeploymentStatusEvent{
+				Repo: testRepo,
+				Deployment: &github.Deployment{
+					ID:          github.Ptr(id),
+					SHA:         github.Ptr(sha),
+					Environment: github.Ptr("staging"),
+					CreatedAt:   &github.Timestamp{Time: now.Add(-time.Hour)},
+					UpdatedAt:   &github.Timestamp{Time: now},
+				},
+				DeploymentStatus: &github.DeploymentStatus{
+					State:     github.Ptr(state),
+					CreatedAt: &github.Timestamp{Time: createdAt},
+				},
+			}
+		}
+
+		for _, event := range []*github.DeploymentStatusEvent{
+			status(13, "def", "failure", now.Add(-40*time.Minute)),
+			status(14, "abc", "success", now.Add(-10*time.Minute)),
+			status(14, "abc", "success", now.Add(-5*time.Minute)),
+		} {
+			if err := s.StoreDeploymentStatusEvent(event); err != nil {
+				t.Fatalf("Failed to store deployment status: %v", err)
+			}
+		}
+
+		for kind, want := range map[string]int64{
+			"deployment_lead_time": 6600,
+			"deployment_restore":   1800,
+		} {
+			records, err := s.GetDurationTotals(kind)
+
+			if err != nil {
+				t.Fatalf("Failed to get duration totals: %v", err)
+			}
+
+			var count, duration int64
+
+			for _, record := range records {
+				if record.Values()[2] == "staging" {
+					count += record.Count
+					duration += record.Duration
+				}
+			}
+
+			if count != 1 || duration != want {
+				t.Errorf("Expected %s of %d to be counted once, got %d with %d", kind, want, count, duration)
+			}
+		}
+	})
+
+	t.Run("webhook delivery", func(t *testing.T) {
+		record := &WebhookDelivery{
+			Delivery:  "delivery",
+			Event:     "workflow_run",
+			CreatedAt: now.Unix(),
+		}
+
+		for _, want := range []bool{true, false} {
+			reserved, err := s.ReserveWebhookDelivery(record)
+
+			if err != nil {
+				t.Fatalf("Failed to reserve delivery: %v", err)
+			}
+
+			if reserved != want {
+				t.Errorf("Expected reservation to be %v, got %v", want, reserved)
+			}
+		}
+
+		if err := s.ReleaseWebhookDelivery(record.Delivery); err != nil {
+			t.Fatalf("Failed to release delivery: %v", err)
+		}
+
+		if reserved, err := s.ReserveWebhookDelivery(record); err != nil || !reserved {
+			t.Errorf("Expected released delivery to be reserved again, got %v with %v", reserved, err)
+		}
+
+		if err := s.PruneWebhookDeliveries(-time.Minute); err != nil {
+			t.Fatalf("Failed to prune deliveries: %v", err)
+		}
+
+		if reserved, err := s.ReserveWebhookDelivery(record); err != nil || !reserved {
+			t.Errorf("Expected pruned delivery to be reserved again, got %v with %v", reserved, err)
+		}
+	})
+
+	t.Run("deleted workflow run", func(t *testing.T) {
+		if err := s.StoreWorkflowRunEvent(&github.WorkflowRunEvent{
+			Repo: testRepo,
+			WorkflowRun: &github.WorkflowRun{
+				ID:         github.Ptr(int64(5)),
+				WorkflowID: github.Ptr(int64(2)),
+				RunNumber:  github.Ptr(5),
+				RunAttempt: github.Ptr(1),
+				Name:       github.Ptr("CI"),
+				Status:     github.Ptr("in_progress"),
+				CreatedAt:  &github.Timestamp{Time: now.Add(-time.Hour)},
+				UpdatedAt:  &github.Timestamp{Time: now.Add(-time.Hour)},
+			},
+		}); err != nil {
+			t.Fatalf("Failed to store workflow run: %v", err)
+		}
+
+		records, err := s.GetWorkflowRuns(2 * time.Hour)
+
+		if err != nil {
+			t.Fatalf("Failed to get workflow runs: %v", err)
+		}
+
+		for _, record := range records {
+			if record.Identifier != 5 {
+				continue
+			}
+
+			if err := s.DeleteWorkflowRun(record); err != nil {
+				t.Fatalf("Failed to delete workflow run: %v", err)
+			}
+		}
+
+		records, err = s.GetWorkflowRuns(2 * time.Hour)
+
+		if err != nil {
+			t.Fatalf("Failed to get workflow runs: %v", err)
+		}
+
+		kept := make(map[int64]bool, len(records))
+
+		for _, record := range records {
+			kept[record.Identifier] = true
+		}
+
+		if kept[5] || !kept[1] {
+			t.Errorf("Expected only the deleted workflow run to be removed, got %v", kept)
+		}
+	})
+
+	t.Run("deleted workflow job", func(t *testing.T) {
+		if err := s.StoreWorkflowJobEvent(&github.WorkflowJobEvent{
+			Repo: testRepo,
+			WorkflowJob: &github.WorkflowJob{
+				ID:           github.Ptr(int64(6)),
+				RunID:        github.Ptr(int64(5)),
+				RunAttempt:   github.Ptr(int64(1)),
+				Name:         github.Ptr("test"),
+				WorkflowName: github.Ptr("CI"),
+				Status:       github.Ptr("in_progress"),
+				CreatedAt:    &github.Timestamp{Time: now.Add(-time.Hour)},
+				StartedAt:    &github.Timestamp{Time: now.Add(-time.Hour)},
+				Steps: []*github.TaskStep{
+					{
+						Number:    github.Ptr(int64(1)),
+						Name:      github.Ptr("checkout"),
+						Status:    github.Ptr("in_progress"),
+						StartedAt: &github.Timestamp{Time: now.Add(-time.Hour)},
+					},
+				},
+			},
+		}); err != nil {
+			t.Fatalf("Failed to store workflow job: %v", err)
+		}
+
+		records, err := s.GetWorkflowJobs(2 * time.Hour)
+
+		if err != nil {
+			t.Fatalf("Failed to get workflow jobs: %v", err)
+		}
+
+		for _, record := range records {
+			if record.Identifier != 6 {
+				continue
+			}
+
+			if err := s.DeleteWorkflowJob(record); err != nil {
+				t.Fatalf("Failed to delete workflow job: %v", err)
+			}
+		}
+
+		records, err = s.GetWorkflowJobs(2 * time.Hour)
+
+		if err != nil {
+			t.Fatalf("Failed to get workflow jobs: %v", err)
+		}
+
+		kept := make(map[int64]bool, len(records))
+
+		for _, record := range records {
+			kept[record.Identifier] = true
+		}
+
+		if kept[6] || !kept[4] {
+			t.Errorf("Expected only the deleted workflow job to be removed, got %v", kept)
+		}
+
+		steps, err := s.GetWorkflowJobSteps(2 * time.Hour)
+
+		if err != nil {
+			t.Fatalf("Failed to get workflow job steps: %v", err)
+		}
+
+		for _, step := range steps {
+			if step.JobID == 6 {
+				t.Errorf("Expected steps of the deleted workflow job to be removed, got %+v", step)
+			}
+		}
+	})
+}
+
+func testTotalCount(t *testing.T, s Store, kind string) int64 {
+	t.Helper()
+
+	records, err := s.GetWorkflowTotals()
+
+	if err != nil {
+		t.Fatalf("Failed to get workflow totals: %v", err)
+	}
+
+	var count int64
+
+	for _, record := range records {
+		if record.Kind == kind {
+			count += record.Count
+		}
+	}
+
+	return count
+}
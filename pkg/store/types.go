@@ -1,6 +1,7 @@
 package store
 
 import (
+	"encoding/json"
 	"strconv"
 )
 
@@ -14,6 +15,7 @@ type WorkflowRun struct {
 	Name       string `db:"name"`
 	Title      string `db:"title"`
 	Status     string `db:"status"`
+	Conclusion string `db:"conclusion"`
 	Branch     string `db:"branch"`
 	SHA        string `db:"sha"`
 	Number     int    `db:"number"`
@@ -23,6 +25,20 @@ type WorkflowRun struct {
 	CreatedAt  int64  `db:"created_at"`
 	UpdatedAt  int64  `db:"updated_at"`
 	StartedAt  int64  `db:"started_at"`
+	CommitAt   int64  `db:"commit_at"`
+	HTMLURL    string `db:"html_url"`
+
+	WorkflowName string `db:"-"`
+	WorkflowPath string `db:"-"`
+}
+
+// State returns the conclusion for completed runs, otherwise the status.
+func (r *WorkflowRun) State() string {
+	if r.Conclusion != "" {
+		return r.Conclusion
+	}
+
+	return r.Status
 }
 
 // ByLabel returns values by the defined list of labels.
@@ -41,7 +57,9 @@ func (r *WorkflowRun) ByLabel(label string) string {
 	case "title":
 		return r.Name
 	case "status":
-		return r.Status
+		return r.State()
+	case "conclusion":
+		return r.Conclusion
 	case "branch":
 		return r.Branch
 	case "sha":
@@ -54,11 +72,36 @@ func (r *WorkflowRun) ByLabel(label string) string {
 		return strconv.FormatInt(r.Identifier, 10)
 	case "actor":
 		return r.Actor
+	case "workflow_name":
+		return r.WorkflowName
+	case "workflow_path":
+		return r.WorkflowPath
 	}
 
 	return ""
 }
 
+// Workflow defines the name and path of a workflow resolved from the API.
+type Workflow struct {
+	Owner string `db:"owner"`
+	Repo  string `db:"repo"`
+
+	Identifier int64  `db:"identifier"`
+	Name       string `db:"name"`
+	Path       string `db:"path"`
+	State      string `db:"state"`
+	UpdatedAt  int64  `db:"updated_at"`
+}
+
+// Repository defines the default branch of a repository.
+type Repository struct {
+	Owner string `db:"owner"`
+	Repo  string `db:"repo"`
+
+	DefaultBranch string `db:"default_branch"`
+	UpdatedAt     int64  `db:"updated_at"`
+}
+
 // WorkflowJob defines the type returned by GitHub.
 type WorkflowJob struct {
 	Owner string `db:"owner"`
@@ -81,6 +124,7 @@ type WorkflowJob struct {
 	RunnerGroupID   int64  `db:"runner_group_id"`
 	RunnerGroupName string `db:"runner_group_name"`
 	WorkflowName    string `db:"workflow_name"`
+	HTMLURL         string `db:"html_url"`
 }
 
 // ByLabel returns values by the defined list of labels.
@@ -126,3 +170,203 @@ func (r *WorkflowJob) ByLabel(label string) string {
 
 	return ""
 }
+
+// WorkflowJobStep defines a single step of a workflow job.
+type WorkflowJobStep struct {
+	Owner        string `db:"owner"`
+	Repo         string `db:"repo"`
+	WorkflowName string `db:"workflow_name"`
+	JobName      string `db:"job_name"`
+	JobID        int64  `db:"job_id"`
+	Number       int64  `db:"number"`
+	Name         string `db:"name"`
+	Status       string `db:"status"`
+	Conclusion   string `db:"conclusion"`
+	CreatedAt    int64  `db:"created_at"`
+	StartedAt    int64  `db:"started_at"`
+	CompletedAt  int64  `db:"completed_at"`
+}
+
+// WorkflowJobFlake defines the number of flaky runs of a workflow job.
+type WorkflowJobFlake struct {
+	Owner        string `db:"owner"`
+	Repo         string `db:"repo"`
+	WorkflowName string `db:"workflow_name"`
+	Name         string `db:"name"`
+	Runs         int64  `db:"runs"`
+	Flaky        int64  `db:"flaky"`
+}
+
+// CheckSuite defines the type returned by GitHub.
+type CheckSuite struct {
+	Owner string `db:"owner"`
+	Repo  string `db:"repo"`
+
+	Identifier int64  `db:"identifier"`
+	App        string `db:"app"`
+	Status     string `db:"status"`
+	Conclusion string `db:"conclusion"`
+	Branch     string `db:"branch"`
+	SHA        string `db:"sha"`
+	CreatedAt  int64  `db:"created_at"`
+	UpdatedAt  int64  `db:"updated_at"`
+}
+
+// ByLabel returns values by the defined list of labels.
+func (r *CheckSuite) ByLabel(label string) string {
+	switch label {
+	case "owner":
+		return r.Owner
+	case "repo":
+		return r.Repo
+	case "app":
+		return r.App
+	case "status":
+		return r.Status
+	case "conclusion":
+		return r.Conclusion
+	case "branch":
+		return r.Branch
+	case "sha":
+		return r.SHA
+	case "identifier":
+		return strconv.FormatInt(r.Identifier, 10)
+	case "suite_id":
+		return strconv.FormatInt(r.Identifier, 10)
+	}
+
+	return ""
+}
+
+// CheckRun defines the type returned by GitHub.
+type CheckRun struct {
+	Owner string `db:"owner"`
+	Repo  string `db:"repo"`
+
+	Identifier  int64  `db:"identifier"`
+	SuiteID     int64  `db:"suite_id"`
+	Name        string `db:"name"`
+	App         string `db:"app"`
+	Status      string `db:"status"`
+	Conclusion  string `db:"conclusion"`
+	Branch      string `db:"branch"`
+	SHA         string `db:"sha"`
+	StartedAt   int64  `db:"started_at"`
+	CompletedAt int64  `db:"completed_at"`
+}
+
+// ByLabel returns values by the defined list of labels.
+func (r *CheckRun) ByLabel(label string) string {
+	switch label {
+	case "owner":
+		return r.Owner
+	case "repo":
+		return r.Repo
+	case "name":
+		return r.Name
+	case "app":
+		return r.App
+	case "status":
+		return r.Status
+	case "conclusion":
+		return r.Conclusion
+	case "branch":
+		return r.Branch
+	case "sha":
+		return r.SHA
+	case "identifier":
+		return strconv.FormatInt(r.Identifier, 10)
+	case "suite_id":
+		return strconv.FormatInt(r.SuiteID, 10)
+	}
+
+	return ""
+}
+
+// PullRequest defines the type returned by GitHub.
+type PullRequest struct {
+	Owner string `db:"owner"`
+	Repo  string `db:"repo"`
+
+	Number        int    `db:"number"`
+	Identifier    int64  `db:"identifier"`
+	Base          string `db:"base"`
+	Head          string `db:"head"`
+	Author        string `db:"author"`
+	State         string `db:"state"`
+	Draft         bool   `db:"draft"`
+	CreatedAt     int64  `db:"created_at"`
+	UpdatedAt     int64  `db:"updated_at"`
+	ClosedAt      int64  `db:"closed_at"`
+	MergedAt      int64  `db:"merged_at"`
+	FirstReviewAt int64  `db:"first_review_at"`
+}
+
+// Deployment defines the type returned by GitHub.
+type Deployment struct {
+	Owner string `db:"owner"`
+	Repo  string `db:"repo"`
+
+	Identifier  int64  `db:"identifier"`
+	Environment string `db:"environment"`
+	Ref         string `db:"ref"`
+	SHA         string `db:"sha"`
+	Task        string `db:"task"`
+	Creator     string `db:"creator"`
+	Status      string `db:"status"`
+	CreatedAt   int64  `db:"created_at"`
+	UpdatedAt   int64  `db:"updated_at"`
+	StatusAt    int64  `db:"status_at"`
+}
+
+// WebhookDelivery defines a processed webhook delivery.
+type WebhookDelivery struct {
+	Delivery  string `db:"delivery"`
+	Event     string `db:"event"`
+	CreatedAt int64  `db:"created_at"`
+}
+
+// WorkflowTotal defines a persistent counter of finished workflow runs or jobs.
+type WorkflowTotal struct {
+	Kind       string `db:"kind"`
+	Owner      string `db:"owner"`
+	Repo       string `db:"repo"`
+	Workflow   string `db:"workflow"`
+	Conclusion string `db:"conclusion"`
+	Bucket     int64  `db:"le"`
+	Count      int64  `db:"count"`
+	Duration   int64  `db:"duration"`
+}
+
+// DurationTotal defines a persistent histogram bucket of observed durations,
+// the label values are stored encoded as a JSON list.
+type DurationTotal struct {
+	Kind     string `db:"kind"`
+	Labels   string `db:"labels"`
+	Bucket   int64  `db:"le"`
+	Count    int64  `db:"count"`
+	Duration int64  `db:"duration"`
+}
+
+// Values decodes the label values of the bucket.
+func (r *DurationTotal) Values() []string {
+	result := make([]string, 0)
+	_ = json.Unmarshal([]byte(r.Labels), &result)
+
+	return result
+}
+
+// WorkflowSummary defines duration percentiles and outcomes of finished workflow runs or jobs.
+type WorkflowSummary struct {
+	Owner      string  `db:"owner"`
+	Repo       string  `db:"repo"`
+	Workflow   string  `db:"workflow"`
+	Name       string  `db:"name"`
+	Count      int64   `db:"count"`
+	Successful int64   `db:"successful"`
+	Sum        float64 `db:"sum"`
+	P50        float64 `db:"p50"`
+	P90        float64 `db:"p90"`
+	P95        float64 `db:"p95"`
+	P99        float64 `db:"p99"`
+}